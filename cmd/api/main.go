@@ -2,6 +2,8 @@ package main
 
 import (
 	"log"
+	"net/http"
+	"time"
 
 	"mini-project-ostore/internal/config"
 	"mini-project-ostore/internal/handler"
@@ -9,6 +11,8 @@ import (
 	"mini-project-ostore/internal/repository"
 	"mini-project-ostore/internal/usecase"
 	"mini-project-ostore/pkg/database"
+	"mini-project-ostore/pkg/oauth"
+	"mini-project-ostore/pkg/sms"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,7 +28,9 @@ func main() {
 		log.Fatal("Database connection failed:", err)
 	}
 
-	database.Migrate(db)
+	if err := database.Migrate(db); err != nil {
+		log.Fatal("Database migration failed:", err)
+	}
 
 	// ------------------------
 	// INITIALIZE REPOSITORIES
@@ -35,64 +41,228 @@ func main() {
 	categoryRepo := repository.NewCategoryRepository(db)
 	productRepo := repository.NewProductRepository(db)
 	transactionRepo := repository.NewTransactionRepository(db)
+	otpRepo := repository.NewOTPRepository(db)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(db)
+	userIdentityRepo := repository.NewUserIdentityRepository(db)
+	recoveryCodeRepo := repository.NewRecoveryCodeRepository(db)
+	twoFactorChallengeRepo := repository.NewTwoFactorChallengeRepository(db)
+	storeModerationRepo := repository.NewStoreModerationRepository(db)
+	storeStaffRepo := repository.NewStoreStaffRepository(db)
+	storeFollowerRepo := repository.NewStoreFollowerRepository(db)
+	reviewRepo := repository.NewReviewRepository(db)
+	storeSettingsRepo := repository.NewStoreSettingsRepository(db)
+	productVariantRepo := repository.NewProductVariantRepository(db)
+	productImportRepo := repository.NewProductImportRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	discountCampaignRepo := repository.NewDiscountCampaignRepository(db)
+	voucherRepo := repository.NewVoucherRepository(db)
+	checkoutQuoteRepo := repository.NewCheckoutQuoteRepository(db)
+	notificationRepo := repository.NewNotificationRepository(db)
+	transactor := repository.NewTransactor(db)
+
+	// Import jobs run in-process, so those still running when the server stopped won't finish
+	if err := productImportRepo.FailUnfinished("interrupted by a server restart"); err != nil {
+		log.Println("Failed to clean up product import jobs:", err)
+	}
 
 	// Region API repositories
 	provinceAPIRepo := repository.NewProvinceAPIRepository()
 	cityAPIRepo := repository.NewCityAPIRepository()
 	subdistrictAPIRepo := repository.NewSubdistrictAPIRepository()
 
+	// SMS gateway; the fake sender only keeps messages in memory, so production needs a real one
+	var smsSender sms.SMSSender
+	switch cfg.SMS.Provider {
+	case "http":
+		if cfg.SMS.URL == "" {
+			log.Fatal("SMS gateway URL is not configured")
+		}
+		smsSender = sms.NewHTTPSender(cfg.SMS.URL, cfg.SMS.APIKey, cfg.SMS.From)
+	case "fake":
+		if cfg.Server.IsProduction() {
+			log.Fatal("The fake SMS sender cannot be used in production; configure an SMS gateway")
+		}
+		smsSender = sms.NewFakeSender()
+	default:
+		log.Fatalf("Unknown SMS provider %q", cfg.SMS.Provider)
+	}
+
+	// Social login providers
+	var oauthProviders []oauth.Provider
+	if cfg.OAuth.Google.ClientID != "" {
+		oauthProviders = append(oauthProviders, oauth.NewGoogleProvider(
+			cfg.OAuth.Google.ClientID, cfg.OAuth.Google.ClientSecret, cfg.OAuth.Google.RedirectURL, cfg.OAuth.Google.LinkRedirectURL))
+	}
+	var fakeOIDC *oauth.FakeOIDCServer
+	if cfg.OAuth.FakeEnabled {
+		// The fake provider signs anyone in as any email, so it is for development only
+		if cfg.Server.IsProduction() {
+			log.Fatal("The fake OIDC provider cannot be used in production; disable it")
+		}
+		fakeOIDC = oauth.NewFakeOIDCServer()
+		base := "http://localhost:" + cfg.Server.Port
+		oauthProviders = append(oauthProviders, oauth.NewOIDCProvider(
+			fakeOIDC.Config("fake", base+"/dev/oidc", base+"/auth/oauth/fake/callback", base+"/auth/oauth/fake/link/callback")))
+	}
+
+	// Product search engine: MySQL FULLTEXT by default, or an in-process index
+	var productSearcher repository.ProductSearcher
+	switch cfg.Search.Engine {
+	case "memory":
+		productSearcher, err = repository.NewMemoryProductSearcher(db)
+		if err != nil {
+			log.Fatal("Search index build failed:", err)
+		}
+	default:
+		productSearcher = repository.NewMySQLProductSearcher(db)
+	}
+
 	// ------------------------
 	// INITIALIZE USECASES
 	// ------------------------
-	userUC := usecase.NewUserUseCase(userRepo, storeRepo)
-	authUC := usecase.NewAuthUseCase(userRepo, storeRepo)
-	storeUC := usecase.NewStoreUseCase(storeRepo, userRepo)
+	userUC := usecase.NewUserUseCase(userRepo, storeRepo, transactionRepo)
+	authUC := usecase.NewAuthUseCase(userRepo, storeRepo, loginAttemptRepo)
+	storeUC := usecase.NewStoreUseCase(storeRepo, userRepo, cfg.Store.MaxStoresPerUser)
 	addressUC := usecase.NewAddressUseCase(addressRepo, userRepo)
 	categoryUC := usecase.NewCategoryUseCase(categoryRepo)
-	productUC := usecase.NewProductUseCase(productRepo, storeRepo, userRepo, categoryRepo)
-	transactionUC := usecase.NewTransactionUseCase(transactionRepo, productRepo, userRepo, addressRepo)
+	productUC := usecase.NewProductUseCase(productRepo, storeRepo, userRepo, categoryRepo, storeStaffRepo, productVariantRepo, discountCampaignRepo, productSearcher, transactor)
+	transactionUC := usecase.NewTransactionUseCase(transactionRepo, productRepo, userRepo, addressRepo, storeRepo, storeStaffRepo, storeSettingsRepo, productVariantRepo, discountCampaignRepo, voucherRepo, checkoutQuoteRepo, notificationRepo, transactor, cfg.Checkout.Couriers, cfg.Checkout.TaxRate)
 	regionUC := usecase.NewRegionUseCase(provinceAPIRepo, cityAPIRepo, subdistrictAPIRepo)
+	otpUC := usecase.NewOTPUseCase(otpRepo, userRepo, smsSender)
+	oauthUC := usecase.NewOAuthUseCase(userRepo, userIdentityRepo, oauthProviders...)
+	twoFactorUC := usecase.NewTwoFactorUseCase(userRepo, recoveryCodeRepo, twoFactorChallengeRepo)
+	storeModerationUC := usecase.NewStoreModerationUseCase(storeRepo, storeModerationRepo)
+	storeStaffUC := usecase.NewStoreStaffUseCase(storeStaffRepo, storeRepo, userRepo)
+	storefrontUC := usecase.NewStorefrontUseCase(storeRepo, productRepo, storeFollowerRepo, reviewRepo, storeSettingsRepo, categoryRepo, discountCampaignRepo, productSearcher)
+	storeSettingsUC := usecase.NewStoreSettingsUseCase(storeSettingsRepo, storeRepo)
+	productImportUC := usecase.NewProductImportUseCase(productImportRepo, productRepo, storeRepo, storeStaffRepo, productUC)
+	inventoryUC := usecase.NewInventoryUseCase(stockMovementRepo, productRepo, productVariantRepo, storeRepo, storeStaffRepo, storeSettingsRepo, notificationRepo)
+	notificationUC := usecase.NewNotificationUseCase(notificationRepo)
+	discountUC := usecase.NewDiscountUseCase(discountCampaignRepo, productRepo, productVariantRepo, storeRepo, storeStaffRepo)
+	voucherUC := usecase.NewVoucherUseCase(voucherRepo, productRepo, productVariantRepo, storeRepo, storeStaffRepo, storeSettingsRepo, discountCampaignRepo)
+	checkoutUC := usecase.NewCheckoutUseCase(checkoutQuoteRepo, productRepo, productVariantRepo, addressRepo, storeRepo, storeSettingsRepo, discountCampaignRepo, voucherRepo, cfg.Checkout.Couriers, cfg.Checkout.TaxRate, cfg.Checkout.QuoteTTL)
+
+	// Quotes that expired without an order are of no further use
+	go func() {
+		for range time.Tick(cfg.Checkout.QuoteCleanupInterval) {
+			if _, err := checkoutUC.DeleteExpiredQuotes(); err != nil {
+				log.Println("Failed to delete expired checkout quotes:", err)
+			}
+		}
+	}()
 
 	// ------------------------
 	// INITIALIZE HANDLERS
 	// ------------------------
 	userHandler := handler.NewUserHandler(userUC)
-	authHandler := handler.NewAuthHandler(authUC, userUC)
+	authHandler := handler.NewAuthHandler(authUC, userUC, otpUC)
 	storeHandler := handler.NewStoreHandler(storeUC)
 	addressHandler := handler.NewAddressHandler(addressUC)
 	categoryHandler := handler.NewCategoryHandler(categoryUC)
 	productHandler := handler.NewProductHandler(productUC)
 	transactionHandler := handler.NewTransactionHandler(transactionUC)
 	regionHandler := handler.NewRegionHandler(regionUC)
+	oauthHandler := handler.NewOAuthHandler(oauthUC)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorUC)
+	storeModerationHandler := handler.NewStoreModerationHandler(storeModerationUC)
+	storeStaffHandler := handler.NewStoreStaffHandler(storeStaffUC)
+	storefrontHandler := handler.NewStorefrontHandler(storefrontUC)
+	storeSettingsHandler := handler.NewStoreSettingsHandler(storeSettingsUC)
+	productImportHandler := handler.NewProductImportHandler(productImportUC)
+	inventoryHandler := handler.NewInventoryHandler(inventoryUC)
+	notificationHandler := handler.NewNotificationHandler(notificationUC)
+	discountHandler := handler.NewDiscountHandler(discountUC)
+	voucherHandler := handler.NewVoucherHandler(voucherUC)
+	checkoutHandler := handler.NewCheckoutHandler(checkoutUC)
 
 	// ------------------------
 	// MIDDLEWARE
 	// ------------------------
 	authMiddleware := middleware.NewAuthMiddleware(userRepo)
+	storeMiddleware := middleware.NewStoreMiddleware(storeRepo, storeStaffRepo)
+	rateLimiter := middleware.NewRateLimitMiddleware(middleware.NewMemoryRateLimitStore())
+
+	authLimit := rateLimiter.Limit(middleware.RateLimitPolicy{
+		Name:   "auth",
+		Limit:  cfg.RateLimit.Auth.Limit,
+		Window: cfg.RateLimit.Auth.Window,
+		KeyBy:  middleware.RateLimitByIP,
+	})
+	catalogLimit := rateLimiter.Limit(middleware.RateLimitPolicy{
+		Name:   "catalog",
+		Limit:  cfg.RateLimit.Catalog.Limit,
+		Window: cfg.RateLimit.Catalog.Window,
+		KeyBy:  middleware.RateLimitByIP,
+	})
+	checkoutLimit := rateLimiter.Limit(middleware.RateLimitPolicy{
+		Name:   "checkout",
+		Limit:  cfg.RateLimit.Checkout.Limit,
+		Window: cfg.RateLimit.Checkout.Window,
+		KeyBy:  middleware.RateLimitByUser,
+	})
+	checkoutPreviewLimit := rateLimiter.Limit(middleware.RateLimitPolicy{
+		Name:   "checkout-preview",
+		Limit:  cfg.RateLimit.CheckoutPreview.Limit,
+		Window: cfg.RateLimit.CheckoutPreview.Window,
+		KeyBy:  middleware.RateLimitByUser,
+	})
+
+	idempotency := middleware.NewIdempotencyMiddleware(middleware.NewMemoryIdempotencyStore())
+	orderIdempotency := idempotency.Idempotent(middleware.IdempotencyPolicy{
+		Name: "order",
+		TTL:  cfg.Idempotency.TTL,
+	})
 
 	// ------------------------
 	// SETUP ROUTER
 	// ------------------------
 	r := gin.Default()
+	// Client IPs key the rate limits, so forwarded headers are only taken from known proxies
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatal("Invalid trusted proxies:", err)
+	}
 
 	// =========================================================
 	// 🔓 PUBLIC ROUTES
 	// =========================================================
-	r.POST("/auth/register", authHandler.Register)
-	r.POST("/auth/login", authHandler.Login)
+	authGroup := r.Group("/auth")
+	authGroup.Use(authLimit)
+	{
+		authGroup.POST("/register", authHandler.Register)
+		authGroup.POST("/login", authHandler.Login)
+		authGroup.POST("/otp/request", authHandler.RequestOTP)
+		authGroup.POST("/otp/verify", authHandler.VerifyOTP)
+		authGroup.GET("/oauth/:provider", oauthHandler.Redirect)
+		authGroup.GET("/oauth/:provider/callback", oauthHandler.Callback)
+		authGroup.GET("/oauth/:provider/link/callback", oauthHandler.LinkCallback)
+		authGroup.POST("/2fa/verify", twoFactorHandler.VerifyChallenge)
+	}
 
-	// Category (public)
-	r.GET("/category", categoryHandler.GetCategories)
-	r.GET("/category/:id", categoryHandler.GetCategoryByID)
+	if fakeOIDC != nil {
+		r.Any("/dev/oidc/*path", gin.WrapH(http.StripPrefix("/dev/oidc", fakeOIDC)))
+	}
 
-	// Product (public)
-	r.GET("/product", productHandler.GetProducts)
-	r.GET("/product/:id", productHandler.GetProductByID)
+	catalog := r.Group("")
+	catalog.Use(catalogLimit)
+	{
+		// Category (public)
+		catalog.GET("/category", categoryHandler.GetCategories)
+		catalog.GET("/category/tree", categoryHandler.GetCategoryTree)
+		catalog.GET("/category/:id", categoryHandler.GetCategoryByID)
+		catalog.GET("/category/:id/attributes", categoryHandler.GetCategoryAttributes)
+
+		// Product (public)
+		catalog.GET("/product", productHandler.GetProducts)
+		catalog.GET("/product/:id", productHandler.GetProductByID)
+
+		// Storefront (public, by slug or ID)
+		catalog.GET("/toko/:id_toko", storefrontHandler.GetStorefront)
 
-	// Region (public)
-	r.GET("/regions/provinces", regionHandler.GetProvinces)
-	r.GET("/regions/provinces/:provinceID/cities", regionHandler.GetCities)
-	r.GET("/regions/cities/:cityID/subdistricts", regionHandler.GetSubdistricts)
+		// Region (public)
+		catalog.GET("/regions/provinces", regionHandler.GetProvinces)
+		catalog.GET("/regions/provinces/:provinceID/cities", regionHandler.GetCities)
+		catalog.GET("/regions/cities/:cityID/subdistricts", regionHandler.GetSubdistricts)
+	}
 
 	// =========================================================
 	// 🔒 PROTECTED ROUTES (need login)
@@ -105,13 +275,35 @@ func main() {
 		{
 			userGroup.GET("", userHandler.GetUserProfile)
 			userGroup.PUT("", userHandler.UpdateUserProfile)
-			userGroup.GET("/products", productHandler.GetUserProducts)
+			userGroup.PATCH("", userHandler.UpdateUserProfile)
+			userGroup.GET("/products", storeMiddleware.ResolveStore(), productHandler.GetUserProducts)
+
+			// TWO-FACTOR AUTHENTICATION
+			userGroup.POST("/2fa/enroll", twoFactorHandler.Enroll)
+			userGroup.POST("/2fa/confirm", twoFactorHandler.Confirm)
+			userGroup.POST("/2fa/disable", twoFactorHandler.Disable)
+			userGroup.POST("/2fa/recovery-codes", twoFactorHandler.RegenerateRecoveryCodes)
+
+			// NOTIFICATIONS
+			userGroup.GET("/notifications", notificationHandler.GetNotifications)
+			userGroup.POST("/notifications/:id/read", notificationHandler.MarkNotificationRead)
+
+			// SOCIAL LOGIN
+			userGroup.GET("/oauth/:provider/link", oauthHandler.StartLink)
+
+			// STORE STAFF INVITATIONS
+			userGroup.GET("/store-invitations", storeStaffHandler.GetInvitations)
+			userGroup.POST("/store-invitations/:id/accept", storeStaffHandler.AcceptInvitation)
+			userGroup.POST("/store-invitations/:id/decline", storeStaffHandler.DeclineInvitation)
+			userGroup.GET("/store-memberships", storeStaffHandler.GetMemberships)
+			userGroup.GET("/following", storefrontHandler.GetFollowedStores)
 
 			// ADDRESS
 			userGroup.GET("/alamat", addressHandler.GetUserAddresses)
 			userGroup.GET("/alamat/:id", addressHandler.GetAddress)
 			userGroup.POST("/alamat", addressHandler.CreateAddress)
 			userGroup.PUT("/alamat/:id", addressHandler.UpdateAddress)
+			userGroup.PATCH("/alamat/:id", addressHandler.UpdateAddress)
 			userGroup.DELETE("/alamat/:id", addressHandler.DeleteAddress)
 		}
 
@@ -119,19 +311,70 @@ func main() {
 		storeGroup := protected.Group("/toko")
 		{
 			storeGroup.GET("", storeHandler.GetStores)
+			storeGroup.POST("", storeHandler.CreateStore)
 			storeGroup.GET("/my", storeHandler.GetMyStore)
-			storeGroup.GET("/:id_toko", storeHandler.GetStoreByID)
+			storeGroup.GET("/orders", storeMiddleware.ResolveStore(), transactionHandler.GetStoreTransactions)
+			storeGroup.GET("/:id_toko/detail", storeHandler.GetStoreByID)
 			storeGroup.PUT("/:id_toko", storeHandler.UpdateStore)
+			storeGroup.PATCH("/:id_toko", storeHandler.UpdateStore)
+			storeGroup.GET("/:id_toko/settings", storeSettingsHandler.GetSettings)
+			storeGroup.PUT("/:id_toko/settings", storeSettingsHandler.UpdateSettings)
+			storeGroup.POST("/:id_toko/follow", storefrontHandler.FollowStore)
+			storeGroup.DELETE("/:id_toko/follow", storefrontHandler.UnfollowStore)
+
+			// SELLER VERIFICATION (KYC)
+			storeGroup.GET("/:id_toko/documents", storeModerationHandler.GetDocuments)
+			storeGroup.POST("/:id_toko/documents", storeModerationHandler.UploadDocument)
+
+			// STORE STAFF (owner only)
+			storeGroup.GET("/:id_toko/staff", storeStaffHandler.GetStaff)
+			storeGroup.POST("/:id_toko/staff", storeStaffHandler.InviteStaff)
+			storeGroup.PUT("/:id_toko/staff/:staff_id", storeStaffHandler.UpdateStaffPermissions)
+			storeGroup.DELETE("/:id_toko/staff/:staff_id", storeStaffHandler.RemoveStaff)
+
+			// DISCOUNT CAMPAIGNS AND FLASH SALES
+			storeGroup.GET("/:id_toko/discounts", discountHandler.GetCampaigns)
+			storeGroup.POST("/:id_toko/discounts", discountHandler.CreateCampaign)
+			storeGroup.DELETE("/:id_toko/discounts/:discount_id", discountHandler.EndCampaign)
+
+			// Store vouchers
+			storeGroup.GET("/:id_toko/vouchers", voucherHandler.GetStoreVouchers)
+			storeGroup.POST("/:id_toko/vouchers", voucherHandler.CreateStoreVoucher)
+			storeGroup.DELETE("/:id_toko/vouchers/:voucher_id", voucherHandler.EndStoreVoucher)
+		}
+
+		// PRODUCT MANAGEMENT (active store via X-Store-ID)
+		productGroup := protected.Group("/product")
+		productGroup.Use(storeMiddleware.ResolveStore())
+		{
+			productGroup.POST("", productHandler.CreateProduct)
+			productGroup.POST("/import", productImportHandler.ImportProducts)
+			productGroup.GET("/import/:job_id", productImportHandler.GetImportJob)
+			productGroup.GET("/export", productImportHandler.ExportProducts)
+			productGroup.GET("/stock-alerts", inventoryHandler.GetLowStockAlerts)
+			productGroup.PUT("/:id", productHandler.UpdateProduct)
+			productGroup.PATCH("/:id", productHandler.UpdateProduct)
+			productGroup.PUT("/:id/variants", productHandler.SetProductVariants)
+			productGroup.PUT("/:id/status", productHandler.SetProductStatus)
+			productGroup.POST("/:id/stock", inventoryHandler.AdjustStock)
+			productGroup.GET("/:id/stock-movements", inventoryHandler.GetStockMovements)
+			productGroup.DELETE("/:id", productHandler.DeleteProduct)
 		}
 
 		// TRANSACTION
 		transactionGroup := protected.Group("/transaction")
 		{
-			transactionGroup.POST("", transactionHandler.CreateTransaction)
+			transactionGroup.POST("", checkoutLimit, orderIdempotency, transactionHandler.CreateTransaction)
 			transactionGroup.GET("", transactionHandler.GetUserTransactions)
 			transactionGroup.GET("/:id", transactionHandler.GetTransaction)
 		}
 
+		// CHECKOUT
+		protected.POST("/checkout/preview", checkoutPreviewLimit, checkoutHandler.PreviewCheckout)
+
+		// VOUCHER
+		protected.POST("/voucher/preview", voucherHandler.PreviewVoucher)
+
 		// =====================================================
 		// 🛡️ ADMIN ROUTES (require is_admin = true)
 		// =====================================================
@@ -141,6 +384,35 @@ func main() {
 			admin.POST("/category", categoryHandler.CreateCategory)
 			admin.PUT("/category/:id", categoryHandler.UpdateCategory)
 			admin.DELETE("/category/:id", categoryHandler.DeleteCategory)
+
+			// USER MANAGEMENT
+			admin.GET("/admin/users", userHandler.GetUsers)
+			admin.GET("/admin/users/:id", userHandler.GetUser)
+			admin.PUT("/admin/users/:id", userHandler.UpdateUser)
+			admin.PATCH("/admin/users/:id", userHandler.UpdateUser)
+			admin.DELETE("/admin/users/:id", userHandler.DeleteUser)
+			admin.GET("/admin/users/:id/stores", userHandler.GetUserStores)
+			admin.GET("/admin/users/:id/transactions", userHandler.GetUserTransactions)
+			admin.POST("/admin/users/:id/suspend", userHandler.SuspendUser)
+			admin.POST("/admin/users/:id/unsuspend", userHandler.UnsuspendUser)
+			admin.POST("/admin/users/:id/unlock", authHandler.UnlockAccount)
+
+			// STORE MODERATION
+			admin.GET("/admin/stores", storeModerationHandler.GetStores)
+			admin.GET("/admin/stores/:id", storeModerationHandler.GetReview)
+			admin.GET("/admin/stores/:id/documents/:document_id", storeModerationHandler.GetDocumentFile)
+			admin.POST("/admin/stores/:id/verify", storeModerationHandler.VerifyStore)
+			admin.POST("/admin/stores/:id/suspend", storeModerationHandler.SuspendStore)
+			admin.POST("/admin/stores/:id/revoke", storeModerationHandler.RevokeStore)
+
+			// PRODUCT MODERATION
+			admin.POST("/admin/products/:id/ban", productHandler.BanProduct)
+			admin.POST("/admin/products/:id/unban", productHandler.UnbanProduct)
+
+			// PLATFORM VOUCHERS
+			admin.GET("/admin/vouchers", voucherHandler.GetPlatformVouchers)
+			admin.POST("/admin/vouchers", voucherHandler.CreatePlatformVoucher)
+			admin.DELETE("/admin/vouchers/:id", voucherHandler.EndPlatformVoucher)
 		}
 	}
 
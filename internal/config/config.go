@@ -1,26 +1,107 @@
 // internal/config/config.go
 package config
 
-import "mini-project-ostore/pkg/database"
+import (
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/pkg/database"
+)
 
 type Config struct {
-	Server   ServerConfig
-	Database database.Config
-	JWT      JWTConfig
+	Server      ServerConfig
+	Database    database.Config
+	JWT         JWTConfig
+	RateLimit   RateLimitConfig
+	OAuth       OAuthConfig
+	Store       StoreConfig
+	Search      SearchConfig
+	Checkout    CheckoutConfig
+	Idempotency IdempotencyConfig
+	SMS         SMSConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port        string
+	Environment string // "development" or "production"; production refuses development-only fakes
+	// TrustedProxies are the addresses or CIDRs of the reverse proxies whose X-Forwarded-For
+	// header is used for the client IP; none are trusted when empty.
+	TrustedProxies []string
+}
+
+// IsProduction reports whether the server runs in production.
+func (c ServerConfig) IsProduction() bool {
+	return c.Environment == "production"
 }
 
 type JWTConfig struct {
 	Secret string
 }
 
+type StoreConfig struct {
+	MaxStoresPerUser int // Zero means unlimited
+}
+
+// CheckoutConfig holds the shipping rates and tax applied when pricing orders.
+type CheckoutConfig struct {
+	Couriers             []domain.CourierRate
+	TaxRate              float64       // Share of the items total after discounts, zero when prices include tax
+	QuoteTTL             time.Duration // How long a checkout preview can be placed at its totals
+	QuoteCleanupInterval time.Duration // How often quotes that expired unused are deleted
+}
+
+// IdempotencyConfig holds how long responses are kept for retries with the same Idempotency-Key.
+type IdempotencyConfig struct {
+	TTL time.Duration
+}
+
+// SMSConfig selects the SMS gateway: "fake" keeps messages in memory for development,
+// "http" posts them to the gateway at URL.
+type SMSConfig struct {
+	Provider string
+	URL      string
+	APIKey   string
+	From     string // Sender ID shown to the recipient
+}
+
+// SearchConfig selects the product search engine: "mysql" (FULLTEXT) or "memory" (in-process index).
+type SearchConfig struct {
+	Engine string
+}
+
+// OAuthConfig holds the social login providers. A provider is enabled when its ClientID is set.
+type OAuthConfig struct {
+	Google OAuthProviderConfig
+	// FakeEnabled mounts a local fake OIDC provider under /dev/oidc for development.
+	FakeEnabled bool
+}
+
+type OAuthProviderConfig struct {
+	ClientID        string
+	ClientSecret    string
+	RedirectURL     string // Login callback
+	LinkRedirectURL string // Callback for linking the provider to a logged-in account
+}
+
+// RateLimitConfig holds the token bucket settings per route group.
+type RateLimitConfig struct {
+	Auth            RateLimitRule // login, register, OTP (keyed by IP)
+	Catalog         RateLimitRule // public category, product and region reads (keyed by IP)
+	Checkout        RateLimitRule // transaction creation (keyed by user)
+	CheckoutPreview RateLimitRule // checkout previews, each saving a quote (keyed by user)
+}
+
+// RateLimitRule allows Limit requests in a burst, refilling fully over Window.
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
 func LoadConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port: "8080",
+			Port:        "8080",
+			Environment: "development",
 		},
 		Database: database.Config{
 			Host:     "localhost",
@@ -32,5 +113,40 @@ func LoadConfig() *Config {
 		JWT: JWTConfig{
 			Secret: "your-secret-key",
 		},
+		OAuth: OAuthConfig{
+			Google: OAuthProviderConfig{
+				RedirectURL:     "http://localhost:8080/auth/oauth/google/callback",
+				LinkRedirectURL: "http://localhost:8080/auth/oauth/google/link/callback",
+			},
+		},
+		Store: StoreConfig{
+			MaxStoresPerUser: 3,
+		},
+		Search: SearchConfig{
+			Engine: "mysql",
+		},
+		Checkout: CheckoutConfig{
+			Couriers: []domain.CourierRate{
+				{Code: "jne", Name: "JNE Reguler", PerKg: 18000, SameCityPerKg: 9000, Days: 3},
+				{Code: "jnt", Name: "J&T Express", PerKg: 17000, SameCityPerKg: 8000, Days: 3},
+				{Code: "sicepat", Name: "SiCepat BEST", PerKg: 25000, SameCityPerKg: 12000, Days: 1},
+			},
+			TaxRate:              0,
+			QuoteTTL:             15 * time.Minute,
+			QuoteCleanupInterval: time.Hour,
+		},
+		Idempotency: IdempotencyConfig{
+			TTL: 24 * time.Hour,
+		},
+		SMS: SMSConfig{
+			Provider: "fake",
+			From:     "ostore",
+		},
+		RateLimit: RateLimitConfig{
+			Auth:            RateLimitRule{Limit: 10, Window: time.Minute},
+			Catalog:         RateLimitRule{Limit: 120, Window: time.Minute},
+			Checkout:        RateLimitRule{Limit: 5, Window: time.Minute},
+			CheckoutPreview: RateLimitRule{Limit: 30, Window: time.Minute},
+		},
 	}
 }
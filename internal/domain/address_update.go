@@ -0,0 +1,14 @@
+package domain
+
+// AddressUpdate holds the changes of a partial address update; nil fields are left unchanged.
+type AddressUpdate struct {
+	Label         *string
+	ReceiverName  *string
+	Phone         *string
+	ProvinceID    *uint
+	CityID        *uint
+	SubDistrictID *uint
+	Detail        *string
+	PostalCode    *string
+	IsPrimary     *bool
+}
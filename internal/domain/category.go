@@ -6,12 +6,35 @@ import (
 	"gorm.io/gorm"
 )
 
+// Category attribute types
+const (
+	AttributeTypeString  = "string"
+	AttributeTypeNumber  = "number"
+	AttributeTypeBoolean = "boolean"
+	AttributeTypeEnum    = "enum"
+)
+
+// CategoryAttribute describes a product attribute of a category, e.g. RAM for laptops.
+// Subcategories inherit the attributes of their ancestors.
+type CategoryAttribute struct {
+	Key      string   `json:"key"`
+	Label    string   `json:"label"`
+	Type     string   `json:"type"` // string, number, boolean or enum
+	Required bool     `json:"required"`
+	Options  []string `json:"options,omitempty"` // Allowed values for enum attributes
+	Unit     string   `json:"unit,omitempty"`
+}
+
 type Category struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Name        string         `gorm:"size:100;uniqueIndex;not null" json:"name"`
-	Description string         `gorm:"type:text" json:"description"`
-	Products    []Product      `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-}
\ No newline at end of file
+	ID          uint                `gorm:"primaryKey" json:"id"`
+	ParentID    *uint               `gorm:"index" json:"parent_id"`
+	ParentKey   uint                `gorm:"->;type:int unsigned GENERATED ALWAYS AS (COALESCE(parent_id, 0)) STORED;uniqueIndex:idx_categories_parent_key_name" json:"-"` // ParentID, 0 for roots, so the index also covers roots
+	Name        string              `gorm:"size:100;not null;uniqueIndex:idx_categories_parent_key_name" json:"name"`                                                     // Unique among siblings
+	Description string              `gorm:"type:text" json:"description"`
+	Attributes  []CategoryAttribute `gorm:"type:text;serializer:json" json:"attributes"`
+	Children    []Category          `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	Products    []Product           `gorm:"foreignKey:CategoryID" json:"products,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt      `gorm:"index" json:"-"`
+}
@@ -5,6 +5,8 @@ type CategoryFilter struct {
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
 	Search string `json:"search"` // Adding search for future potential filtering
+	Cursor    string `json:"cursor"`     // Keyset cursor from a previous page; replaces Page when set
+	SkipCount bool   `json:"skip_count"` // Skip counting the total number of matches
 }
 
 // SetDefaults sets default values for pagination if not provided.
@@ -15,4 +17,7 @@ func (f *CategoryFilter) SetDefaults() {
 	if f.Limit <= 0 {
 		f.Limit = DefaultLimit
 	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
 }
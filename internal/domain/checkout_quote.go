@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// CourierRate is a shipping service offered at checkout. Each store ships its own package,
+// charged per started kilogram (product weights are in grams).
+type CourierRate struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	PerKg         float64 `json:"per_kg"`
+	SameCityPerKg float64 `json:"same_city_per_kg"` // When the store ships from the buyer's city, 0 for PerKg
+	Days          int     `json:"days"`             // Delivery time, after the store's processing days
+}
+
+// Cost returns the cost of shipping a package of the given weight in grams.
+func (c CourierRate) Cost(weight float64, sameCity bool) float64 {
+	rate := c.PerKg
+	if sameCity && c.SameCityPerKg > 0 {
+		rate = c.SameCityPerKg
+	}
+	return math.Max(1, math.Ceil(weight/1000)) * rate
+}
+
+// ShippingOption is what a courier charges for the package of one store.
+type ShippingOption struct {
+	Courier       string  `json:"courier"`
+	Name          string  `json:"name"`
+	Cost          float64 `json:"cost"`
+	EstimatedDays int     `json:"estimated_days"` // Processing and delivery
+}
+
+// CheckoutQuote is a priced preview of an order. Committing it before ExpiresAt charges the same totals.
+type CheckoutQuote struct {
+	ID               string               `gorm:"primaryKey;size:36" json:"quote_id"`
+	UserID           uint                 `gorm:"not null;index" json:"-"`
+	AddressID        uint                 `gorm:"not null" json:"address_id"`
+	Courier          string               `gorm:"size:50;not null" json:"courier"`
+	VoucherCode      string               `gorm:"size:50" json:"voucher_code,omitempty"`
+	Stores           []CheckoutQuoteStore `gorm:"type:mediumtext;serializer:json" json:"stores"`
+	Subtotal         float64              `gorm:"type:decimal(10,2);not null" json:"subtotal"`          // Items at the price charged
+	CampaignDiscount float64              `gorm:"type:decimal(10,2);not null" json:"campaign_discount"` // Saved on the regular prices, already in Subtotal
+	VoucherDiscount  float64              `gorm:"type:decimal(10,2);not null" json:"voucher_discount"`
+	ShippingCost     float64              `gorm:"type:decimal(10,2);not null" json:"shipping_cost"`
+	ShippingDiscount float64              `gorm:"type:decimal(10,2);not null" json:"shipping_discount"`
+	Tax              float64              `gorm:"type:decimal(10,2);not null" json:"tax"`
+	GrandTotal       float64              `gorm:"type:decimal(10,2);not null" json:"grand_total"`
+	ExpiresAt        time.Time            `gorm:"not null;index" json:"expires_at"`
+	UsedAt           *time.Time           `json:"-"` // Set once an order commits the quote
+	CreatedAt        time.Time            `json:"created_at"`
+}
+
+// CheckoutQuoteStore is the part of a quote shipped by one store.
+type CheckoutQuoteStore struct {
+	StoreID         uint                `json:"store_id"`
+	StoreName       string              `json:"store_name"`
+	Lines           []CheckoutQuoteLine `json:"lines"`
+	Subtotal        float64             `json:"subtotal"`
+	Weight          float64             `json:"weight"`
+	ShippingOptions []ShippingOption    `json:"shipping_options"`
+	ShippingCost    float64             `json:"shipping_cost"` // With the quote's courier
+}
+
+// CheckoutQuoteLine is a priced item of a quote.
+type CheckoutQuoteLine struct {
+	ProductID    uint    `json:"product_id"`
+	VariantID    *uint   `json:"variant_id,omitempty"`
+	Name         string  `json:"name"`
+	VariantName  string  `json:"variant_name,omitempty"`
+	Quantity     int     `json:"quantity"`
+	RegularPrice float64 `json:"regular_price"`
+	Price        float64 `json:"price"` // Charged per unit
+	CampaignID   *uint   `json:"campaign_id,omitempty"`
+	Total        float64 `json:"total"`
+}
+
+// Lines returns the lines of all stores, in the order of the quote.
+func (q *CheckoutQuote) Lines() []CheckoutQuoteLine {
+	var lines []CheckoutQuoteLine
+	for _, store := range q.Stores {
+		lines = append(lines, store.Lines...)
+	}
+	return lines
+}
@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Discount campaign types
+const (
+	DiscountTypeSale      = "sale"       // Sale price for a product, or one of its variants
+	DiscountTypeStoreSale = "store_sale" // Percentage off all products of the store
+	DiscountTypeFlashSale = "flash_sale" // Sale price for a product with a limited quantity per buyer
+)
+
+// DiscountCampaign is a time-bound price reduction set up by a seller. Product sales and flash sales
+// give either a fixed sale price or a percentage off; store-wide sales always give a percentage off.
+// When several campaigns apply, the buyer gets the lowest price; campaigns don't stack.
+type DiscountCampaign struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	StoreID      uint           `gorm:"not null;index" json:"store_id"`
+	Type         string         `gorm:"size:20;not null" json:"type"`
+	Name         string         `gorm:"size:100;not null" json:"name"`
+	ProductID    *uint          `gorm:"index" json:"product_id,omitempty"`              // Product of a sale or flash sale
+	VariantID    *uint          `json:"variant_id,omitempty"`                           // Limits a sale to one variant
+	SalePrice    *float64       `gorm:"type:decimal(10,2)" json:"sale_price,omitempty"` // Fixed price, instead of a percentage
+	Percentage   float64        `gorm:"type:decimal(5,2)" json:"percentage,omitempty"`  // Percentage off the regular price
+	StartsAt     time.Time      `gorm:"not null;index" json:"starts_at"`
+	EndsAt       time.Time      `gorm:"not null;index" json:"ends_at"`
+	Quota        int            `gorm:"not null;default:0" json:"quota,omitempty"`          // Flash sale units available, 0 for no limit
+	PerUserLimit int            `gorm:"not null;default:0" json:"per_user_limit,omitempty"` // Flash sale units per buyer
+	SoldQuantity int            `gorm:"not null;default:0" json:"sold_quantity"`            // Flash sale units sold
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// FlashSaleReservation counts the flash sale units reserved by a buyer's orders, so the per buyer
+// limit is enforced by the same conditional update that reserves them.
+type FlashSaleReservation struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	CampaignID uint      `gorm:"not null;uniqueIndex:idx_flash_sale_reservations_buyer" json:"campaign_id"`
+	UserID     uint      `gorm:"not null;uniqueIndex:idx_flash_sale_reservations_buyer;index" json:"user_id"`
+	Quantity   int       `gorm:"not null;default:0" json:"quantity"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AppliedDiscount describes the campaign behind a discounted price shown to buyers.
+type AppliedDiscount struct {
+	CampaignID   uint      `json:"campaign_id"`
+	Type         string    `json:"type"`
+	Name         string    `json:"name"`
+	EndsAt       time.Time `json:"ends_at"`
+	PerUserLimit int       `json:"per_user_limit,omitempty"` // Flash sales only
+	Remaining    *int      `json:"remaining,omitempty"`      // Flash sale units left, when limited
+}
+
+// Covers reports whether the campaign applies to the product, or to the variant when one is given.
+func (c *DiscountCampaign) Covers(product *Product, variant *ProductVariant) bool {
+	if c.StoreID != product.StoreID {
+		return false
+	}
+	if c.Type == DiscountTypeStoreSale {
+		return true
+	}
+	if c.ProductID == nil || *c.ProductID != product.ID {
+		return false
+	}
+	return c.VariantID == nil || (variant != nil && *c.VariantID == variant.ID)
+}
+
+// PriceFor returns the discounted price for a regular price, rounded to cents.
+// A fixed sale price never raises the price.
+func (c *DiscountCampaign) PriceFor(price float64) float64 {
+	if c.SalePrice != nil {
+		return math.Min(*c.SalePrice, price)
+	}
+	return math.Round(price*(100-c.Percentage)) / 100
+}
+
+// Applied describes the campaign for buyers.
+func (c *DiscountCampaign) Applied() *AppliedDiscount {
+	applied := &AppliedDiscount{CampaignID: c.ID, Type: c.Type, Name: c.Name, EndsAt: c.EndsAt}
+	if c.Type == DiscountTypeFlashSale {
+		applied.PerUserLimit = c.PerUserLimit
+		if c.Quota > 0 {
+			remaining := c.Quota - c.SoldQuantity
+			applied.Remaining = &remaining
+		}
+	}
+	return applied
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+)
+
+// Failed login reasons
+const (
+	LoginFailureUnknownEmail  = "unknown_email"
+	LoginFailureWrongPassword = "wrong_password"
+	LoginFailureAccountLocked = "account_locked" // Rejected during an account lockout
+	LoginFailureIPBlocked     = "ip_blocked"     // Rejected during an IP backoff
+)
+
+// LoginCredentialFailures are the reasons counted towards backoffs; rejections during a backoff
+// are only audited, so they don't extend it.
+var LoginCredentialFailures = []string{LoginFailureUnknownEmail, LoginFailureWrongPassword}
+
+// LoginAttempt is an audit record of a failed login.
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    *uint     `gorm:"index" json:"user_id"` // Nil when the email does not belong to any user
+	Email     string    `gorm:"size:100;index" json:"email"`
+	IPAddress string    `gorm:"size:45;index" json:"ip_address"`
+	UserAgent string    `gorm:"size:255" json:"user_agent"`
+	Reason    string    `gorm:"size:50;not null" json:"reason"` // unknown_email, wrong_password, account_locked, ip_blocked
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+)
+
+// Notification types
+const (
+	NotificationLowStock = "low_stock" // A product or variant of the user's store fell to its low-stock threshold
+)
+
+// Notification is a message for a user, kept in their inbox until read.
+type Notification struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	Type      string     `gorm:"size:50;not null" json:"type"`
+	Message   string     `gorm:"size:255;not null" json:"message"`
+	StoreID   *uint      `json:"store_id,omitempty"`
+	ProductID *uint      `json:"product_id,omitempty"`
+	VariantID *uint      `json:"variant_id,omitempty"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+import (
+	"time"
+)
+
+// OTP purposes
+const (
+	OTPPurposeLogin = "login"
+)
+
+type OTP struct {
+	ID         uint       `gorm:"primaryKey" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Phone      string     `gorm:"size:20;not null;index" json:"phone"`
+	Purpose    string     `gorm:"size:20;not null" json:"purpose"`
+	CodeHash   string     `gorm:"size:64;not null" json:"-"`          // SHA-256 hex of the code, never the code itself
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"` // Number of verification attempts
+	ExpiresAt  time.Time  `gorm:"not null" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"` // Set once the code is verified or invalidated
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidCursor is returned for a cursor that can't be decoded or used with the listing it was passed to.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor is the decoded form of an opaque keyset pagination cursor: the row a page starts after
+// (or ends before, when Backward) and the sort order it was issued for.
+type Cursor struct {
+	ID       uint   `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+	Sort     string `json:"s,omitempty"`
+}
+
+// Encode returns the opaque string form of the cursor.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor previously returned by Encode.
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || json.Unmarshal(data, &c) != nil || c.ID == 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// PageInfo describes a page of a listing: the total number of matching rows, unless counting
+// was skipped, and the cursors of the next and previous pages when there are any.
+type PageInfo struct {
+	TotalCount   int64
+	CountSkipped bool
+	NextCursor   string
+	PrevCursor   string
+}
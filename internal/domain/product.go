@@ -6,22 +6,66 @@ import (
 	"gorm.io/gorm"
 )
 
+// Product publish statuses
+const (
+	ProductStatusDraft     = "draft"     // Being prepared, only visible to the seller
+	ProductStatusActive    = "active"    // Listed and open for orders
+	ProductStatusScheduled = "scheduled" // Listed once PublishAt is reached
+	ProductStatusArchived  = "archived"  // Taken off sale but kept for the order history
+	ProductStatusBanned    = "banned"    // Taken down by an admin, see BanReason
+)
+
 type Product struct {
 	ID          uint           `gorm:"primaryKey" json:"id"`
-	StoreID     uint           `gorm:"not null" json:"store_id"`
+	StoreID     uint           `gorm:"not null;uniqueIndex:idx_products_store_sku" json:"store_id"`
 	CategoryID  uint           `gorm:"not null" json:"category_id"`
-	SKU         string         `gorm:"size:50;uniqueIndex;not null" json:"sku"`
-	Slug        string         `gorm:"size:255;uniqueIndex;not null" json:"slug"`
-	Name        string         `gorm:"size:200;not null" json:"name"`
-	Description string         `gorm:"type:text" json:"description"`
+	SKU         string         `gorm:"size:50;not null;uniqueIndex:idx_products_store_sku" json:"sku"` // Unique within the store
+	Slug        string         `gorm:"size:255;uniqueIndex;not null" json:"slug"` // Generated from Name unless set; previous slugs redirect here
+	Name        string         `gorm:"size:200;not null;index:idx_products_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"name"`
+	Description string         `gorm:"type:text;index:idx_products_fulltext,class:FULLTEXT,option:WITH PARSER ngram" json:"description"`
 	Price       float64        `gorm:"type:decimal(10,2);not null" json:"price"`
 	Stock       int            `gorm:"not null" json:"stock"`
 	Weight      float64        `gorm:"type:decimal(10,2)" json:"weight"`
 	Images      string         `gorm:"type:text" json:"images"` // JSON array of image URLs
-	IsAvailable bool           `gorm:"default:true" json:"is_available"`
+	IsAvailable bool           `json:"is_available"` // No column default, so false is saved as given
+	Status      string         `gorm:"size:20;not null;default:active;index" json:"status"`
+	PublishAt   *time.Time     `json:"publish_at,omitempty"`   // When a scheduled product goes live
+	UnpublishAt *time.Time     `json:"unpublish_at,omitempty"` // Listed products are hidden again from this time
+	BanReason   string         `gorm:"size:255" json:"ban_reason,omitempty"`
+	Attributes  map[string]interface{} `gorm:"type:text;serializer:json" json:"attributes,omitempty"` // Values for the category attribute schema
 	Store       Store          `gorm:"foreignKey:StoreID" json:"store,omitempty"`
 	Category    Category       `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Options     []ProductOption  `gorm:"foreignKey:ProductID" json:"options,omitempty"`
+	Variants    []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"` // When present, stock is tracked per variant
+	SearchScore float64           `gorm:"-" json:"search_score,omitempty"`  // Relevance when listed from a search
+	Highlights  map[string]string `gorm:"-" json:"highlights,omitempty"`    // Matched search terms wrapped in <em>
+	SalePrice   *float64          `gorm:"-" json:"sale_price,omitempty"`    // Price with the best running discount campaign
+	Discount    *AppliedDiscount  `gorm:"-" json:"discount,omitempty"`      // Campaign giving SalePrice
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
-}
\ No newline at end of file
+}
+
+// IsValidProductStatus reports whether status is a known product status.
+func IsValidProductStatus(status string) bool {
+	switch status {
+	case ProductStatusDraft, ProductStatusActive, ProductStatusScheduled, ProductStatusArchived, ProductStatusBanned:
+		return true
+	}
+	return false
+}
+
+// IsPublished reports whether the product is listed and can be ordered at the given time.
+// A scheduled product goes live by itself at PublishAt, and a listed one is hidden again at UnpublishAt.
+func (p *Product) IsPublished(now time.Time) bool {
+	switch p.Status {
+	case ProductStatusActive:
+	case ProductStatusScheduled:
+		if p.PublishAt == nil || now.Before(*p.PublishAt) {
+			return false
+		}
+	default:
+		return false
+	}
+	return p.UnpublishAt == nil || now.Before(*p.UnpublishAt)
+}
@@ -0,0 +1,25 @@
+package domain
+
+// PriceFacetBoundaries are the upper bounds of the price buckets counted in the product facets.
+// Prices from the last boundary upwards fall in an open-ended bucket.
+var PriceFacetBoundaries = []float64{50000, 100000, 250000, 500000, 1000000}
+
+// ProductFacets holds the number of products per category and price bucket for a product listing.
+// Each facet is counted with all the other filters applied, but not its own.
+type ProductFacets struct {
+	Categories  []CategoryFacet   `json:"categories"`
+	PriceRanges []PriceRangeFacet `json:"price_ranges"`
+}
+
+type CategoryFacet struct {
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Count      int64  `json:"count"`
+}
+
+// PriceRangeFacet counts products priced from Min (inclusive) up to Max (exclusive); Max is zero for the last bucket.
+type PriceRangeFacet struct {
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max,omitempty"`
+	Count int64   `json:"count"`
+}
@@ -10,12 +10,48 @@ type ProductFilter struct {
 	MaxPrice   float64 `json:"max_price"`
 	StoreID    uint    `json:"store_id"`
 	UserID     uint    `json:"user_id"`
+	Sort       string  `json:"sort"`        // One of the ProductSort* options; relevance for searches, newest otherwise
+	InStock    bool    `json:"in_stock"`    // Only products with stock left
+	Available  bool    `json:"available"`   // Only products of stores accepting orders (not on vacation)
+	ProvinceID uint    `json:"province_id"` // Store origin province
+	CityID     uint    `json:"city_id"`     // Store origin city
+	MinRating  float64 `json:"min_rating"`  // Minimum average review rating
+	Cursor     string  `json:"cursor"`      // Keyset cursor from a previous page; replaces Page when set
+	SkipCount  bool    `json:"skip_count"`  // Skip counting the total number of matches
+	Status     string  `json:"status"`      // Publish status, only for the seller's own listing
+	// CategoryIDs is the category filter expanded with its subcategories
+	CategoryIDs []uint `json:"-"`
+	// ProductIDs restricts the listing to search hits, kept in the given (relevance) order
+	ProductIDs []uint `json:"-"`
+	// IncludeUnverifiedStores keeps products of pending and suspended stores, e.g. for the owner's own listing
+	IncludeUnverifiedStores bool `json:"-"`
+	// IncludeUnpublished keeps drafts, scheduled, archived and banned products, e.g. for the seller's own listing
+	IncludeUnpublished bool `json:"-"`
+}
+
+// Product listing sort options
+const (
+	ProductSortNewest      = "newest"
+	ProductSortPriceAsc    = "price_asc"
+	ProductSortPriceDesc   = "price_desc"
+	ProductSortBestSelling = "best_selling"
+	ProductSortRating      = "rating"
+)
+
+// IsValidProductSort reports whether sort is a known sort option; empty means the default order.
+func IsValidProductSort(sort string) bool {
+	switch sort {
+	case "", ProductSortNewest, ProductSortPriceAsc, ProductSortPriceDesc, ProductSortBestSelling, ProductSortRating:
+		return true
+	}
+	return false
 }
 
 // Default values for pagination
 const (
 	DefaultPage  = 1
 	DefaultLimit = 10
+	MaxLimit     = 100
 )
 
 // SetDefaults sets default values for pagination if not provided.
@@ -26,4 +62,7 @@ func (f *ProductFilter) SetDefaults() {
 	if f.Limit <= 0 {
 		f.Limit = DefaultLimit
 	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
 }
\ No newline at end of file
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"time"
+)
+
+// Product import job statuses
+const (
+	ProductImportStatusPending   = "pending"
+	ProductImportStatusRunning   = "running"
+	ProductImportStatusCompleted = "completed" // All rows were processed; see FailedRows for rejected ones
+	ProductImportStatusFailed    = "failed"    // The job stopped before processing all rows
+)
+
+// ProductImportColumns are the columns of the bulk import and export files, in export order.
+// Attributes holds the category attribute values as a JSON object; options and variants hold JSON
+// arrays laid out like the variants request, and the stock of a product with variants is theirs.
+// Publish_at and unpublish_at are RFC 3339 times.
+var ProductImportColumns = []string{"sku", "slug", "name", "category_id", "description", "price", "stock", "weight", "images", "is_available", "attributes",
+	"status", "publish_at", "unpublish_at", "options", "variants"}
+
+// ProductImportJob tracks a bulk product import running in the background.
+type ProductImportJob struct {
+	ID            uint                 `gorm:"primaryKey" json:"id"`
+	StoreID       uint                 `gorm:"not null;index" json:"store_id"`
+	UserID        uint                 `gorm:"not null" json:"user_id"` // Who uploaded the file
+	FileName      string               `gorm:"size:255" json:"file_name"`
+	Format        string               `gorm:"size:10;not null" json:"format"` // csv or xlsx
+	Status        string               `gorm:"size:20;not null;default:pending" json:"status"`
+	TotalRows     int                  `json:"total_rows"`
+	ProcessedRows int                  `json:"processed_rows"`
+	SucceededRows int                  `json:"succeeded_rows"`
+	FailedRows    int                  `json:"failed_rows"`
+	Errors        []ProductImportError `gorm:"type:mediumtext;serializer:json" json:"errors"` // Per-row error report
+	Message       string               `gorm:"type:text" json:"message,omitempty"`            // Why a failed job stopped
+	StartedAt     *time.Time           `json:"started_at,omitempty"`
+	FinishedAt    *time.Time           `json:"finished_at,omitempty"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+}
+
+// ProductImportError is a rejected row of an import file. Row is the line number in the file, the header being row 1.
+type ProductImportError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku,omitempty"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+// ProductSearchHit is a product matching a search query with its relevance.
+type ProductSearchHit struct {
+	ProductID  uint              `json:"product_id"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"` // Field name -> HTML-escaped text with matched words in <em>
+}
@@ -0,0 +1,13 @@
+package domain
+
+import (
+	"time"
+)
+
+// ProductSlugHistory keeps a previous slug of a product so old links redirect to its current slug.
+type ProductSlugHistory struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProductID uint      `gorm:"not null;index" json:"product_id"`
+	Slug      string    `gorm:"size:255;uniqueIndex;not null" json:"slug"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package domain
+
+// ProductUpdate holds the changes of a partial product update. Nil fields are left unchanged,
+// so zero values (stock 0, is_available false, an empty description) can be set on purpose.
+type ProductUpdate struct {
+	StoreID     *uint
+	CategoryID  *uint
+	SKU         *string
+	Slug        *string // An empty slug is generated again from the name
+	Name        *string
+	Description *string
+	Price       *float64
+	Stock       *int
+	Weight      *float64
+	Images      *string
+	IsAvailable *bool
+	Attributes  map[string]interface{} // Replaces the product attributes when not nil
+}
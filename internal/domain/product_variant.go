@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ProductOption is an option type of a product, e.g. "Size" with values S, M, L.
+type ProductOption struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProductID uint      `gorm:"not null;index" json:"product_id"`
+	Name      string    `gorm:"size:50;not null" json:"name"`
+	Values    []string  `gorm:"type:text;serializer:json" json:"values"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductVariant is a purchasable combination of option values with its own SKU and stock.
+// Variant SKUs are unique within the store, together with the product SKUs.
+type ProductVariant struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	ProductID   uint              `gorm:"not null;index" json:"product_id"`
+	StoreID     uint              `gorm:"not null;uniqueIndex:idx_product_variants_store_sku" json:"-"` // Of the product, for the SKU index
+	SKU         string            `gorm:"size:50;not null;uniqueIndex:idx_product_variants_store_sku" json:"sku"`
+	Name        string            `gorm:"size:200;not null" json:"name"`            // e.g. "Red / M", in option order
+	Options     map[string]string `gorm:"type:text;serializer:json" json:"options"` // Option name -> value
+	Price       *float64          `gorm:"type:decimal(10,2)" json:"price"`          // Overrides the product price when set
+	Stock       int               `gorm:"not null" json:"stock"`
+	Image       string            `gorm:"size:255" json:"image"`
+	IsAvailable bool              `json:"is_available"`
+	SalePrice   *float64          `gorm:"-" json:"sale_price,omitempty"` // Price with the best running discount campaign
+	Discount    *AppliedDiscount  `gorm:"-" json:"discount,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt    `gorm:"index" json:"-"` // Kept for orders referencing the variant
+}
+
+// EffectivePrice returns the variant's price override, or the product price.
+func (v *ProductVariant) EffectivePrice(product *Product) float64 {
+	if v.Price != nil {
+		return *v.Price
+	}
+	return product.Price
+}
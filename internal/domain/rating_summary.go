@@ -0,0 +1,8 @@
+package domain
+
+// RatingSummary aggregates review ratings, e.g. over all products of a store.
+type RatingSummary struct {
+	Average      float64       `json:"average"`
+	Count        int64         `json:"count"`
+	Distribution map[int]int64 `json:"distribution"` // Number of reviews per star (1-5)
+}
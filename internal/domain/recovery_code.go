@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+)
+
+// RecoveryCode is a single-use backup code for two-factor authentication.
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    uint       `gorm:"not null;index" json:"user_id"`
+	CodeHash  string     `gorm:"size:64;not null" json:"-"` // SHA-256 hex of the code
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+)
+
+// Stock movement types
+const (
+	StockMovementSale         = "sale"         // Stock out by an order
+	StockMovementRestock      = "restock"      // Stock in from the seller, e.g. a new delivery
+	StockMovementAdjustment   = "adjustment"   // Correction after a count, damage or loss
+	StockMovementReturn       = "return"       // Stock in from goods returned by a buyer
+	StockMovementCancellation = "cancellation" // Stock in from an order that didn't go through
+)
+
+// DefaultLowStockThreshold is used for stores that haven't configured their settings yet.
+const DefaultLowStockThreshold = 5
+
+// StockMovement is an entry of the inventory ledger. Every stock change goes through the ledger,
+// so the stock of a product is the sum of its movements, and the stock of a variant the sum of
+// the movements recorded for it (which also count towards the product).
+type StockMovement struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	ProductID         uint      `gorm:"not null;index" json:"product_id"`
+	VariantID         *uint     `gorm:"index" json:"variant_id,omitempty"`
+	Type              string    `gorm:"size:20;not null" json:"type"`
+	Quantity          int       `gorm:"not null" json:"quantity"`      // Positive for stock in, negative for stock out
+	StockAfter        int       `gorm:"not null" json:"stock_after"`   // Product stock after the movement
+	VariantStockAfter *int      `json:"variant_stock_after,omitempty"` // Variant stock after the movement
+	Reason            string    `gorm:"size:255" json:"reason"`
+	ActorID           *uint     `json:"actor_id,omitempty"`                    // User who made the change; empty for system movements
+	TransactionID     *uint     `gorm:"index" json:"transaction_id,omitempty"` // Order behind a sale or cancellation
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+// IsValidStockMovementType reports whether t is a known stock movement type.
+func IsValidStockMovementType(t string) bool {
+	switch t {
+	case StockMovementSale, StockMovementRestock, StockMovementAdjustment, StockMovementReturn, StockMovementCancellation:
+		return true
+	}
+	return false
+}
+
+// StockMovementFilter represents the filters and pagination parameters for the stock movement history of a product.
+type StockMovementFilter struct {
+	Page      int    `json:"page"`
+	Limit     int    `json:"limit"`
+	ProductID uint   `json:"product_id"`
+	VariantID uint   `json:"variant_id"` // Only movements of this variant
+	Type      string `json:"type"`
+	Cursor    string `json:"cursor"`     // Keyset cursor from a previous page; replaces Page when set
+	SkipCount bool   `json:"skip_count"` // Skip counting the total number of matches
+}
+
+// SetDefaults sets default values for pagination if not provided.
+func (f *StockMovementFilter) SetDefaults() {
+	if f.Page <= 0 {
+		f.Page = DefaultPage
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+}
+
+// LowStockItem is a product, or a variant of it, whose stock is at or below the store's low-stock threshold.
+type LowStockItem struct {
+	ProductID   uint   `json:"product_id"`
+	ProductName string `json:"product_name"`
+	SKU         string `json:"sku"`
+	VariantID   *uint  `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	Stock       int    `json:"stock"`
+	Threshold   int    `json:"threshold"`
+}
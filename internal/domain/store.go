@@ -6,17 +6,34 @@ import (
 	"gorm.io/gorm"
 )
 
+// Store verification statuses
+const (
+	StoreStatusPending   = "pending"   // Awaiting admin review, hidden together with its products
+	StoreStatusVerified  = "verified"  // Reviewed and public
+	StoreStatusSuspended = "suspended" // Hidden together with its products
+)
+
 type Store struct {
-	ID           uint           `gorm:"primaryKey" json:"id"`
-	UserID       uint           `gorm:"not null" json:"user_id"`
-	Name         string         `gorm:"size:100;not null" json:"name"`
-	Description  string         `gorm:"type:text" json:"description"`
-	Address      string         `gorm:"type:text" json:"address"`
-	Phone        string         `gorm:"size:20" json:"phone"`
-	PhotoProfile string         `gorm:"size:255" json:"photo_profile"` // New field for store profile photo
-	User         User           `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Products     []Product      `gorm:"foreignKey:StoreID" json:"products,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	ID             uint                 `gorm:"primaryKey" json:"id"`
+	UserID         uint                 `gorm:"not null" json:"user_id"`
+	Name           string               `gorm:"size:100;not null" json:"name"`
+	Slug           string               `gorm:"size:150;uniqueIndex;default:null" json:"slug"` // Generated from Name, unique across stores
+	Description    string               `gorm:"type:text" json:"description"`
+	Address        string               `gorm:"type:text" json:"address"`
+	Phone          string               `gorm:"size:20" json:"phone"`
+	PhotoProfile   string               `gorm:"size:255" json:"photo_profile"` // New field for store profile photo
+	Status         string               `gorm:"size:20;not null;default:pending;index" json:"status"`
+	VerifiedAt     *time.Time           `json:"verified_at,omitempty"`
+	Settings       *StoreSettings       `gorm:"foreignKey:StoreID" json:"-"` // Seller-only, buyers see PublicSettings
+	PublicSettings *PublicStoreSettings `gorm:"-" json:"settings,omitempty"`
+	User           User                 `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Products       []Product            `gorm:"foreignKey:StoreID" json:"products,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+// IsPublic reports whether buyers can see the store and buy its products, which takes a verified store.
+func (s *Store) IsPublic() bool {
+	return s.Status == StoreStatusVerified
 }
\ No newline at end of file
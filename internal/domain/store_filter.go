@@ -5,6 +5,9 @@ type StoreFilter struct {
 	Page  int `json:"page"`
 	Limit int `json:"limit"`
 	Search string `json:"search"` // Adding search for future potential filtering
+	Status string `json:"status"` // Filter by verification status
+	Cursor    string `json:"cursor"`     // Keyset cursor from a previous page; replaces Page when set
+	SkipCount bool   `json:"skip_count"` // Skip counting the total number of matches
 }
 
 // SetDefaults sets default values for pagination if not provided.
@@ -15,4 +18,7 @@ func (f *StoreFilter) SetDefaults() {
 	if f.Limit <= 0 {
 		f.Limit = DefaultLimit
 	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
 }
\ No newline at end of file
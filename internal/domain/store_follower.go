@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"time"
+)
+
+// StoreFollower records a user following a store.
+type StoreFollower struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StoreID   uint      `gorm:"not null;uniqueIndex:idx_store_follower" json:"store_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_store_follower;index" json:"user_id"`
+	Store     Store     `gorm:"foreignKey:StoreID" json:"store,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
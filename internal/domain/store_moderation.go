@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"time"
+)
+
+// KYC document types accepted for seller verification
+const (
+	StoreDocumentKTP    = "ktp"    // National identity card
+	StoreDocumentNPWP   = "npwp"   // Tax ID
+	StoreDocumentSIUP   = "siup"   // Business license
+	StoreDocumentSelfie = "selfie" // Selfie holding the KTP
+)
+
+// Store moderation actions
+const (
+	StoreActionVerify  = "verify"
+	StoreActionSuspend = "suspend"
+	StoreActionRevoke  = "revoke" // Back to pending, e.g. when documents must be resubmitted
+)
+
+// StoreDocument is a KYC document uploaded by a seller for store verification.
+type StoreDocument struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	StoreID      uint      `gorm:"not null;index" json:"store_id"`
+	Type         string    `gorm:"size:20;not null" json:"type"`
+	FilePath     string    `gorm:"size:255;not null" json:"-"` // Relative to the uploads directory
+	OriginalName string    `gorm:"size:255" json:"original_name"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StoreModerationLog records an admin moderation action on a store.
+type StoreModerationLog struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	StoreID    uint      `gorm:"not null;index" json:"store_id"`
+	AdminID    uint      `gorm:"not null" json:"admin_id"`
+	Action     string    `gorm:"size:20;not null" json:"action"`
+	FromStatus string    `gorm:"size:20;not null" json:"from_status"`
+	ToStatus   string    `gorm:"size:20;not null" json:"to_status"`
+	Reason     string    `gorm:"type:text;not null" json:"reason"`
+	CreatedAt  time.Time `json:"created_at"`
+}
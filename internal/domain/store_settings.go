@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"time"
+)
+
+// DefaultMinProcessingDays is used for stores that haven't configured their settings yet.
+const DefaultMinProcessingDays = 1
+
+// OperatingHour is the opening window of a store on one weekday, in "HH:MM" server local time.
+type OperatingHour struct {
+	Day   time.Weekday `json:"day"` // 0 = Sunday ... 6 = Saturday
+	Open  string       `json:"open"`
+	Close string       `json:"close"`
+}
+
+// StoreSettings holds the structured seller settings of a store.
+type StoreSettings struct {
+	ID      uint `gorm:"primaryKey" json:"id"`
+	StoreID uint `gorm:"not null;uniqueIndex" json:"store_id"`
+
+	// Origin (pickup) address used for shipping, referencing the region API like Address
+	OriginProvinceID    uint   `json:"origin_province_id"`
+	OriginCityID        uint   `json:"origin_city_id"`
+	OriginSubDistrictID uint   `json:"origin_subdistrict_id"`
+	OriginDetail        string `gorm:"type:text" json:"origin_detail"`
+	OriginPostalCode    string `gorm:"size:10" json:"origin_postal_code"`
+
+	// Weekdays missing from the list are closed days
+	OperatingHours []OperatingHour `gorm:"type:text;serializer:json" json:"operating_hours"`
+
+	VacationMode    bool       `gorm:"default:false" json:"vacation_mode"`
+	VacationMessage string     `gorm:"type:text" json:"vacation_message"` // Auto-reply shown to buyers while on vacation
+	VacationUntil   *time.Time `json:"vacation_until,omitempty"`          // Vacation ends automatically when set
+
+	MinProcessingDays int       `gorm:"not null;default:1" json:"min_processing_days"`
+	LowStockThreshold int       `gorm:"not null;default:5" json:"low_stock_threshold"` // Stock at or below this raises a low-stock alert
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// PublicStoreSettings are the store settings shown to buyers, leaving out the origin address.
+type PublicStoreSettings struct {
+	OperatingHours    []OperatingHour `json:"operating_hours"`
+	VacationMode      bool            `json:"vacation_mode"`
+	VacationMessage   string          `json:"vacation_message"`
+	VacationUntil     *time.Time      `json:"vacation_until,omitempty"`
+	MinProcessingDays int             `json:"min_processing_days"`
+}
+
+// Public returns the settings shown to buyers.
+func (s *StoreSettings) Public() *PublicStoreSettings {
+	return &PublicStoreSettings{
+		OperatingHours:    s.OperatingHours,
+		VacationMode:      s.VacationMode,
+		VacationMessage:   s.VacationMessage,
+		VacationUntil:     s.VacationUntil,
+		MinProcessingDays: s.MinProcessingDays,
+	}
+}
+
+// OnVacation reports whether the store is on vacation at the given time.
+func (s *StoreSettings) OnVacation(now time.Time) bool {
+	if !s.VacationMode {
+		return false
+	}
+	return s.VacationUntil == nil || now.Before(*s.VacationUntil)
+}
+
+// IsOpen reports whether the store is within its operating hours at the given time.
+// Stores without operating hours are considered always open.
+func (s *StoreSettings) IsOpen(now time.Time) bool {
+	if s.OnVacation(now) {
+		return false
+	}
+	if len(s.OperatingHours) == 0 {
+		return true
+	}
+
+	clock := now.Format("15:04")
+	for _, h := range s.OperatingHours {
+		if h.Day == now.Weekday() && clock >= h.Open && clock < h.Close {
+			return true
+		}
+	}
+	return false
+}
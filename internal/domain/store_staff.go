@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+)
+
+// Store staff permissions
+const (
+	StorePermissionManageProducts = "manage_products"
+	StorePermissionProcessOrders  = "process_orders"
+	StorePermissionViewFinances   = "view_finances"
+)
+
+// Store staff statuses
+const (
+	StoreStaffStatusInvited = "invited"
+	StoreStaffStatusActive  = "active"
+)
+
+// StoreStaff grants a user delegated permissions on a store they don't own.
+type StoreStaff struct {
+	ID                uint       `gorm:"primaryKey" json:"id"`
+	StoreID           uint       `gorm:"not null;uniqueIndex:idx_store_staff_user" json:"store_id"`
+	UserID            uint       `gorm:"not null;uniqueIndex:idx_store_staff_user;index" json:"user_id"`
+	InvitedByID       uint       `gorm:"not null" json:"invited_by_id"`
+	Status            string     `gorm:"size:20;not null;default:invited" json:"status"`
+	CanManageProducts bool       `gorm:"default:false" json:"can_manage_products"`
+	CanProcessOrders  bool       `gorm:"default:false" json:"can_process_orders"`
+	CanViewFinances   bool       `gorm:"default:false" json:"can_view_finances"`
+	AcceptedAt        *time.Time `json:"accepted_at"`
+	Store             Store      `gorm:"foreignKey:StoreID" json:"store,omitempty"`
+	User              User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Can reports whether an active staff member holds the permission.
+func (s *StoreStaff) Can(permission string) bool {
+	if s.Status != StoreStaffStatusActive {
+		return false
+	}
+	switch permission {
+	case StorePermissionManageProducts:
+		return s.CanManageProducts
+	case StorePermissionProcessOrders:
+		return s.CanProcessOrders
+	case StorePermissionViewFinances:
+		return s.CanViewFinances
+	}
+	return false
+}
+
+// SetPermissions replaces the staff member's permissions with the given list.
+func (s *StoreStaff) SetPermissions(permissions []string) {
+	s.CanManageProducts, s.CanProcessOrders, s.CanViewFinances = false, false, false
+	for _, p := range permissions {
+		switch p {
+		case StorePermissionManageProducts:
+			s.CanManageProducts = true
+		case StorePermissionProcessOrders:
+			s.CanProcessOrders = true
+		case StorePermissionViewFinances:
+			s.CanViewFinances = true
+		}
+	}
+}
+
+// Permissions lists the permissions granted to the staff member.
+func (s *StoreStaff) Permissions() []string {
+	permissions := []string{}
+	if s.CanManageProducts {
+		permissions = append(permissions, StorePermissionManageProducts)
+	}
+	if s.CanProcessOrders {
+		permissions = append(permissions, StorePermissionProcessOrders)
+	}
+	if s.CanViewFinances {
+		permissions = append(permissions, StorePermissionViewFinances)
+	}
+	return permissions
+}
@@ -0,0 +1,10 @@
+package domain
+
+// StoreUpdate holds the changes of a partial store update; nil fields are left unchanged.
+type StoreUpdate struct {
+	Name         *string
+	Description  *string
+	Address      *string
+	Phone        *string
+	PhotoProfile *string
+}
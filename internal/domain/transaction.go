@@ -13,6 +13,11 @@ type Transaction struct {
 	InvoiceNumber   string         `gorm:"size:100;uniqueIndex;not null" json:"invoice_number"`
 	TotalAmount     float64        `gorm:"type:decimal(10,2);not null" json:"total_amount"`
 	ShippingCost    float64        `gorm:"type:decimal(10,2);not null" json:"shipping_cost"`
+	VoucherCode     string         `gorm:"size:50" json:"voucher_code,omitempty"`
+	VoucherDiscount float64        `gorm:"type:decimal(10,2);not null;default:0" json:"voucher_discount"`  // Off the items
+	ShippingDiscount float64       `gorm:"type:decimal(10,2);not null;default:0" json:"shipping_discount"` // Off the shipping cost
+	Tax             float64        `gorm:"type:decimal(10,2);not null;default:0" json:"tax"`
+	QuoteID         string         `gorm:"size:36;index" json:"quote_id,omitempty"` // Checkout quote the order was placed with
 	PaymentMethod   string         `gorm:"size:50;not null" json:"payment_method"` // e.g., credit_card, bank_transfer, COD
 	Status          string         `gorm:"size:50;not null" json:"status"`         // pending, paid, shipped, completed, cancelled
 	ShippingCourier string         `gorm:"size:50" json:"shipping_courier"`
@@ -34,6 +39,7 @@ type TransactionItem struct {
 	ID            uint         `gorm:"primaryKey" json:"id"`
 	TransactionID uint         `gorm:"not null" json:"transaction_id"`
 	ProductID     uint         `gorm:"not null" json:"product_id"`
+	VariantID     *uint        `json:"variant_id,omitempty"`
 	Quantity      int          `gorm:"not null" json:"quantity"`
 	Price         float64      `gorm:"type:decimal(10,2);not null" json:"price"`
 	ProductLog    ProductLog   `gorm:"foreignKey:TransactionItemID" json:"product_log"`
@@ -44,8 +50,15 @@ type ProductLog struct {
 	TransactionItemID  uint      `gorm:"not null" json:"transaction_item_id"`
 	ProductName        string    `gorm:"size:200;not null" json:"product_name"`
 	ProductDescription string    `gorm:"type:text" json:"product_description"`
-	ProductPrice       float64   `gorm:"type:decimal(10,2);not null" json:"product_price"`
+	ProductPrice       float64   `gorm:"type:decimal(10,2);not null" json:"product_price"` // Regular price of the product or variant
+	DiscountedPrice    float64   `gorm:"type:decimal(10,2);not null;default:0" json:"discounted_price"` // Price charged, after the discount campaign
+	CampaignID         *uint     `gorm:"index" json:"campaign_id,omitempty"`
+	CampaignType       string    `gorm:"size:20" json:"campaign_type,omitempty"`
 	ProductWeight      float64   `gorm:"type:decimal(10,2)" json:"product_weight"`
 	ProductImages      string    `gorm:"type:text" json:"product_images"`
+	VariantID          *uint     `json:"variant_id,omitempty"`
+	VariantSKU         string    `gorm:"size:50" json:"variant_sku,omitempty"`
+	VariantName        string    `gorm:"size:200" json:"variant_name,omitempty"`
+	VariantImage       string    `gorm:"size:255" json:"variant_image,omitempty"`
 	CreatedAt          time.Time `json:"created_at"`
 }
\ No newline at end of file
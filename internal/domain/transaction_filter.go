@@ -8,6 +8,8 @@ type TransactionFilter struct {
 	StoreID     uint   `json:"store_id"`     // Filter by store involved in the transaction
 	Status      string `json:"status"`       // Filter by transaction status
 	PaymentMethod string `json:"payment_method"` // Filter by payment method
+	Cursor      string `json:"cursor"`     // Keyset cursor from a previous page; replaces Page when set
+	SkipCount   bool   `json:"skip_count"` // Skip counting the total number of matches
 }
 
 // SetDefaults sets default values for pagination if not provided.
@@ -18,4 +20,7 @@ func (f *TransactionFilter) SetDefaults() {
 	if f.Limit <= 0 {
 		f.Limit = DefaultLimit
 	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
 }
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+)
+
+// TwoFactorChallenge tracks the verification attempts made with a two-factor challenge token,
+// keyed by the token ID, so a token can't be used for unlimited guesses or reused after login.
+type TwoFactorChallenge struct {
+	ID         string     `gorm:"primaryKey;size:36" json:"id"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	Attempts   int        `gorm:"not null;default:0" json:"attempts"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	ConsumedAt *time.Time `json:"consumed_at"` // Set once the challenge is completed
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -13,6 +13,15 @@ type User struct {
 	Phone       string         `gorm:"size:20;uniqueIndex;not null" json:"phone"`
 	Password    string         `gorm:"size:255;not null" json:"-"`
 	IsAdmin     bool           `gorm:"default:false" json:"is_admin"`
+	PhoneVerifiedAt *time.Time `json:"phone_verified_at"` // Set after the first successful OTP verification
+	EmailVerifiedAt *time.Time `json:"email_verified_at"` // Set when a logged-in user links a provider that verified the email
+	FailedLoginCount int        `gorm:"not null;default:0" json:"-"` // Consecutive failed password logins
+	LockedUntil      *time.Time `json:"locked_until,omitempty"`      // Login is refused until this time
+	TOTPSecret       string     `gorm:"size:64" json:"-"`             // Base32 secret, set on enrollment
+	TOTPLastCounter  int64      `gorm:"not null;default:0" json:"-"`  // Last accepted time step, prevents code replay
+	TwoFactorEnabled bool       `gorm:"default:false" json:"two_factor_enabled"`
+	SuspendedAt      *time.Time `json:"suspended_at,omitempty"` // Suspended users can't log in or use existing tokens
+	SuspendReason    string     `gorm:"size:255" json:"suspend_reason,omitempty"`
 	Stores      []Store        `gorm:"foreignKey:UserID" json:"stores,omitempty"`
 	Addresses   []Address      `gorm:"foreignKey:UserID" json:"addresses,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+// User role and status filter values
+const (
+	UserRoleAdmin = "admin"
+	UserRoleUser  = "user"
+
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+)
+
+// UserFilter represents the filters and pagination parameters for admin user queries.
+type UserFilter struct {
+	Page        int        `json:"page"`
+	Limit       int        `json:"limit"`
+	Search      string     `json:"search"`       // Matches name, email or phone
+	Role        string     `json:"role"`         // admin or user
+	Status      string     `json:"status"`       // active or suspended
+	CreatedFrom *time.Time `json:"created_from"` // Inclusive
+	CreatedTo   *time.Time `json:"created_to"`   // Exclusive
+}
+
+// SetDefaults sets default values for pagination if not provided.
+func (f *UserFilter) SetDefaults() {
+	if f.Page <= 0 {
+		f.Page = DefaultPage
+	}
+	if f.Limit <= 0 {
+		f.Limit = DefaultLimit
+	}
+	if f.Limit > MaxLimit {
+		f.Limit = MaxLimit
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an external OAuth/OIDC provider.
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"user_id"`
+	Provider  string    `gorm:"size:50;not null;uniqueIndex:idx_provider_subject" json:"provider"`
+	Subject   string    `gorm:"size:255;not null;uniqueIndex:idx_provider_subject" json:"subject"` // Provider's stable user ID ("sub")
+	Email     string    `gorm:"size:100" json:"email"`
+	User      User      `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+// UserUpdate holds the changes of a partial user update; nil fields are left unchanged.
+type UserUpdate struct {
+	Name     *string
+	Email    *string
+	Phone    *string
+	Password *string // Plain text, hashed by the usecase
+	IsAdmin  *bool   // Only set by the admin user endpoints
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Voucher types
+const (
+	VoucherTypeFixed        = "fixed"         // Fixed amount off the items
+	VoucherTypePercentage   = "percentage"    // Percentage off the items, up to MaxDiscount
+	VoucherTypeFreeShipping = "free_shipping" // Shipping cost covered, up to MaxDiscount
+)
+
+// Voucher is a code buyers enter at checkout. Platform vouchers (no StoreID) are set up by admins
+// and apply to the whole order; store vouchers only apply to the items of their store.
+type Voucher struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Code         string         `gorm:"size:50;not null;uniqueIndex" json:"code"` // Upper case
+	StoreID      *uint          `gorm:"index" json:"store_id,omitempty"`
+	Type         string         `gorm:"size:20;not null" json:"type"`
+	Value        float64        `gorm:"type:decimal(10,2);not null;default:0" json:"value"` // Amount, or percentage off
+	MinSpend     float64        `gorm:"type:decimal(10,2);not null;default:0" json:"min_spend"`
+	MaxDiscount  float64        `gorm:"type:decimal(10,2);not null;default:0" json:"max_discount"` // 0 for no cap
+	UsageLimit   int            `gorm:"not null;default:0" json:"usage_limit"`                     // Redemptions in total, 0 for no limit
+	PerUserLimit int            `gorm:"not null;default:0" json:"per_user_limit"`                  // Redemptions per buyer, 0 for no limit
+	UsedCount    int            `gorm:"not null;default:0" json:"used_count"`
+	StartsAt     time.Time      `gorm:"not null" json:"starts_at"`
+	EndsAt       time.Time      `gorm:"not null" json:"ends_at"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// VoucherRedemption records a voucher used on an order.
+type VoucherRedemption struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	VoucherID        uint      `gorm:"not null;index" json:"voucher_id"`
+	UserID           uint      `gorm:"not null;index" json:"user_id"`
+	TransactionID    *uint     `gorm:"index" json:"transaction_id,omitempty"` // Set once the order is saved
+	Discount         float64   `gorm:"type:decimal(10,2);not null" json:"discount"`
+	ShippingDiscount float64   `gorm:"type:decimal(10,2);not null" json:"shipping_discount"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// VoucherQuote is the outcome of applying a voucher to an order.
+type VoucherQuote struct {
+	Code             string  `json:"code"`
+	Subtotal         float64 `json:"subtotal"`          // All items
+	EligibleSubtotal float64 `json:"eligible_subtotal"` // Items the voucher applies to
+	ShippingCost     float64 `json:"shipping_cost"`
+	Discount         float64 `json:"discount"`          // Off the items
+	ShippingDiscount float64 `json:"shipping_discount"` // Off the shipping cost
+	Total            float64 `json:"total"`
+}
+
+// Discounts returns the discount off the eligible items and off the shipping cost, rounded to cents.
+func (v *Voucher) Discounts(eligibleSubtotal, shippingCost float64) (float64, float64) {
+	var discount, shippingDiscount float64
+	switch v.Type {
+	case VoucherTypeFixed:
+		discount = math.Min(v.Value, eligibleSubtotal)
+	case VoucherTypePercentage:
+		discount = eligibleSubtotal * v.Value / 100
+		if v.MaxDiscount > 0 {
+			discount = math.Min(discount, v.MaxDiscount)
+		}
+	case VoucherTypeFreeShipping:
+		shippingDiscount = shippingCost
+		if v.MaxDiscount > 0 {
+			shippingDiscount = math.Min(shippingDiscount, v.MaxDiscount)
+		}
+	}
+	return math.Round(discount*100) / 100, math.Round(shippingDiscount*100) / 100
+}
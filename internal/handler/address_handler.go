@@ -31,16 +31,17 @@ type CreateAddressRequest struct {
 	IsPrimary     *bool  `json:"is_primary"` // Use pointer to differentiate between false and not provided
 }
 
+// UpdateAddressRequest is a partial update: omitted fields are kept.
 type UpdateAddressRequest struct {
-	Label         string `json:"label" binding:"omitempty,min=3,max=50"`
-	ReceiverName  string `json:"receiver_name" binding:"omitempty,min=3,max=100"`
-	Phone         string `json:"phone" binding:"omitempty"`
-	ProvinceID    uint `json:"province_id" binding:"omitempty"`
-	CityID        uint `json:"city_id" binding:"omitempty"`
-	SubDistrictID uint `json:"subdistrict_id" binding:"omitempty"`
-	Detail        string `json:"detail" binding:"omitempty"`
-	PostalCode    string `json:"postal_code" binding:"omitempty"`
-	IsPrimary     *bool  `json:"is_primary"` // Use pointer to differentiate between false and not provided
+	Label         *string `json:"label" binding:"omitempty,min=3,max=50"`
+	ReceiverName  *string `json:"receiver_name" binding:"omitempty,min=3,max=100"`
+	Phone         *string `json:"phone" binding:"omitempty,min=1,max=20"`
+	ProvinceID    *uint   `json:"province_id" binding:"omitempty,gt=0"`
+	CityID        *uint   `json:"city_id" binding:"omitempty,gt=0"`
+	SubDistrictID *uint   `json:"subdistrict_id" binding:"omitempty,gt=0"`
+	Detail        *string `json:"detail" binding:"omitempty,min=1"`
+	PostalCode    *string `json:"postal_code" binding:"omitempty,min=1,max=10"`
+	IsPrimary     *bool   `json:"is_primary"` // Use pointer to differentiate between false and not provided
 }
 
 // CreateAddress handles the creation of a new address.
@@ -111,8 +112,14 @@ func (h *AddressHandler) GetAddress(c *gin.Context) {
 	c.JSON(http.StatusOK, address)
 }
 
-// UpdateAddress updates an existing address.
+// UpdateAddress updates an address of the authenticated user.
 func (h *AddressHandler) UpdateAddress(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	addressIDStr := c.Param("id")
 	addressID, err := strconv.ParseUint(addressIDStr, 10, 32)
 	if err != nil {
@@ -126,36 +133,19 @@ func (h *AddressHandler) UpdateAddress(c *gin.Context) {
 		return
 	}
 
-	address := &domain.Address{ID: uint(addressID)}
-	if req.Label != "" {
-		address.Label = req.Label
-	}
-	if req.ReceiverName != "" {
-		address.ReceiverName = req.ReceiverName
-	}
-	if req.Phone != "" {
-		address.Phone = req.Phone
-	}
-	if req.ProvinceID != 0 {
-		address.ProvinceID = req.ProvinceID
-	}
-	if req.CityID != 0 {
-		address.CityID = req.CityID
-	}
-	if req.SubDistrictID != 0 {
-		address.SubDistrictID = req.SubDistrictID
-	}
-	if req.Detail != "" {
-		address.Detail = req.Detail
-	}
-	if req.PostalCode != "" {
-		address.PostalCode = req.PostalCode
-	}
-	if req.IsPrimary != nil {
-		address.IsPrimary = *req.IsPrimary
+	changes := domain.AddressUpdate{
+		Label:         req.Label,
+		ReceiverName:  req.ReceiverName,
+		Phone:         req.Phone,
+		ProvinceID:    req.ProvinceID,
+		CityID:        req.CityID,
+		SubDistrictID: req.SubDistrictID,
+		Detail:        req.Detail,
+		PostalCode:    req.PostalCode,
+		IsPrimary:     req.IsPrimary,
 	}
 
-	if err := h.addressUC.Update(address); err != nil {
+	if err := h.addressUC.Update(uint(addressID), userID.(uint), changes); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -2,7 +2,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/usecase"
 
@@ -12,10 +15,11 @@ import (
 type AuthHandler struct {
 	authUC usecase.AuthUseCase
 	userUC usecase.UserUseCase
+	otpUC  usecase.OTPUseCase
 }
 
-func NewAuthHandler(authUC usecase.AuthUseCase, userUC usecase.UserUseCase) *AuthHandler {
-	return &AuthHandler{authUC: authUC, userUC: userUC}
+func NewAuthHandler(authUC usecase.AuthUseCase, userUC usecase.UserUseCase, otpUC usecase.OTPUseCase) *AuthHandler {
+	return &AuthHandler{authUC: authUC, userUC: userUC, otpUC: otpUC}
 }
 
 type RegisterRequest struct {
@@ -30,9 +34,20 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type OTPRequest struct {
+	Phone string `json:"phone" binding:"required"`
+}
+
+type OTPVerifyRequest struct {
+	Phone string `json:"phone" binding:"required"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
 type AuthResponse struct {
-	Token string      `json:"token"`
-	User  interface{} `json:"user"`
+	Token             string      `json:"token,omitempty"`
+	TwoFactorRequired bool        `json:"two_factor_required,omitempty"`
+	ChallengeToken    string      `json:"challenge_token,omitempty"` // Exchange at /auth/2fa/verify when two_factor_required
+	User              interface{} `json:"user"`
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -49,37 +64,100 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Password: req.Password,
 	}
 
+	// Register also creates the user's default store
 	err := h.authUC.Register(user)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Create default store for user
-	err = h.userUC.CreateDefaultStore(user.ID, user.Name)
+	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
+}
+
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.authUC.Login(req.Email, req.Password, c.ClientIP(), c.Request.UserAgent())
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create store"})
+		respondLoginError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "User registered successfully"})
+	c.JSON(http.StatusOK, newAuthResponse(result))
 }
 
-func (h *AuthHandler) Login(c *gin.Context) {
-	var req LoginRequest
+// RequestOTP sends a login code by SMS to a registered phone number. The response is the same
+// for unregistered numbers.
+func (h *AuthHandler) RequestOTP(c *gin.Context) {
+	var req OTPRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	token, user, err := h.authUC.Login(req.Email, req.Password)
+	if err := h.otpUC.RequestLoginOTP(req.Phone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "If the phone number is registered, an OTP has been sent"})
+}
+
+// VerifyOTP checks a login code and returns the same response as Login.
+func (h *AuthHandler) VerifyOTP(c *gin.Context) {
+	var req OTPVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.otpUC.VerifyLoginOTP(req.Phone, req.Code)
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result))
+}
+
+// UnlockAccount clears the login lockout of a user (admin only).
+func (h *AuthHandler) UnlockAccount(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.authUC.UnlockAccount(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
-	response := AuthResponse{
-		Token: token,
+	c.JSON(http.StatusOK, gin.H{"message": "User unlocked successfully"})
+}
+
+// respondLoginError answers a failed login; a locked out account or IP gets 429 with Retry-After.
+func respondLoginError(c *gin.Context, err error) {
+	var lockedErr *usecase.LoginLockedError
+	if errors.As(err, &lockedErr) {
+		c.Header("Retry-After", strconv.Itoa(int(lockedErr.RetryAfter.Seconds())+1))
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+}
+
+func newAuthResponse(result *usecase.LoginResult) AuthResponse {
+	user := result.User
+	return AuthResponse{
+		Token:             result.Token,
+		TwoFactorRequired: result.TwoFactorRequired,
+		ChallengeToken:    result.ChallengeToken,
 		User: gin.H{
 			"id":    user.ID,
 			"name":  user.Name,
@@ -87,6 +165,4 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			"phone": user.Phone,
 		},
 	}
-
-	c.JSON(http.StatusOK, response)
 }
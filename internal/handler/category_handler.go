@@ -19,13 +19,17 @@ func NewCategoryHandler(categoryUC usecase.CategoryUseCase) *CategoryHandler {
 }
 
 type CreateCategoryRequest struct {
-	Name        string `json:"name" binding:"required,min=3,max=100"`
-	Description string `json:"description"`
+	Name        string                     `json:"name" binding:"required,min=3,max=100"`
+	Description string                     `json:"description"`
+	ParentID    *uint                      `json:"parent_id"`
+	Attributes  []domain.CategoryAttribute `json:"attributes"`
 }
 
 type UpdateCategoryRequest struct {
-	Name        string `json:"name" binding:"omitempty,min=3,max=100"`
-	Description string `json:"description"`
+	Name        string                      `json:"name" binding:"omitempty,min=3,max=100"`
+	Description string                      `json:"description"`
+	ParentID    *uint                       `json:"parent_id"`  // 0 moves the category to the root level
+	Attributes  *[]domain.CategoryAttribute `json:"attributes"` // Replaces the attribute schema when set
 }
 
 // PaginatedCategoryResponse defines the structure for a paginated list of categories.
@@ -33,8 +37,10 @@ type PaginatedCategoryResponse struct {
 	Categories []domain.Category `json:"categories"`
 	Page       int               `json:"page"`
 	Limit      int               `json:"limit"`
-	TotalCount int64             `json:"total_count"`
-	TotalPages int               `json:"total_pages"`
+	TotalCount *int64            `json:"total_count,omitempty"`
+	TotalPages *int              `json:"total_pages,omitempty"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
 }
 
 // CreateCategory handles the creation of a new category.
@@ -48,10 +54,12 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	category := &domain.Category{
 		Name:        req.Name,
 		Description: req.Description,
+		ParentID:    req.ParentID,
+		Attributes:  req.Attributes,
 	}
 
 	if err := h.categoryUC.Create(category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -75,20 +83,20 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 	}
 
 	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
 
 	// Parse filtering parameters
 	filter.Search = c.Query("search")
 
-	categories, totalCount, err := h.categoryUC.GetCategories(filter)
+	categories, page, err := h.categoryUC.GetCategories(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	totalPages := 0
-	if filter.Limit > 0 {
-		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
-	}
+	totalCount, totalPages := pageTotals(page, filter.Limit)
 
 	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
 		Status:  true,
@@ -99,6 +107,8 @@ func (h *CategoryHandler) GetCategories(c *gin.Context) {
 			Limit:      filter.Limit,
 			TotalCount: totalCount,
 			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
 		},
 	})
 }
@@ -135,16 +145,31 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	category := &domain.Category{ID: uint(categoryID)}
+	// Start from the existing category so omitted fields are kept
+	category, err := h.categoryUC.GetByID(uint(categoryID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Category not found"})
+		return
+	}
 	if req.Name != "" {
 		category.Name = req.Name
 	}
 	if req.Description != "" {
 		category.Description = req.Description
 	}
+	if req.ParentID != nil {
+		if *req.ParentID == 0 {
+			category.ParentID = nil
+		} else {
+			category.ParentID = req.ParentID
+		}
+	}
+	if req.Attributes != nil {
+		category.Attributes = *req.Attributes
+	}
 
 	if err := h.categoryUC.Update(category); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -166,4 +191,40 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "Category deleted successfully"})
-}
\ No newline at end of file
+}
+
+// GetCategoryTree retrieves all categories nested under their parents.
+func (h *CategoryHandler) GetCategoryTree(c *gin.Context) {
+	tree, err := h.categoryUC.GetTree()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    tree,
+	})
+}
+
+// GetCategoryAttributes retrieves the attribute schema of a category, including inherited attributes.
+func (h *CategoryHandler) GetCategoryAttributes(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category ID"})
+		return
+	}
+
+	schema, err := h.categoryUC.GetAttributeSchema(uint(categoryID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    schema,
+	})
+}
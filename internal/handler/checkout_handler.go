@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CheckoutHandler struct {
+	checkoutUC usecase.CheckoutUseCase
+}
+
+func NewCheckoutHandler(checkoutUC usecase.CheckoutUseCase) *CheckoutHandler {
+	return &CheckoutHandler{checkoutUC: checkoutUC}
+}
+
+type CheckoutPreviewRequest struct {
+	AddressID   uint                           `json:"address_id" binding:"required"`
+	Courier     string                         `json:"courier" binding:"omitempty,max=50"` // Cheapest when empty
+	VoucherCode string                         `json:"voucher_code" binding:"omitempty,max=50"`
+	Items       []CreateTransactionItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// PreviewCheckout prices a proposed order without placing it. The returned quote_id can be
+// passed to POST /transaction to place the order at the same totals.
+func (h *CheckoutHandler) PreviewCheckout(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req CheckoutPreviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]domain.TransactionItem, 0, len(req.Items))
+	for _, itemReq := range req.Items {
+		items = append(items, domain.TransactionItem{
+			ProductID: itemReq.ProductID,
+			VariantID: itemReq.VariantID,
+			Quantity:  itemReq.Quantity,
+		})
+	}
+
+	quote, err := h.checkoutUC.Preview(userID.(uint), req.AddressID, items, req.Courier, req.VoucherCode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    quote,
+	})
+}
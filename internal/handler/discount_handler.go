@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DiscountHandler struct {
+	discountUC usecase.DiscountUseCase
+}
+
+func NewDiscountHandler(discountUC usecase.DiscountUseCase) *DiscountHandler {
+	return &DiscountHandler{discountUC: discountUC}
+}
+
+type CreateDiscountCampaignRequest struct {
+	Type         string    `json:"type" binding:"required,oneof=sale store_sale flash_sale"`
+	Name         string    `json:"name" binding:"required,max=100"`
+	ProductID    *uint     `json:"product_id"`                                 // Required for a sale or flash sale
+	VariantID    *uint     `json:"variant_id"`                                 // Limits a sale to one variant
+	SalePrice    *float64  `json:"sale_price" binding:"omitempty,gt=0"`        // Fixed price, instead of a percentage
+	Percentage   float64   `json:"percentage" binding:"omitempty,gt=0,lt=100"` // Percentage off the regular price
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+	Quota        int       `json:"quota" binding:"omitempty,min=0"`          // Flash sale units available, 0 for no limit
+	PerUserLimit int       `json:"per_user_limit" binding:"omitempty,min=1"` // Required for a flash sale
+}
+
+// CreateCampaign sets up a discount campaign or flash sale for a store.
+func (h *DiscountHandler) CreateCampaign(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req CreateDiscountCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	campaign := &domain.DiscountCampaign{
+		StoreID:      uint(storeID),
+		Type:         req.Type,
+		Name:         req.Name,
+		ProductID:    req.ProductID,
+		VariantID:    req.VariantID,
+		SalePrice:    req.SalePrice,
+		Percentage:   req.Percentage,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+		Quota:        req.Quota,
+		PerUserLimit: req.PerUserLimit,
+	}
+	if err := h.discountUC.Create(campaign, userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  true,
+		"message": "Discount campaign created",
+		"data":    campaign,
+	})
+}
+
+// GetCampaigns lists the discount campaigns of a store.
+func (h *DiscountHandler) GetCampaigns(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	campaigns, err := h.discountUC.GetCampaigns(uint(storeID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    campaigns,
+	})
+}
+
+// EndCampaign stops a running campaign, or cancels one that hasn't started yet.
+func (h *DiscountHandler) EndCampaign(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	campaignID, err := strconv.ParseUint(c.Param("discount_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discount ID"})
+		return
+	}
+
+	if err := h.discountUC.End(uint(storeID), uint(campaignID), userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Discount campaign ended successfully"})
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InventoryHandler struct {
+	inventoryUC usecase.InventoryUseCase
+}
+
+func NewInventoryHandler(inventoryUC usecase.InventoryUseCase) *InventoryHandler {
+	return &InventoryHandler{inventoryUC: inventoryUC}
+}
+
+type AdjustStockRequest struct {
+	Type      string `json:"type" binding:"required,oneof=restock return adjustment"`
+	Quantity  int    `json:"quantity" binding:"required"` // Positive adds stock; an adjustment may be negative
+	VariantID *uint  `json:"variant_id"`                  // Required for products with variants
+	Reason    string `json:"reason" binding:"max=255"`    // Required for an adjustment
+}
+
+type PaginatedStockMovementResponse struct {
+	Movements  []domain.StockMovement `json:"movements"`
+	Page       int                    `json:"page"`
+	Limit      int                    `json:"limit"`
+	TotalCount *int64                 `json:"total_count,omitempty"`
+	TotalPages *int                   `json:"total_pages,omitempty"`
+	NextCursor string                 `json:"next_cursor,omitempty"`
+	PrevCursor string                 `json:"prev_cursor,omitempty"`
+}
+
+// AdjustStock records a restock, return or stock adjustment for a product.
+func (h *InventoryHandler) AdjustStock(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req AdjustStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	movement := &domain.StockMovement{
+		ProductID: uint(productID),
+		VariantID: req.VariantID,
+		Type:      req.Type,
+		Quantity:  req.Quantity,
+		Reason:    req.Reason,
+	}
+	lowStock, err := h.inventoryUC.AdjustStock(movement, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":    true,
+		"message":   "Stock updated",
+		"data":      movement,
+		"low_stock": lowStock,
+	})
+}
+
+// GetStockMovements lists the stock movement history of a product, newest first.
+func (h *InventoryHandler) GetStockMovements(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	filter := domain.StockMovementFilter{ProductID: uint(productID), Type: c.Query("type")}
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+	filter.SetDefaults()
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
+	if variantIDStr := c.Query("variant_id"); variantIDStr != "" {
+		variantID, err := strconv.ParseUint(variantIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid variant_id format"})
+			return
+		}
+		filter.VariantID = uint(variantID)
+	}
+
+	movements, page, err := h.inventoryUC.GetStockMovements(filter, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
+	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Data: PaginatedStockMovementResponse{
+			Movements:  movements,
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalCount: totalCount,
+			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		},
+	})
+}
+
+// GetLowStockAlerts lists the products and variants of the active store that are running low on stock.
+func (h *InventoryHandler) GetLowStockAlerts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	storeID, ok := activeStoreID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.inventoryUC.GetLowStock(storeID, userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    items,
+	})
+}
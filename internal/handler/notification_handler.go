@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationUC usecase.NotificationUseCase
+}
+
+func NewNotificationHandler(notificationUC usecase.NotificationUseCase) *NotificationHandler {
+	return &NotificationHandler{notificationUC: notificationUC}
+}
+
+// GetNotifications lists the latest notifications of the authenticated user; ?unread=true leaves out read ones.
+func (h *NotificationHandler) GetNotifications(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	unreadOnly := false
+	if unreadStr := c.Query("unread"); unreadStr != "" {
+		unread, err := strconv.ParseBool(unreadStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid unread format"})
+			return
+		}
+		unreadOnly = unread
+	}
+
+	notifications, err := h.notificationUC.GetNotifications(userID.(uint), unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    notifications,
+	})
+}
+
+// MarkNotificationRead marks a notification of the authenticated user as read.
+func (h *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.notificationUC.MarkRead(uint(id), userID.(uint)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read successfully"})
+}
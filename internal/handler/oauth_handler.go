@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	oauthStateCookie     = "oauth_state"
+	oauthLinkStateCookie = "oauth_link_state"
+)
+
+type OAuthHandler struct {
+	oauthUC usecase.OAuthUseCase
+}
+
+func NewOAuthHandler(oauthUC usecase.OAuthUseCase) *OAuthHandler {
+	return &OAuthHandler{oauthUC: oauthUC}
+}
+
+// Redirect sends the user to the provider's consent page.
+func (h *OAuthHandler) Redirect(c *gin.Context) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate state"})
+		return
+	}
+	state := hex.EncodeToString(b)
+
+	authURL, err := h.oauthUC.AuthCodeURL(c.Param("provider"), state)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The state cookie protects the callback against login CSRF
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, 600, "/auth/oauth", "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// Callback completes the provider flow and returns the same response as /auth/login.
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Login cancelled: " + errParam})
+		return
+	}
+
+	state, err := c.Cookie(oauthStateCookie)
+	if err != nil || state == "" || state != c.Query("state") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OAuth state"})
+		return
+	}
+	c.SetCookie(oauthStateCookie, "", -1, "/auth/oauth", "", c.Request.TLS != nil, true)
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Authorization code is required"})
+		return
+	}
+
+	result, err := h.oauthUC.Login(c.Request.Context(), c.Param("provider"), code)
+	if err != nil {
+		respondLoginError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result))
+}
+
+// StartLink returns the provider consent URL for linking a provider account to the logged-in
+// user; the client sends the user there. Accounts with an unverified email can be linked this way.
+func (h *OAuthHandler) StartLink(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	authURL, state, err := h.oauthUC.LinkCodeURL(c.Param("provider"), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The state cookie ties the link callback to this browser, like the login state does
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthLinkStateCookie, state, 600, "/auth/oauth", "", c.Request.TLS != nil, true)
+	c.JSON(http.StatusOK, gin.H{"auth_url": authURL})
+}
+
+// LinkCallback completes the link flow started with StartLink.
+func (h *OAuthHandler) LinkCallback(c *gin.Context) {
+	if errParam := c.Query("error"); errParam != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Linking cancelled: " + errParam})
+		return
+	}
+
+	state, err := c.Cookie(oauthLinkStateCookie)
+	if err != nil || state == "" || state != c.Query("state") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid OAuth state"})
+		return
+	}
+	c.SetCookie(oauthLinkStateCookie, "", -1, "/auth/oauth", "", c.Request.TLS != nil, true)
+
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Authorization code is required"})
+		return
+	}
+
+	if err := h.oauthUC.Link(c.Request.Context(), c.Param("provider"), code, state); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Provider linked successfully"})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindPageMode parses the keyset cursor and skip_count options of a list endpoint.
+// It responds with 400 and returns false when they are invalid.
+func bindPageMode(c *gin.Context, cursor *string, skipCount *bool) bool {
+	if *cursor = c.Query("cursor"); *cursor != "" {
+		if _, err := domain.DecodeCursor(*cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return false
+		}
+	}
+	if skipCountStr := c.Query("skip_count"); skipCountStr != "" {
+		skip, err := strconv.ParseBool(skipCountStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid skip_count format"})
+			return false
+		}
+		*skipCount = skip
+	}
+	return true
+}
+
+// listErrorStatus returns the status for a failed listing: 400 for a cursor that can't be used with it,
+// e.g. one issued for another sort order, and 500 otherwise.
+func listErrorStatus(err error) int {
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// pageTotals returns the total count and number of pages of a list response,
+// or nil for both when counting was skipped.
+func pageTotals(page domain.PageInfo, limit int) (*int64, *int) {
+	if page.CountSkipped {
+		return nil, nil
+	}
+	totalPages := 0
+	if limit > 0 {
+		totalPages = int((page.TotalCount + int64(limit) - 1) / int64(limit))
+	}
+	return &page.TotalCount, &totalPages
+}
@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/middleware"
 	"mini-project-ostore/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -19,31 +22,70 @@ func NewProductHandler(productUC usecase.ProductUseCase) *ProductHandler {
 }
 
 type CreateProductRequest struct {
-	StoreID     uint    `json:"store_id" binding:"required"`
-	CategoryID  uint    `json:"category_id" binding:"required"`
-	SKU         string  `json:"sku" binding:"required,min=3,max=50"`
-	Slug        string  `json:"slug" binding:"required,min=3,max=255"`
-	Name        string  `json:"name" binding:"required,min=3,max=200"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"required,gt=0"`
-	Stock       int     `json:"stock" binding:"required,gte=0"`
-	Weight      float64 `json:"weight" binding:"omitempty,gt=0"`
-	Images      string  `json:"images"` // JSON array of image URLs
-	IsAvailable bool    `json:"is_available"`
+	StoreID     uint                   `json:"store_id"` // Defaults to the active store (X-Store-ID)
+	CategoryID  uint                   `json:"category_id" binding:"required"`
+	SKU         string                 `json:"sku" binding:"required,min=3,max=50"`
+	Slug        string                 `json:"slug" binding:"omitempty,min=3,max=255"` // Generated from the name when empty
+	Name        string                 `json:"name" binding:"required,min=3,max=200"`
+	Description string                 `json:"description"`
+	Price       float64                `json:"price" binding:"required,gt=0"`
+	Stock       *int                   `json:"stock" binding:"required,gte=0"`
+	Weight      float64                `json:"weight" binding:"omitempty,gt=0"`
+	Images      string                 `json:"images"`       // JSON array of image URLs
+	IsAvailable *bool                  `json:"is_available"` // Defaults to true
+	Attributes  map[string]interface{} `json:"attributes"`   // Values for the category attribute schema
+
+	// Publish status, active by default; publish_at is required for a scheduled product
+	// and unpublish_at hides the product again from that time
+	Status      string     `json:"status" binding:"omitempty,oneof=draft active scheduled"`
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
 }
 
+// UpdateProductRequest is a partial update: omitted fields are kept, while fields sent
+// with a zero value (stock 0, is_available false, an empty description) are applied.
 type UpdateProductRequest struct {
-	StoreID     uint    `json:"store_id" binding:"omitempty"`
-	CategoryID  uint    `json:"category_id" binding:"omitempty"`
-	SKU         string  `json:"sku" binding:"omitempty,min=3,max=50"`
-	Slug        string  `json:"slug" binding:"omitempty,min=3,max=255"`
-	Name        string  `json:"name" binding:"omitempty,min=3,max=200"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price" binding:"omitempty,gt=0"`
-	Stock       int     `json:"stock" binding:"omitempty,gte=0"`
-	Weight      float64 `json:"weight" binding:"omitempty,gt=0"`
-	Images      string  `json:"images"` // JSON array of image URLs
-	IsAvailable *bool   `json:"is_available"`
+	StoreID     *uint                  `json:"store_id" binding:"omitempty,gt=0"`
+	CategoryID  *uint                  `json:"category_id" binding:"omitempty,gt=0"`
+	SKU         *string                `json:"sku" binding:"omitempty,min=3,max=50"`
+	Slug        *string                `json:"slug" binding:"omitempty,max=255"` // Empty generates it again from the name
+	Name        *string                `json:"name" binding:"omitempty,min=3,max=200"`
+	Description *string                `json:"description"`
+	Price       *float64               `json:"price" binding:"omitempty,gt=0"`
+	Stock       *int                   `json:"stock" binding:"omitempty,gte=0"`
+	Weight      *float64               `json:"weight" binding:"omitempty,gte=0"` // 0 clears the weight
+	Images      *string                `json:"images"`                           // JSON array of image URLs
+	IsAvailable *bool                  `json:"is_available"`
+	Attributes  map[string]interface{} `json:"attributes"` // Replaces the product attributes when set
+}
+
+type SetProductStatusRequest struct {
+	Status      string     `json:"status" binding:"required,oneof=draft active scheduled archived"`
+	PublishAt   *time.Time `json:"publish_at"`   // Required for a scheduled product
+	UnpublishAt *time.Time `json:"unpublish_at"` // Hides an active or scheduled product again from this time
+}
+
+type BanProductRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+type ProductOptionRequest struct {
+	Name   string   `json:"name" binding:"required,max=50"`
+	Values []string `json:"values" binding:"required,min=1,dive,required,max=50"`
+}
+
+type ProductVariantRequest struct {
+	SKU         string            `json:"sku" binding:"required,min=3,max=50"`
+	Options     map[string]string `json:"options" binding:"required"`     // Option name -> value
+	Price       *float64          `json:"price" binding:"omitempty,gt=0"` // Overrides the product price
+	Stock       int               `json:"stock" binding:"gte=0"`
+	Image       string            `json:"image" binding:"omitempty,max=255"`
+	IsAvailable *bool             `json:"is_available"` // Defaults to true
+}
+
+type SetProductVariantsRequest struct {
+	Options  []ProductOptionRequest  `json:"options" binding:"max=3,dive"`
+	Variants []ProductVariantRequest `json:"variants" binding:"max=100,dive"`
 }
 
 // PaginatedProductResponse defines the structure for a paginated list of products.
@@ -51,14 +93,68 @@ type PaginatedProductResponse struct {
 	Products   []domain.Product `json:"products"`
 	Page       int              `json:"page"`
 	Limit      int              `json:"limit"`
-	TotalCount int64            `json:"total_count"`
-	TotalPages int              `json:"total_pages"`
+	TotalCount *int64           `json:"total_count,omitempty"`
+	TotalPages *int             `json:"total_pages,omitempty"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+	PrevCursor string           `json:"prev_cursor,omitempty"`
+	// Facets are only included in the public product listing
+	Facets *domain.ProductFacets `json:"facets,omitempty"`
+}
+
+// bindProductListOptions parses the sort option and the stock, availability, location and rating filters of a product listing.
+func bindProductListOptions(c *gin.Context, filter *domain.ProductFilter) error {
+	filter.Sort = c.Query("sort")
+	if !domain.IsValidProductSort(filter.Sort) {
+		return errors.New("Invalid sort option")
+	}
+
+	if inStockStr := c.Query("in_stock"); inStockStr != "" {
+		inStock, err := strconv.ParseBool(inStockStr)
+		if err != nil {
+			return errors.New("Invalid in_stock format")
+		}
+		filter.InStock = inStock
+	}
+
+	if availableStr := c.Query("available"); availableStr != "" {
+		available, err := strconv.ParseBool(availableStr)
+		if err != nil {
+			return errors.New("Invalid available format")
+		}
+		filter.Available = available
+	}
+
+	if provinceIDStr := c.Query("province_id"); provinceIDStr != "" {
+		provinceID, err := strconv.ParseUint(provinceIDStr, 10, 32)
+		if err != nil {
+			return errors.New("Invalid province_id format")
+		}
+		filter.ProvinceID = uint(provinceID)
+	}
+
+	if cityIDStr := c.Query("city_id"); cityIDStr != "" {
+		cityID, err := strconv.ParseUint(cityIDStr, 10, 32)
+		if err != nil {
+			return errors.New("Invalid city_id format")
+		}
+		filter.CityID = uint(cityID)
+	}
+
+	if minRatingStr := c.Query("min_rating"); minRatingStr != "" {
+		minRating, err := strconv.ParseFloat(minRatingStr, 64)
+		if err != nil || minRating < 0 || minRating > 5 {
+			return errors.New("Invalid min_rating format")
+		}
+		filter.MinRating = minRating
+	}
+
+	return nil
 }
 
 // CreateProduct handles the creation of a new product.
 func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	// Get authenticated user ID
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -75,6 +171,20 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	// Fall back to the active store selected by the store switcher
+	if req.StoreID == 0 {
+		activeStoreID, ok := c.Get("store_id")
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "store_id is required when you own several stores; set it or the X-Store-ID header"})
+			return
+		}
+		req.StoreID = activeStoreID.(uint)
+	}
+
+	isAvailable := true
+	if req.IsAvailable != nil {
+		isAvailable = *req.IsAvailable
+	}
 	product := &domain.Product{
 		StoreID:     req.StoreID,
 		CategoryID:  req.CategoryID,
@@ -83,10 +193,14 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		Name:        req.Name,
 		Description: req.Description,
 		Price:       req.Price,
-		Stock:       req.Stock,
+		Stock:       *req.Stock,
 		Weight:      req.Weight,
 		Images:      req.Images,
-		IsAvailable: req.IsAvailable,
+		IsAvailable: isAvailable,
+		Attributes:  req.Attributes,
+		Status:      req.Status,
+		PublishAt:   req.PublishAt,
+		UnpublishAt: req.UnpublishAt,
 	}
 
 	// Pass userID to Create
@@ -115,6 +229,9 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 	}
 
 	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
 
 	// Parse filtering parameters
 	filter.Search = c.Query("search")
@@ -164,17 +281,25 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		}
 	}
 
-	products, totalCount, err := h.productUC.GetProducts(filter)
+	if err := bindProductListOptions(c, &filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, page, err := h.productUC.GetProducts(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	totalPages := 0
-	if filter.Limit > 0 {
-		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
+	facets, err := h.productUC.GetProductFacets(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
 
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
 	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
 		Status:  true,
 		Message: "Succeed to GET data",
@@ -184,6 +309,9 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 			Limit:      filter.Limit,
 			TotalCount: totalCount,
 			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+			Facets:     facets,
 		},
 	})
 }
@@ -191,8 +319,8 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 // GetUserProducts retrieves all products for stores owned by the authenticated user with pagination and filtering.
 func (h *ProductHandler) GetUserProducts(c *gin.Context) {
 	// Get authenticated user ID from context
-	// This assumes the auth middleware adds "userID" to the context
-	userID, exists := c.Get("userID")
+	// This assumes the auth middleware adds "user_id" to the context
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -219,6 +347,9 @@ func (h *ProductHandler) GetUserProducts(c *gin.Context) {
 		}
 	}
 	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
 
 	// Parse filtering parameters (similar to GetProducts)
 	filter.Search = c.Query("search")
@@ -259,19 +390,32 @@ func (h *ProductHandler) GetUserProducts(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store_id format"})
 			return
 		}
+	} else if c.GetHeader(middleware.StoreHeader) != "" {
+		// Only an explicitly switched store narrows the listing; otherwise all the user's stores are shown
+		if activeStoreID, ok := c.Get("store_id"); ok {
+			filter.StoreID = activeStoreID.(uint)
+		}
 	}
 
-	products, totalCount, err := h.productUC.GetUserProducts(filter)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	filter.Status = c.Query("status")
+	if filter.Status != "" && !domain.IsValidProductStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status filter"})
 		return
 	}
 
-	totalPages := 0
-	if filter.Limit > 0 {
-		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
+	if err := bindProductListOptions(c, &filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, page, err := h.productUC.GetUserProducts(filter)
+	if err != nil {
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
 	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
 		Status:  true,
 		Message: "Succeed to GET data",
@@ -281,23 +425,36 @@ func (h *ProductHandler) GetUserProducts(c *gin.Context) {
 			Limit:      filter.Limit,
 			TotalCount: totalCount,
 			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
 		},
 	})
 }
 
-// GetProductByID retrieves a single product by its ID.
+// GetProductByID retrieves a single product by its slug, or by its ID for older links.
+// A previous slug of the product redirects to the current one.
 func (h *ProductHandler) GetProductByID(c *gin.Context) {
-	productIDStr := c.Param("id")
-	productID, err := strconv.ParseUint(productIDStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+	key := c.Param("id")
+	product, err := h.productUC.GetBySlug(key)
+	if err == nil && product.Slug != key {
+		location := "/product/" + product.Slug
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
 		return
 	}
 
-	product, err := h.productUC.GetByID(uint(productID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
-		return
+		productID, parseErr := strconv.ParseUint(key, 10, 32)
+		if parseErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if product, err = h.productUC.GetByID(uint(productID)); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
 	}
 	c.JSON(http.StatusOK, product)
 }
@@ -305,7 +462,7 @@ func (h *ProductHandler) GetProductByID(c *gin.Context) {
 // UpdateProduct updates an existing product.
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	// Get authenticated user ID
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -329,43 +486,23 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product := &domain.Product{ID: uint(productID)}
-	if req.StoreID != 0 {
-		product.StoreID = req.StoreID
-	}
-	if req.CategoryID != 0 {
-		product.CategoryID = req.CategoryID
-	}
-	if req.SKU != "" {
-		product.SKU = req.SKU
-	}
-	if req.Slug != "" {
-		product.Slug = req.Slug
-	}
-	if req.Name != "" {
-		product.Name = req.Name
-	}
-	if req.Description != "" {
-		product.Description = req.Description
-	}
-	if req.Price != 0 {
-		product.Price = req.Price
-	}
-	if req.Stock != 0 {
-		product.Stock = req.Stock
-	}
-	if req.Weight != 0 {
-		product.Weight = req.Weight
-	}
-	if req.Images != "" {
-		product.Images = req.Images
-	}
-	if req.IsAvailable != nil {
-		product.IsAvailable = *req.IsAvailable
+	changes := domain.ProductUpdate{
+		StoreID:     req.StoreID,
+		CategoryID:  req.CategoryID,
+		SKU:         req.SKU,
+		Slug:        req.Slug,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		Stock:       req.Stock,
+		Weight:      req.Weight,
+		Images:      req.Images,
+		IsAvailable: req.IsAvailable,
+		Attributes:  req.Attributes,
 	}
 
 	// Pass userID to Update
-	if err := h.productUC.Update(product, authenticatedUserID); err != nil {
+	if err := h.productUC.Update(uint(productID), changes, authenticatedUserID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -376,7 +513,7 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 // DeleteProduct deletes a product by its ID.
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	// Get authenticated user ID
-	userID, exists := c.Get("userID")
+	userID, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
@@ -402,3 +539,122 @@ func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Product deleted successfully"})
 }
+
+// SetProductVariants replaces the options and variants of a product.
+func (h *ProductHandler) SetProductVariants(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req SetProductVariantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	options := make([]domain.ProductOption, 0, len(req.Options))
+	for _, optionReq := range req.Options {
+		options = append(options, domain.ProductOption{Name: optionReq.Name, Values: optionReq.Values})
+	}
+
+	variants := make([]domain.ProductVariant, 0, len(req.Variants))
+	for _, variantReq := range req.Variants {
+		isAvailable := true
+		if variantReq.IsAvailable != nil {
+			isAvailable = *variantReq.IsAvailable
+		}
+		variants = append(variants, domain.ProductVariant{
+			SKU:         variantReq.SKU,
+			Options:     variantReq.Options,
+			Price:       variantReq.Price,
+			Stock:       variantReq.Stock,
+			Image:       variantReq.Image,
+			IsAvailable: isAvailable,
+		})
+	}
+
+	product, err := h.productUC.SetVariants(uint(productID), userID.(uint), options, variants)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product variants updated successfully", "data": product})
+}
+
+// SetProductStatus publishes, schedules, unpublishes (draft) or archives a product.
+func (h *ProductHandler) SetProductStatus(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req SetProductStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.productUC.SetStatus(uint(productID), userID.(uint), req.Status, req.PublishAt, req.UnpublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product status updated successfully", "data": product})
+}
+
+// BanProduct takes a product down with a reason shown to the seller (admin only).
+func (h *ProductHandler) BanProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	var req BanProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	product, err := h.productUC.Ban(uint(productID), req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product banned successfully", "data": product})
+}
+
+// UnbanProduct lifts the ban of a product, which comes back as a draft (admin only).
+func (h *ProductHandler) UnbanProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid product ID"})
+		return
+	}
+
+	product, err := h.productUC.Unban(uint(productID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Product unbanned successfully", "data": product})
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/usecase"
+	"mini-project-ostore/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ProductImportHandler struct {
+	importUC usecase.ProductImportUseCase
+}
+
+func NewProductImportHandler(importUC usecase.ProductImportUseCase) *ProductImportHandler {
+	return &ProductImportHandler{importUC: importUC}
+}
+
+const maxImportFileSize = 10 << 20 // 10MB
+
+type ImportProductsRequest struct {
+	File *multipart.FileHeader `form:"file" binding:"required"` // .csv or .xlsx with the export columns
+}
+
+// activeStoreID returns the store selected by the store switcher (X-Store-ID, or the user's only store).
+func activeStoreID(c *gin.Context) (uint, bool) {
+	storeID, ok := c.Get("store_id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "set the X-Store-ID header to choose the store"})
+		return 0, false
+	}
+	return storeID.(uint), true
+}
+
+// ImportProducts uploads a product file for the active store and starts a background import job.
+func (h *ProductImportHandler) ImportProducts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	storeID, ok := activeStoreID(c)
+	if !ok {
+		return
+	}
+
+	// Larger bodies are cut off before the multipart form is parsed; the extra 1MB leaves room for the form encoding
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize+(1<<20))
+
+	var req ImportProductsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file size exceeds 10MB limit"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.File.Size > maxImportFileSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file size exceeds 10MB limit"})
+		return
+	}
+
+	file, err := req.File.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read the uploaded file"})
+		return
+	}
+
+	job, err := h.importUC.StartImport(storeID, userID.(uint), req.File.Filename, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"status":  true,
+		"message": "Import started",
+		"data":    job,
+	})
+}
+
+// GetImportJob reports the progress and per-row errors of an import job.
+func (h *ProductImportHandler) GetImportJob(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	jobID, err := strconv.ParseUint(c.Param("job_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.importUC.GetImportJob(uint(jobID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    job,
+	})
+}
+
+// ExportProducts downloads all products of the active store as CSV (default) or XLSX.
+func (h *ProductImportHandler) ExportProducts(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	storeID, ok := activeStoreID(c)
+	if !ok {
+		return
+	}
+
+	format := c.DefaultQuery("format", spreadsheet.FormatCSV)
+	data, err := h.importUC.Export(storeID, userID.(uint), format)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="products-%d.%s"`, storeID, format))
+	c.Data(http.StatusOK, spreadsheet.ContentTypes[format], data)
+}
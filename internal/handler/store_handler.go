@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -25,18 +26,18 @@ func NewStoreHandler(storeUC usecase.StoreUseCase) *StoreHandler {
 }
 
 type CreateStoreRequest struct {
-	UserID      uint   `form:"user_id" binding:"required"`
 	Name        string `form:"name" binding:"required,min=3,max=100"`
 	Description string `form:"description"`
 	Address     string `form:"address"`
 	Phone       string `form:"phone" binding:"omitempty"`
 }
 
+// UpdateStoreRequest is a partial update: omitted fields are kept, fields sent empty are cleared.
 type UpdateStoreRequest struct {
-	Name        string                `form:"name" binding:"omitempty,min=3,max=100"`
-	Description string                `form:"description"`
-	Address     string                `form:"address"`
-	Phone       string                `form:"phone" binding:"omitempty"`
+	Name        *string               `form:"name" binding:"omitempty,min=3,max=100"`
+	Description *string               `form:"description"`
+	Address     *string               `form:"address"`
+	Phone       *string               `form:"phone" binding:"omitempty,max=20"`
 	PhotoProfile *multipart.FileHeader `form:"photo_profile"` // Field for file upload
 }
 
@@ -45,12 +46,20 @@ type PaginatedStoreResponse struct {
 	Stores     []domain.Store `json:"stores"`
 	Page       int            `json:"page"`
 	Limit      int            `json:"limit"`
-	TotalCount int64          `json:"total_count"`
-	TotalPages int            `json:"total_pages"`
+	TotalCount *int64         `json:"total_count,omitempty"`
+	TotalPages *int           `json:"total_pages,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	PrevCursor string         `json:"prev_cursor,omitempty"`
 }
 
-// CreateStore handles the creation of a new store.
+// CreateStore handles the creation of a new store for the authenticated user.
 func (h *StoreHandler) CreateStore(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	var req CreateStoreRequest
 	if err := c.ShouldBind(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -58,7 +67,7 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 	}
 
 	store := &domain.Store{
-		UserID:      req.UserID,
+		UserID:      userID.(uint),
 		Name:        req.Name,
 		Description: req.Description,
 		Address:     req.Address,
@@ -66,11 +75,11 @@ func (h *StoreHandler) CreateStore(c *gin.Context) {
 	}
 
 	if err := h.storeUC.Create(store); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "Store created successfully", "store_id": store.ID})
+	c.JSON(http.StatusCreated, gin.H{"message": "Store created successfully", "store_id": store.ID, "slug": store.Slug})
 }
 
 // GetStores retrieves all stores with pagination and filtering.
@@ -90,20 +99,23 @@ func (h *StoreHandler) GetStores(c *gin.Context) {
 	}
 
 	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
 
 	// Parse filtering parameters
 	filter.Search = c.Query("search")
 
-	stores, totalCount, err := h.storeUC.GetStores(filter)
+	// Only verified stores are listed publicly
+	filter.Status = domain.StoreStatusVerified
+
+	stores, page, err := h.storeUC.GetStores(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	totalPages := 0
-	if filter.Limit > 0 {
-		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
-	}
+	totalCount, totalPages := pageTotals(page, filter.Limit)
 
 	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
 		Status:  true,
@@ -114,6 +126,8 @@ func (h *StoreHandler) GetStores(c *gin.Context) {
 			Limit:      filter.Limit,
 			TotalCount: totalCount,
 			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
 		},
 	})
 }
@@ -137,17 +151,27 @@ func (h *StoreHandler) GetUserStores(c *gin.Context) {
 	c.JSON(http.StatusOK, stores)
 }
 
-// GetStoreByID retrieves a single store by its ID.
+// GetStoreByID retrieves a single store by its ID for its owner or an admin, including stores
+// still under review. Buyers use the public storefront instead.
 func (h *StoreHandler) GetStoreByID(c *gin.Context) {
-	storeIDStr := c.Param("id_toko")
-	storeID, err := strconv.ParseUint(storeIDStr, 10, 32)
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
 		return
 	}
 
-	store, err := h.storeUC.GetByID(uint(storeID))
+	store, err := h.storeUC.GetByIDForUser(uint(storeID), userID.(uint))
 	if err != nil {
+		if errors.Is(err, usecase.ErrStoreAccessDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
@@ -191,20 +215,12 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 		return
 	}
 
-	// Create a store object for updates, pre-filling with existing data to ensure all fields are considered.
-	// We only update fields that are explicitly provided in the request.
-	storeToUpdate := existingStore // Start with existing data
-	if req.Name != "" {
-		storeToUpdate.Name = req.Name
-	}
-	if req.Description != "" {
-		storeToUpdate.Description = req.Description
-	}
-	if req.Address != "" {
-		storeToUpdate.Address = req.Address
-	}
-	if req.Phone != "" {
-		storeToUpdate.Phone = req.Phone
+	// Only the fields present in the request are updated
+	changes := domain.StoreUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		Address:     req.Address,
+		Phone:       req.Phone,
 	}
 
 	// Handle photo profile upload
@@ -259,7 +275,7 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 
 		// Construct the relative path to be stored in the database (e.g., "stores/uuid.jpg")
 		dbPhotoPath := filepath.Join("stores", newFileName)
-		storeToUpdate.PhotoProfile = dbPhotoPath // This is the path stored in DB
+		changes.PhotoProfile = &dbPhotoPath // This is the path stored in DB
 
 		// Construct the full path where the file will be saved on the server
 		savePath := filepath.Join(uploadSubDir, newFileName)
@@ -271,7 +287,7 @@ func (h *StoreHandler) UpdateStore(c *gin.Context) {
 		}
 	}
 
-	if err := h.storeUC.Update(storeToUpdate); err != nil {
+	if err := h.storeUC.Update(existingStore.ID, changes); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
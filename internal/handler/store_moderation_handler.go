@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type StoreModerationHandler struct {
+	moderationUC usecase.StoreModerationUseCase
+}
+
+func NewStoreModerationHandler(moderationUC usecase.StoreModerationUseCase) *StoreModerationHandler {
+	return &StoreModerationHandler{moderationUC: moderationUC}
+}
+
+type UploadStoreDocumentRequest struct {
+	Type     string                `form:"type" binding:"required,oneof=ktp npwp siup selfie"`
+	Document *multipart.FileHeader `form:"document" binding:"required"`
+}
+
+type ModerateStoreRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// UploadDocument uploads a KYC document for the authenticated user's store.
+func (h *StoreModerationHandler) UploadDocument(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	// Ownership is checked before anything is written to disk
+	if err := h.moderationUC.AuthorizeDocuments(uint(storeID), userID.(uint)); err != nil {
+		if errors.Is(err, usecase.ErrStoreDocumentsDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	var req UploadStoreDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	file := req.Document
+	if file.Size > (8 << 20) { // 8MB
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file size exceeds 8MB limit"})
+		return
+	}
+
+	allowedExtensions := map[string]bool{
+		".jpg":  true,
+		".jpeg": true,
+		".png":  true,
+		".pdf":  true,
+	}
+	extension := strings.ToLower(filepath.Ext(file.Filename))
+	if !allowedExtensions[extension] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file type. Only JPG, JPEG, PNG, PDF are allowed."})
+		return
+	}
+
+	// KYC documents are kept outside the public stores folder
+	uploadSubDir := filepath.Join("..", "..", "uploads", "kyc")
+	if err := os.MkdirAll(uploadSubDir, os.ModePerm); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create upload directory: %v", err)})
+		return
+	}
+
+	newFileName := uuid.New().String() + extension
+	document := &domain.StoreDocument{
+		StoreID:      uint(storeID),
+		Type:         req.Type,
+		FilePath:     filepath.Join("kyc", newFileName),
+		OriginalName: file.Filename,
+	}
+
+	// Save the file before recording it, so no document points at a missing file,
+	// and remove it again when the document can't be recorded
+	filePath := filepath.Join(uploadSubDir, newFileName)
+	if err := c.SaveUploadedFile(file, filePath); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to save document: %v", err)})
+		return
+	}
+
+	if err := h.moderationUC.AddDocument(document, userID.(uint)); err != nil {
+		if removeErr := os.Remove(filePath); removeErr != nil && !os.IsNotExist(removeErr) {
+			fmt.Printf("Warning: Failed to delete unrecorded document %s: %v\n", filePath, removeErr)
+		}
+		if errors.Is(err, usecase.ErrStoreDocumentsDenied) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Document uploaded successfully", "document_id": document.ID})
+}
+
+// GetDocuments lists the KYC documents of the authenticated user's store.
+func (h *StoreModerationHandler) GetDocuments(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	documents, err := h.moderationUC.GetDocuments(uint(storeID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    documents,
+	})
+}
+
+// GetStores lists stores of any status for admin review.
+func (h *StoreModerationHandler) GetStores(c *gin.Context) {
+	var filter domain.StoreFilter
+
+	// Parse pagination parameters
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
+
+	// Parse filtering parameters
+	filter.Search = c.Query("search")
+	filter.Status = c.Query("status")
+
+	stores, page, err := h.moderationUC.GetStores(filter)
+	if err != nil {
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
+	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Data: PaginatedStoreResponse{
+			Stores:     stores,
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalCount: totalCount,
+			TotalPages: totalPages,
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+		},
+	})
+}
+
+// GetReview retrieves a store with its KYC documents and moderation history.
+func (h *StoreModerationHandler) GetReview(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	review, err := h.moderationUC.GetReview(uint(storeID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    review,
+	})
+}
+
+// GetDocumentFile serves an uploaded KYC document to an admin.
+func (h *StoreModerationHandler) GetDocumentFile(c *gin.Context) {
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	documentID, err := strconv.ParseUint(c.Param("document_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid document ID"})
+		return
+	}
+
+	document, err := h.moderationUC.GetDocument(uint(storeID), uint(documentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.FileAttachment(filepath.Join("..", "..", "uploads", document.FilePath), document.OriginalName)
+}
+
+// VerifyStore marks a store as verified.
+func (h *StoreModerationHandler) VerifyStore(c *gin.Context) {
+	h.moderate(c, domain.StoreActionVerify)
+}
+
+// SuspendStore suspends a store and hides its products.
+func (h *StoreModerationHandler) SuspendStore(c *gin.Context) {
+	h.moderate(c, domain.StoreActionSuspend)
+}
+
+// RevokeStore sends a store back to pending review.
+func (h *StoreModerationHandler) RevokeStore(c *gin.Context) {
+	h.moderate(c, domain.StoreActionRevoke)
+}
+
+func (h *StoreModerationHandler) moderate(c *gin.Context, action string) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req ModerateStoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	store, err := h.moderationUC.Moderate(uint(storeID), adminID.(uint), action, req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Store " + store.Status,
+		"data":    store,
+	})
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StoreSettingsHandler struct {
+	settingsUC usecase.StoreSettingsUseCase
+}
+
+func NewStoreSettingsHandler(settingsUC usecase.StoreSettingsUseCase) *StoreSettingsHandler {
+	return &StoreSettingsHandler{settingsUC: settingsUC}
+}
+
+type UpdateStoreSettingsRequest struct {
+	OriginProvinceID    uint                   `json:"origin_province_id" binding:"required"`
+	OriginCityID        uint                   `json:"origin_city_id" binding:"required"`
+	OriginSubDistrictID uint                   `json:"origin_subdistrict_id" binding:"required"`
+	OriginDetail        string                 `json:"origin_detail" binding:"required"`
+	OriginPostalCode    string                 `json:"origin_postal_code" binding:"required,max=10"`
+	OperatingHours      []domain.OperatingHour `json:"operating_hours"`
+	VacationMode        bool                   `json:"vacation_mode"`
+	VacationMessage     string                 `json:"vacation_message"`
+	VacationUntil       *time.Time             `json:"vacation_until"`
+	MinProcessingDays   int                    `json:"min_processing_days" binding:"required,min=1,max=30"`
+	LowStockThreshold   *int                   `json:"low_stock_threshold" binding:"omitempty,min=1,max=10000"` // Unchanged when omitted
+}
+
+// GetSettings retrieves the settings of the authenticated owner's store.
+func (h *StoreSettingsHandler) GetSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	settings, err := h.settingsUC.GetSettings(uint(storeID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    settings,
+	})
+}
+
+// UpdateSettings replaces the settings of the authenticated owner's store.
+func (h *StoreSettingsHandler) UpdateSettings(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req UpdateStoreSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings := &domain.StoreSettings{
+		StoreID:             uint(storeID),
+		OriginProvinceID:    req.OriginProvinceID,
+		OriginCityID:        req.OriginCityID,
+		OriginSubDistrictID: req.OriginSubDistrictID,
+		OriginDetail:        req.OriginDetail,
+		OriginPostalCode:    req.OriginPostalCode,
+		OperatingHours:      req.OperatingHours,
+		VacationMode:        req.VacationMode,
+		VacationMessage:     req.VacationMessage,
+		VacationUntil:       req.VacationUntil,
+		MinProcessingDays:   req.MinProcessingDays,
+	}
+	if req.LowStockThreshold != nil {
+		settings.LowStockThreshold = *req.LowStockThreshold
+	}
+
+	if err := h.settingsUC.UpdateSettings(settings, userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Store settings updated successfully", "data": settings})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StoreStaffHandler struct {
+	staffUC usecase.StoreStaffUseCase
+}
+
+func NewStoreStaffHandler(staffUC usecase.StoreStaffUseCase) *StoreStaffHandler {
+	return &StoreStaffHandler{staffUC: staffUC}
+}
+
+type InviteStaffRequest struct {
+	Email       string   `json:"email" binding:"required,email"`
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=manage_products process_orders view_finances"`
+}
+
+type UpdateStaffPermissionsRequest struct {
+	Permissions []string `json:"permissions" binding:"required,min=1,dive,oneof=manage_products process_orders view_finances"`
+}
+
+// InviteStaff invites a registered user to the owner's store.
+func (h *StoreStaffHandler) InviteStaff(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req InviteStaffRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	staff, err := h.staffUC.Invite(uint(storeID), userID.(uint), req.Email, req.Permissions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "Invitation sent successfully", "invitation_id": staff.ID})
+}
+
+// GetStaff lists the staff and pending invitations of the owner's store.
+func (h *StoreStaffHandler) GetStaff(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	staff, err := h.staffUC.GetStaff(uint(storeID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    staff,
+	})
+}
+
+// UpdateStaffPermissions replaces the permissions of a staff member.
+func (h *StoreStaffHandler) UpdateStaffPermissions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	staffID, err := strconv.ParseUint(c.Param("staff_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID"})
+		return
+	}
+
+	var req UpdateStaffPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.staffUC.UpdatePermissions(uint(storeID), uint(staffID), userID.(uint), req.Permissions); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff permissions updated successfully"})
+}
+
+// RemoveStaff revokes a staff membership or pending invitation.
+func (h *StoreStaffHandler) RemoveStaff(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	staffID, err := strconv.ParseUint(c.Param("staff_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff ID"})
+		return
+	}
+
+	if err := h.staffUC.Remove(uint(storeID), uint(staffID), userID.(uint)); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Staff removed successfully"})
+}
+
+// GetInvitations lists the pending store invitations of the authenticated user.
+func (h *StoreStaffHandler) GetInvitations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	invitations, err := h.staffUC.GetInvitations(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    invitations,
+	})
+}
+
+// GetMemberships lists the stores the authenticated user works at as staff.
+func (h *StoreStaffHandler) GetMemberships(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	memberships, err := h.staffUC.GetMemberships(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    memberships,
+	})
+}
+
+// AcceptInvitation accepts a pending store invitation.
+func (h *StoreStaffHandler) AcceptInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.staffUC.AcceptInvitation(uint(id), userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted successfully"})
+}
+
+// DeclineInvitation declines a pending store invitation.
+func (h *StoreStaffHandler) DeclineInvitation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+
+	if err := h.staffUC.DeclineInvitation(uint(id), userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation declined successfully"})
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StorefrontHandler struct {
+	storefrontUC usecase.StorefrontUseCase
+}
+
+func NewStorefrontHandler(storefrontUC usecase.StorefrontUseCase) *StorefrontHandler {
+	return &StorefrontHandler{storefrontUC: storefrontUC}
+}
+
+// StorefrontResponse defines the structure of a public store page.
+type StorefrontResponse struct {
+	Store         *domain.Store            `json:"store"`
+	IsOpen        bool                     `json:"is_open"`
+	ProductCount  int64                    `json:"product_count"`
+	FollowerCount int64                    `json:"follower_count"`
+	Rating        *domain.RatingSummary    `json:"rating"`
+	Products      PaginatedProductResponse `json:"products"`
+}
+
+// GetStorefront retrieves the public page of a store by slug (or ID), without login.
+func (h *StorefrontHandler) GetStorefront(c *gin.Context) {
+	var filter domain.ProductFilter
+
+	// Parse pagination parameters
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
+
+	// Parse filtering parameters
+	filter.Search = c.Query("search")
+	if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
+		if categoryID, err := strconv.ParseUint(categoryIDStr, 10, 32); err == nil {
+			filter.CategoryID = uint(categoryID)
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id format"})
+			return
+		}
+	}
+	if err := bindProductListOptions(c, &filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	storefront, err := h.storefrontUC.GetStorefront(c.Param("id_toko"), filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalCount, totalPages := pageTotals(storefront.ProductPage, filter.Limit)
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data": StorefrontResponse{
+			Store:         storefront.Store,
+			IsOpen:        storefront.IsOpen,
+			ProductCount:  storefront.ProductCount,
+			FollowerCount: storefront.FollowerCount,
+			Rating:        storefront.Rating,
+			Products: PaginatedProductResponse{
+				Products:   storefront.Products,
+				Page:       filter.Page,
+				Limit:      filter.Limit,
+				TotalCount: totalCount,
+				TotalPages: totalPages,
+				NextCursor: storefront.ProductPage.NextCursor,
+				PrevCursor: storefront.ProductPage.PrevCursor,
+			},
+		},
+	})
+}
+
+// FollowStore makes the authenticated user follow a store.
+func (h *StorefrontHandler) FollowStore(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	followerCount, err := h.storefrontUC.Follow(c.Param("id_toko"), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Store followed successfully", "follower_count": followerCount})
+}
+
+// UnfollowStore removes the authenticated user's follow of a store.
+func (h *StorefrontHandler) UnfollowStore(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	followerCount, err := h.storefrontUC.Unfollow(c.Param("id_toko"), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Store unfollowed successfully", "follower_count": followerCount})
+}
+
+// GetFollowedStores lists the stores the authenticated user follows.
+func (h *StorefrontHandler) GetFollowedStores(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	followed, err := h.storefrontUC.GetFollowedStores(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    followed,
+	})
+}
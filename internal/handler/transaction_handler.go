@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -18,19 +19,21 @@ func NewTransactionHandler(transactionUC usecase.TransactionUseCase) *Transactio
 	return &TransactionHandler{transactionUC: transactionUC}
 }
 
+// CreateTransactionItemRequest is an item to order; it is charged the current price
+// with the best running discount campaign.
 type CreateTransactionItemRequest struct {
-	ProductID uint    `json:"product_id" binding:"required"`
-	Quantity  int     `json:"quantity" binding:"required,min=1"`
-	Price     float64 `json:"price" binding:"required,min=0"`
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id"` // Required for products with variants
+	Quantity  int   `json:"quantity" binding:"required,min=1"`
 }
 
 type CreateTransactionRequest struct {
-	UserID          uint                           `json:"user_id" binding:"required"`
-	AddressID       uint                           `json:"address_id" binding:"required"`
-	ShippingCost    float64                        `json:"shipping_cost" binding:"required,min=0"`
+	QuoteID         string                         `json:"quote_id" binding:"omitempty,max=36"` // From /checkout/preview; sets the address, shipping, voucher and items
+	AddressID       uint                           `json:"address_id" binding:"required_without=QuoteID"`
 	PaymentMethod   string                         `json:"payment_method" binding:"required"`
-	ShippingCourier string                         `json:"shipping_courier"`
-	Items           []CreateTransactionItemRequest `json:"items" binding:"required,min=1"`
+	ShippingCourier string                         `json:"shipping_courier"`                        // Charged at its rates, the cheapest courier when empty
+	VoucherCode     string                         `json:"voucher_code" binding:"omitempty,max=50"` // Redeemed with the order
+	Items           []CreateTransactionItemRequest `json:"items" binding:"required_without=QuoteID"`
 }
 
 // PaginatedTransactionResponse defines the structure for a paginated list of transactions.
@@ -38,12 +41,21 @@ type PaginatedTransactionResponse struct {
 	Transactions []domain.Transaction `json:"transactions"`
 	Page         int                  `json:"page"`
 	Limit        int                  `json:"limit"`
-	TotalCount   int64                `json:"total_count"`
-	TotalPages   int                  `json:"total_pages"`
+	TotalCount   *int64               `json:"total_count,omitempty"`
+	TotalPages   *int                 `json:"total_pages,omitempty"`
+	NextCursor   string               `json:"next_cursor,omitempty"`
+	PrevCursor   string               `json:"prev_cursor,omitempty"`
 }
 
 // CreateTransaction handles the creation of a new transaction.
 func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
+	// The buyer is the authenticated user; per-buyer limits are keyed on it
+	uid, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
 	var req CreateTransactionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -51,20 +63,21 @@ func (h *TransactionHandler) CreateTransaction(c *gin.Context) {
 	}
 
 	transaction := &domain.Transaction{
-		UserID:          req.UserID,
+		UserID:          uid.(uint),
 		AddressID:       req.AddressID,
 		TotalAmount:     0, // Will be calculated in usecase
-		ShippingCost:    req.ShippingCost,
 		PaymentMethod:   req.PaymentMethod,
 		ShippingCourier: req.ShippingCourier,
+		VoucherCode:     req.VoucherCode,
+		QuoteID:         req.QuoteID,
 		Status:          "pending", // Default status
 	}
 
 	for _, itemReq := range req.Items {
 		transaction.Items = append(transaction.Items, domain.TransactionItem{
 			ProductID: itemReq.ProductID,
+			VariantID: itemReq.VariantID,
 			Quantity:  itemReq.Quantity,
-			Price:     itemReq.Price,
 		})
 	}
 
@@ -106,6 +119,9 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 		}
 	}
 	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
 
 	// Parse filtering parameters
 	filter.Status = c.Query("status")
@@ -121,16 +137,13 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 	}
 
 	// Retrieve all transactions for this user with filter and pagination
-	transactions, totalCount, err := h.transactionUC.GetUserTransactions(filter)
+	transactions, page, err := h.transactionUC.GetUserTransactions(filter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	totalPages := 0
-	if filter.Limit > 0 {
-		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
-	}
+	totalCount, totalPages := pageTotals(page, filter.Limit)
 
 	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
 		Status:  true,
@@ -141,6 +154,8 @@ func (h *TransactionHandler) GetUserTransactions(c *gin.Context) {
 			Limit:        filter.Limit,
 			TotalCount:   totalCount,
 			TotalPages:   totalPages,
+			NextCursor:   page.NextCursor,
+			PrevCursor:   page.PrevCursor,
 		},
 	})
 }
@@ -174,4 +189,63 @@ func (h *TransactionHandler) GetTransaction(c *gin.Context) {
 		return
 	}
 	c.JSON(http.StatusOK, transaction)
-}
\ No newline at end of file
+}
+
+// GetStoreTransactions retrieves the orders of the active store (see middleware.StoreHeader).
+func (h *TransactionHandler) GetStoreTransactions(c *gin.Context) {
+	storeID, exists := c.Get("store_id")
+	if !exists {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Select a store with the X-Store-ID header"})
+		return
+	}
+
+	var filter domain.TransactionFilter
+	filter.StoreID = storeID.(uint)
+
+	// Parse pagination parameters
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
+
+	// Parse filtering parameters
+	filter.Status = c.Query("status")
+	filter.PaymentMethod = c.Query("payment_method")
+
+	userID, _ := c.Get("user_id")
+	transactions, page, err := h.transactionUC.GetStoreTransactions(filter, userID.(uint))
+	if err != nil {
+		if errors.Is(err, usecase.ErrStorePermission) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(listErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
+	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Data: PaginatedTransactionResponse{
+			Transactions: transactions,
+			Page:         filter.Page,
+			Limit:        filter.Limit,
+			TotalCount:   totalCount,
+			TotalPages:   totalPages,
+			NextCursor:   page.NextCursor,
+			PrevCursor:   page.PrevCursor,
+		},
+	})
+}
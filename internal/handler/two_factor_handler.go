@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"net/http"
+
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TwoFactorHandler struct {
+	twoFactorUC usecase.TwoFactorUseCase
+}
+
+func NewTwoFactorHandler(twoFactorUC usecase.TwoFactorUseCase) *TwoFactorHandler {
+	return &TwoFactorHandler{twoFactorUC: twoFactorUC}
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"` // TOTP code or recovery code
+}
+
+// Enroll starts TOTP enrollment for the authenticated user.
+func (h *TwoFactorHandler) Enroll(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	enrollment, err := h.twoFactorUC.Enroll(userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Scan the provisioning URI and confirm with a code",
+		"data":    enrollment,
+	})
+}
+
+// Confirm enables two-factor authentication and returns the recovery codes.
+func (h *TwoFactorHandler) Confirm(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorUC.Confirm(userID.(uint), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Two-factor authentication enabled",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// Disable turns two-factor authentication off.
+func (h *TwoFactorHandler) Disable(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.twoFactorUC.Disable(userID.(uint), req.Code); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the authenticated user's recovery codes.
+func (h *TwoFactorHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req TwoFactorCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	codes, err := h.twoFactorUC.RegenerateRecoveryCodes(userID.(uint), req.Code)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Recovery codes regenerated",
+		"data":    gin.H{"recovery_codes": codes},
+	})
+}
+
+// VerifyChallenge completes a two-factor login and returns the final token.
+func (h *TwoFactorHandler) VerifyChallenge(c *gin.Context) {
+	var req TwoFactorChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.twoFactorUC.VerifyChallenge(req.ChallengeToken, req.Code)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newAuthResponse(result))
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/usecase"
@@ -18,13 +20,27 @@ func NewUserHandler(userUC usecase.UserUseCase) *UserHandler {
 	return &UserHandler{userUC: userUC}
 }
 
-// UserUpdateRequest represents the request body for updating a user.
+// UserUpdateRequest represents the request body for a partial user update; omitted fields are kept.
 type UserUpdateRequest struct {
-	Name     string `json:"name" binding:"omitempty,min=3"`
-	Email    string `json:"email" binding:"omitempty,email"`
-	Phone    string `json:"phone" binding:"omitempty"` // using regex for phone is better but for now omitempty
-	Password string `json:"password" binding:"omitempty,min=6"`
-	IsAdmin  *bool  `json:"is_admin"` // Use pointer to differentiate between false and not provided
+	Name     *string `json:"name" binding:"omitempty,min=3,max=100"`
+	Email    *string `json:"email" binding:"omitempty,email"`
+	Phone    *string `json:"phone" binding:"omitempty,min=1,max=20"` // using regex for phone is better but for now a length check
+	Password *string `json:"password" binding:"omitempty,min=6"`
+	IsAdmin  *bool   `json:"is_admin"` // Use pointer to differentiate between false and not provided
+}
+
+// SuspendUserRequest represents the request body for suspending a user.
+type SuspendUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// PaginatedUserResponse defines the structure for a paginated list of users.
+type PaginatedUserResponse struct {
+	Users      []domain.User `json:"users"`
+	Page       int           `json:"page"`
+	Limit      int           `json:"limit"`
+	TotalCount int64         `json:"total_count"`
+	TotalPages int           `json:"total_pages"`
 }
 
 // GetUser retrieves a user by ID.
@@ -60,26 +76,16 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		return
 	}
 
-	user := &domain.User{ID: uint(id)}
-
-	// Only update fields if they are explicitly provided in the request
-	if req.Name != "" {
-		user.Name = req.Name
-	}
-	if req.Email != "" {
-		user.Email = req.Email
-	}
-	if req.Phone != "" {
-		user.Phone = req.Phone
-	}
-	if req.Password != "" {
-		user.Password = req.Password
-	}
-	if req.IsAdmin != nil {
-		user.IsAdmin = *req.IsAdmin
+	// Only fields present in the request are updated
+	changes := domain.UserUpdate{
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Password: req.Password,
+		IsAdmin:  req.IsAdmin,
 	}
 
-	err = h.userUC.Update(user)
+	err = h.userUC.Update(uint(id), changes)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -122,32 +128,245 @@ func (h *UserHandler) UpdateUserProfile(c *gin.Context) {
 		return
 	}
 
-	user := &domain.User{ID: userID.(uint)}
+	// IsAdmin is ignored here: roles are only changed through the admin user endpoints
+	changes := domain.UserUpdate{
+		Name:     req.Name,
+		Email:    req.Email,
+		Phone:    req.Phone,
+		Password: req.Password,
+	}
+
+	err := h.userUC.Update(userID.(uint), changes)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to UPDATE data",
+	})
+}
+
+// GetUsers retrieves users with pagination, search and filtering (admin only).
+func (h *UserHandler) GetUsers(c *gin.Context) {
+	var filter domain.UserFilter
 
-	if req.Name != "" {
-		user.Name = req.Name
+	// Parse pagination parameters
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
 	}
-	if req.Email != "" {
-		user.Email = req.Email
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+
+	filter.SetDefaults() // Apply default page and limit if not set
+
+	// Parse filtering parameters
+	filter.Search = c.Query("search")
+
+	filter.Role = c.Query("role")
+	if filter.Role != "" && filter.Role != domain.UserRoleAdmin && filter.Role != domain.UserRoleUser {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, must be admin or user"})
+		return
 	}
-	if req.Phone != "" {
-		user.Phone = req.Phone
+
+	filter.Status = c.Query("status")
+	if filter.Status != "" && filter.Status != domain.UserStatusActive && filter.Status != domain.UserStatusSuspended {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, must be active or suspended"})
+		return
 	}
-	if req.Password != "" {
-		user.Password = req.Password
+
+	if fromStr := c.Query("created_from"); fromStr != "" {
+		if from, err := time.ParseInLocation("2006-01-02", fromStr, time.Local); err == nil {
+			filter.CreatedFrom = &from
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid created_from format, use YYYY-MM-DD"})
+			return
+		}
 	}
-	if req.IsAdmin != nil {
-		user.IsAdmin = *req.IsAdmin
+
+	if toStr := c.Query("created_to"); toStr != "" {
+		if to, err := time.ParseInLocation("2006-01-02", toStr, time.Local); err == nil {
+			to = to.AddDate(0, 0, 1) // Include the whole end day
+			filter.CreatedTo = &to
+		} else {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid created_to format, use YYYY-MM-DD"})
+			return
+		}
 	}
 
-	err := h.userUC.Update(user)
+	users, totalCount, err := h.userUC.GetUsers(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	totalPages := 0
+	if filter.Limit > 0 {
+		totalPages = int((totalCount + int64(filter.Limit) - 1) / int64(filter.Limit))
+	}
+
+	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Data: PaginatedUserResponse{
+			Users:      users,
+			Page:       filter.Page,
+			Limit:      filter.Limit,
+			TotalCount: totalCount,
+			TotalPages: totalPages,
+		},
+	})
+}
+
+// GetUserStores retrieves the stores owned by a user (admin only).
+func (h *UserHandler) GetUserStores(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	stores, err := h.userUC.GetUserStores(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"status":  true,
-		"message": "Succeed to UPDATE data",
+		"message": "Succeed to GET data",
+		"data":    stores,
 	})
 }
+
+// GetUserTransactions retrieves the orders placed by a user (admin only).
+func (h *UserHandler) GetUserTransactions(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	filter := domain.TransactionFilter{UserID: uint(id)}
+
+	// Parse pagination parameters
+	if pageStr := c.Query("page"); pageStr != "" {
+		if page, err := strconv.Atoi(pageStr); err == nil {
+			filter.Page = page
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			filter.Limit = limit
+		}
+	}
+	filter.SetDefaults() // Apply default page and limit if not set
+	if !bindPageMode(c, &filter.Cursor, &filter.SkipCount) {
+		return
+	}
+
+	filter.Status = c.Query("status")
+
+	transactions, page, err := h.userUC.GetUserTransactions(filter)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	totalCount, totalPages := pageTotals(page, filter.Limit)
+
+	c.JSON(http.StatusOK, domain.StandardPaginatedResponse{
+		Status:  true,
+		Message: "Succeed to GET data",
+		Data: PaginatedTransactionResponse{
+			Transactions: transactions,
+			Page:         filter.Page,
+			Limit:        filter.Limit,
+			TotalCount:   totalCount,
+			TotalPages:   totalPages,
+			NextCursor:   page.NextCursor,
+			PrevCursor:   page.PrevCursor,
+		},
+	})
+}
+
+// SuspendUser suspends a user account (admin only).
+func (h *UserHandler) SuspendUser(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var req SuspendUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userUC.Suspend(adminID.(uint), uint(id), req.Reason); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User suspended successfully"})
+}
+
+// UnsuspendUser lifts a user's suspension (admin only).
+func (h *UserHandler) UnsuspendUser(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.userUC.Unsuspend(uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User unsuspended successfully"})
+}
+
+// DeleteUser anonymizes and soft-deletes a user (admin only).
+func (h *UserHandler) DeleteUser(c *gin.Context) {
+	adminID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	if err := h.userUC.Delete(adminID.(uint), uint(id)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "User deleted successfully"})
+}
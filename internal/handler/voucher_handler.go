@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoucherHandler struct {
+	voucherUC usecase.VoucherUseCase
+}
+
+func NewVoucherHandler(voucherUC usecase.VoucherUseCase) *VoucherHandler {
+	return &VoucherHandler{voucherUC: voucherUC}
+}
+
+type CreateVoucherRequest struct {
+	Code         string    `json:"code" binding:"required,alphanum,min=3,max=50"` // Stored in upper case
+	Type         string    `json:"type" binding:"required,oneof=fixed percentage free_shipping"`
+	Value        float64   `json:"value" binding:"gte=0"` // Amount, or percentage off; unused for free shipping
+	MinSpend     float64   `json:"min_spend" binding:"gte=0"`
+	MaxDiscount  float64   `json:"max_discount" binding:"gte=0"`             // 0 for no cap
+	UsageLimit   int       `json:"usage_limit" binding:"gte=0"`              // 0 for no limit
+	PerUserLimit *int      `json:"per_user_limit" binding:"omitempty,gte=0"` // Defaults to 1; 0 for no limit
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	EndsAt       time.Time `json:"ends_at" binding:"required"`
+}
+
+type PreviewVoucherRequest struct {
+	Code         string                         `json:"code" binding:"required,max=50"`
+	ShippingCost float64                        `json:"shipping_cost" binding:"gte=0"`
+	Items        []CreateTransactionItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+func (req *CreateVoucherRequest) voucher() *domain.Voucher {
+	perUserLimit := 1
+	if req.PerUserLimit != nil {
+		perUserLimit = *req.PerUserLimit
+	}
+	return &domain.Voucher{
+		Code:         req.Code,
+		Type:         req.Type,
+		Value:        req.Value,
+		MinSpend:     req.MinSpend,
+		MaxDiscount:  req.MaxDiscount,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: perUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+	}
+}
+
+// CreateStoreVoucher creates a voucher for the items of a store.
+func (h *VoucherHandler) CreateStoreVoucher(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	var req CreateVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	voucher := req.voucher()
+	id := uint(storeID)
+	voucher.StoreID = &id
+	if err := h.voucherUC.CreateStoreVoucher(voucher, userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  true,
+		"message": "Voucher created",
+		"data":    voucher,
+	})
+}
+
+// GetStoreVouchers lists the vouchers of a store.
+func (h *VoucherHandler) GetStoreVouchers(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+
+	vouchers, err := h.voucherUC.GetStoreVouchers(uint(storeID), userID.(uint))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    vouchers,
+	})
+}
+
+// EndStoreVoucher stops a voucher of a store from being used.
+func (h *VoucherHandler) EndStoreVoucher(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	storeID, err := strconv.ParseUint(c.Param("id_toko"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid store ID"})
+		return
+	}
+	voucherID, err := strconv.ParseUint(c.Param("voucher_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid voucher ID"})
+		return
+	}
+
+	if err := h.voucherUC.EndStoreVoucher(uint(storeID), uint(voucherID), userID.(uint)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Voucher ended successfully"})
+}
+
+// CreatePlatformVoucher creates a voucher for orders from any store (admin only).
+func (h *VoucherHandler) CreatePlatformVoucher(c *gin.Context) {
+	var req CreateVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	voucher := req.voucher()
+	if err := h.voucherUC.CreatePlatformVoucher(voucher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  true,
+		"message": "Voucher created",
+		"data":    voucher,
+	})
+}
+
+// GetPlatformVouchers lists the platform vouchers (admin only).
+func (h *VoucherHandler) GetPlatformVouchers(c *gin.Context) {
+	vouchers, err := h.voucherUC.GetPlatformVouchers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    vouchers,
+	})
+}
+
+// EndPlatformVoucher stops a platform voucher from being used (admin only).
+func (h *VoucherHandler) EndPlatformVoucher(c *gin.Context) {
+	voucherID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid voucher ID"})
+		return
+	}
+
+	if err := h.voucherUC.EndPlatformVoucher(uint(voucherID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Voucher ended successfully"})
+}
+
+// PreviewVoucher shows the discount a voucher gives on a proposed order, without using it.
+func (h *VoucherHandler) PreviewVoucher(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req PreviewVoucherRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]domain.TransactionItem, 0, len(req.Items))
+	for _, itemReq := range req.Items {
+		items = append(items, domain.TransactionItem{
+			ProductID: itemReq.ProductID,
+			VariantID: itemReq.VariantID,
+			Quantity:  itemReq.Quantity,
+		})
+	}
+
+	quote, err := h.voucherUC.Preview(req.Code, userID.(uint), items, req.ShippingCost)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  true,
+		"message": "Succeed to GET data",
+		"data":    quote,
+	})
+}
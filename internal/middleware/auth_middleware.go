@@ -44,14 +44,23 @@ func (m *AuthMiddleware) ValidateToken() gin.HandlerFunc {
 			return
 		}
 
+		if user.SuspendedAt != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", claims.UserID)
-		c.Set("is_admin", claims.IsAdmin) // <- harus snake_case
+		c.Set("is_admin", user.IsAdmin) // <- harus snake_case; from the database, as the role may have changed since login
+		c.Set("two_factor", claims.TwoFactor)
 		c.Set("user", user)
 
 		c.Next()
 	}
 }
 
+// RequireAdmin allows only admins who logged in with a second factor.
+// Two-factor authentication is mandatory for admin accounts.
 func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		isAdmin, exists := c.Get("is_admin")
@@ -60,6 +69,11 @@ func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+		if twoFactor, _ := c.Get("two_factor"); twoFactor != true {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Two-factor authentication is required for admin accounts"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader is the request header carrying the client's idempotency key.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// maxIdempotencyKeyLength bounds the keys clients may send.
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyPolicy names a group of routes sharing idempotency keys, and how long
+// their responses are kept for retries.
+type IdempotencyPolicy struct {
+	Name string
+	TTL  time.Duration
+}
+
+type IdempotencyMiddleware struct {
+	store IdempotencyStore
+}
+
+func NewIdempotencyMiddleware(store IdempotencyStore) *IdempotencyMiddleware {
+	return &IdempotencyMiddleware{store: store}
+}
+
+// idempotencyWriter copies the response body so it can be stored for replays.
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotent makes retries of a request with the same Idempotency-Key header safe: the first
+// response is stored for the policy TTL and replayed to later requests with the key, which
+// get an Idempotent-Replayed header. Reusing a key for a different request is rejected.
+// Keys are scoped per user; requests without the header are handled as usual.
+func (m *IdempotencyMiddleware) Idempotent(policy IdempotencyPolicy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(IdempotencyKeyHeader)
+		if idempotencyKey == "" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256([]byte(c.Request.Method + " " + c.FullPath() + "\n" + string(body)))
+		fingerprint := hex.EncodeToString(sum[:])
+		key := "idempotency:" + policy.Name + ":" + RateLimitByUser(c) + ":" + idempotencyKey
+
+		record, err := m.store.Begin(c.Request.Context(), key, fingerprint, policy.TTL)
+		if err != nil {
+			// Fail open like the rate limiter: an unavailable store must not take the API down with it
+			log.Printf("idempotency store error for %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		if record != nil {
+			switch {
+			case record.Fingerprint != fingerprint:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+			case !record.Completed:
+				c.JSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still in progress"})
+			default:
+				c.Header("Idempotent-Replayed", "true")
+				c.Data(record.Status, record.ContentType, record.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		// A panicking handler must not leave the key stuck in progress; the panic is
+		// passed on to the recovery middleware
+		defer func() {
+			if r := recover(); r != nil {
+				m.release(c, key)
+				panic(r)
+			}
+		}()
+		c.Next()
+
+		// Server errors are not kept, so the request can be retried with the same key
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			m.release(c, key)
+			return
+		}
+
+		completed := IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Completed:   true,
+			Status:      status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}
+		if err := m.store.Complete(c.Request.Context(), key, completed, policy.TTL); err != nil {
+			log.Printf("idempotency store error for %s: %v", key, err)
+		}
+	}
+}
+
+// release frees a key so the request can be retried with it.
+func (m *IdempotencyMiddleware) release(c *gin.Context, key string) {
+	if err := m.store.Release(c.Request.Context(), key); err != nil {
+		log.Printf("idempotency store error for %s: %v", key, err)
+	}
+}
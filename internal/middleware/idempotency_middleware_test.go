@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newIdempotentRouter serves POST /orders behind the middleware with the given handler; the
+// X-User header stands in for ValidateToken.
+func newIdempotentRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	}))
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			id, _ := strconv.Atoi(user)
+			c.Set("user_id", uint(id))
+		}
+	})
+	policy := IdempotencyPolicy{Name: "test", TTL: time.Hour}
+	r.POST("/orders", NewIdempotencyMiddleware(NewMemoryIdempotencyStore()).Idempotent(policy), handler)
+	return r
+}
+
+func postOrder(r *gin.Engine, user, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("X-User", user)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// countingHandler creates an order per call and reports its number.
+func countingHandler(calls *int32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		n := atomic.AddInt32(calls, 1)
+		c.JSON(http.StatusCreated, gin.H{"order": n})
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	t.Run("a retry replays the first response", func(t *testing.T) {
+		var calls int32
+		r := newIdempotentRouter(countingHandler(&calls))
+		first := postOrder(r, "1", "key-1", `{"items":[1]}`)
+		retry := postOrder(r, "1", "key-1", `{"items":[1]}`)
+
+		if calls != 1 {
+			t.Fatalf("handler ran %d times, want 1", calls)
+		}
+		if retry.Code != first.Code || retry.Body.String() != first.Body.String() {
+			t.Fatalf("retry got %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+		}
+		if retry.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatal("retry is missing the Idempotent-Replayed header")
+		}
+	})
+
+	t.Run("a key reused for another request is rejected", func(t *testing.T) {
+		var calls int32
+		r := newIdempotentRouter(countingHandler(&calls))
+		postOrder(r, "1", "key-1", `{"items":[1]}`)
+		if w := postOrder(r, "1", "key-1", `{"items":[2]}`); w.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("reused key: status %d, want 422", w.Code)
+		}
+		if calls != 1 {
+			t.Fatalf("handler ran %d times, want 1", calls)
+		}
+	})
+
+	t.Run("keys are scoped per user", func(t *testing.T) {
+		var calls int32
+		r := newIdempotentRouter(countingHandler(&calls))
+		postOrder(r, "1", "key-1", `{"items":[1]}`)
+		if w := postOrder(r, "2", "key-1", `{"items":[1]}`); w.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatal("another user got the response of the first user")
+		}
+		if calls != 2 {
+			t.Fatalf("handler ran %d times, want 2", calls)
+		}
+	})
+
+	t.Run("a request in progress blocks its retries", func(t *testing.T) {
+		started, finish := make(chan struct{}), make(chan struct{})
+		r := newIdempotentRouter(func(c *gin.Context) {
+			close(started)
+			<-finish
+			c.Status(http.StatusCreated)
+		})
+		done := make(chan *httptest.ResponseRecorder)
+		go func() { done <- postOrder(r, "1", "key-1", `{}`) }()
+		<-started
+
+		if w := postOrder(r, "1", "key-1", `{}`); w.Code != http.StatusConflict {
+			t.Fatalf("retry during the first request: status %d, want 409", w.Code)
+		}
+		close(finish)
+		if w := <-done; w.Code != http.StatusCreated {
+			t.Fatalf("first request: status %d, want 201", w.Code)
+		}
+	})
+
+	t.Run("server errors and panics free the key", func(t *testing.T) {
+		var calls int32
+		r := newIdempotentRouter(func(c *gin.Context) {
+			switch atomic.AddInt32(&calls, 1) {
+			case 1:
+				c.Status(http.StatusInternalServerError)
+			case 2:
+				panic("handler failed")
+			default:
+				c.Status(http.StatusCreated)
+			}
+		})
+		for _, want := range []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusCreated} {
+			if w := postOrder(r, "1", "key-1", `{}`); w.Code != want {
+				t.Fatalf("call %d: status %d, want %d", calls, w.Code, want)
+			}
+		}
+	})
+
+	t.Run("requests without a key are not deduplicated", func(t *testing.T) {
+		var calls int32
+		r := newIdempotentRouter(countingHandler(&calls))
+		postOrder(r, "1", "", `{}`)
+		postOrder(r, "1", "", `{}`)
+		if calls != 2 {
+			t.Fatalf("handler ran %d times, want 2", calls)
+		}
+	})
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IdempotencyRecord is what is kept for an idempotency key: the fingerprint of the request
+// that first used it and, once that request has finished, its response.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Completed   bool   `json:"completed"` // False while the first request is in progress
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// IdempotencyStore keeps idempotency records for IdempotencyMiddleware.
+type IdempotencyStore interface {
+	// Begin claims key for a request with the given fingerprint. It returns nil when the key
+	// was free, or the existing record when the key has been used already.
+	Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error)
+	// Complete stores the response of the request that claimed key.
+	Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error
+	// Release frees key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+// ------------------------
+// IN-MEMORY STORE
+// ------------------------
+
+type memoryIdempotencyEntry struct {
+	record    IdempotencyRecord
+	expiresAt time.Time
+}
+
+// MemoryIdempotencyStore keeps records in process memory. It is suitable for a
+// single instance; use RedisIdempotencyStore when running several replicas.
+type MemoryIdempotencyStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]*memoryIdempotencyEntry), lastSweep: time.Now()}
+}
+
+func (s *MemoryIdempotencyStore) Begin(_ context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		record := e.record
+		return &record, nil
+	}
+	s.entries[key] = &memoryIdempotencyEntry{
+		record:    IdempotencyRecord{Fingerprint: fingerprint},
+		expiresAt: now.Add(ttl),
+	}
+	return nil, nil
+}
+
+func (s *MemoryIdempotencyStore) Complete(_ context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = &memoryIdempotencyEntry{record: record, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// sweep drops expired records.
+func (s *MemoryIdempotencyStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	for key, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// ------------------------
+// REDIS-COMPATIBLE STORE
+// ------------------------
+
+// idempotencyBeginScript sets the record unless the key exists, returning the existing one.
+// KEYS[1] = record key, ARGV = record JSON, ttl in ms.
+const idempotencyBeginScript = `
+local existing = redis.call('GET', KEYS[1])
+if existing then
+  return existing
+end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return ''
+`
+
+// idempotencySetScript overwrites the record. KEYS[1] = record key, ARGV = record JSON, ttl in ms.
+const idempotencySetScript = `
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`
+
+// idempotencyDeleteScript removes the record. KEYS[1] = record key.
+const idempotencyDeleteScript = `
+return redis.call('DEL', KEYS[1])
+`
+
+// RedisIdempotencyStore keeps records in a Redis-compatible server so keys are
+// shared between API instances.
+type RedisIdempotencyStore struct {
+	client RedisScripter
+}
+
+func NewRedisIdempotencyStore(client RedisScripter) *RedisIdempotencyStore {
+	return &RedisIdempotencyStore{client: client}
+}
+
+func (s *RedisIdempotencyStore) Begin(ctx context.Context, key, fingerprint string, ttl time.Duration) (*IdempotencyRecord, error) {
+	value, err := json.Marshal(IdempotencyRecord{Fingerprint: fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := s.client.Eval(ctx, idempotencyBeginScript, []string{key}, string(value), ttl.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	existing, ok := reply.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected idempotency script reply: %v", reply)
+	}
+	if existing == "" {
+		return nil, nil
+	}
+
+	var record IdempotencyRecord
+	if err := json.Unmarshal([]byte(existing), &record); err != nil {
+		return nil, fmt.Errorf("unexpected idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+func (s *RedisIdempotencyStore) Complete(ctx context.Context, key string, record IdempotencyRecord, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Eval(ctx, idempotencySetScript, []string{key}, string(value), ttl.Milliseconds())
+	return err
+}
+
+func (s *RedisIdempotencyStore) Release(ctx context.Context, key string) error {
+	_, err := s.client.Eval(ctx, idempotencyDeleteScript, []string{key})
+	return err
+}
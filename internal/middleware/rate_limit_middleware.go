@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimitKeyFunc derives the bucket key for a request.
+type RateLimitKeyFunc func(c *gin.Context) string
+
+// RateLimitByIP keys requests by client IP address.
+func RateLimitByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// RateLimitByUser keys requests by the authenticated user_id set by ValidateToken,
+// falling back to the client IP address for anonymous requests.
+func RateLimitByUser(c *gin.Context) string {
+	if userID, exists := c.Get("user_id"); exists {
+		return fmt.Sprintf("user:%v", userID)
+	}
+	return RateLimitByIP(c)
+}
+
+// RateLimitPolicy describes a token bucket: Limit requests may be made in a burst,
+// and the bucket refills completely over Window.
+type RateLimitPolicy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	KeyBy  RateLimitKeyFunc
+}
+
+// Validate checks that the policy allows at least one request over a positive window.
+func (p RateLimitPolicy) Validate() error {
+	if p.Limit <= 0 {
+		return fmt.Errorf("rate limit policy %q: limit must be positive, got %d", p.Name, p.Limit)
+	}
+	if p.Window <= 0 {
+		return fmt.Errorf("rate limit policy %q: window must be positive, got %s", p.Name, p.Window)
+	}
+	return nil
+}
+
+// RateLimitResult is the outcome of taking a token from a bucket.
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int
+	ResetAfter time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until the next token is available, zero if allowed
+}
+
+type RateLimitMiddleware struct {
+	store RateLimitStore
+}
+
+func NewRateLimitMiddleware(store RateLimitStore) *RateLimitMiddleware {
+	return &RateLimitMiddleware{store: store}
+}
+
+// Limit enforces the policy and sets the RateLimit-* headers on every response,
+// plus Retry-After when the request is rejected. It panics when the policy is invalid,
+// so a misconfiguration stops the server at startup.
+func (m *RateLimitMiddleware) Limit(policy RateLimitPolicy) gin.HandlerFunc {
+	if err := policy.Validate(); err != nil {
+		panic(err)
+	}
+	keyBy := policy.KeyBy
+	if keyBy == nil {
+		keyBy = RateLimitByIP
+	}
+
+	return func(c *gin.Context) {
+		key := "ratelimit:" + policy.Name + ":" + keyBy(c)
+		result, err := m.store.Take(c.Request.Context(), key, policy)
+		if err != nil {
+			// Fail open: an unavailable store must not take the API down with it
+			log.Printf("rate limit store error for %s: %v", key, err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Limit, int(policy.Window.Seconds())))
+		c.Header("RateLimit-Limit", strconv.Itoa(policy.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
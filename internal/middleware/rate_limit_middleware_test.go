@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(context.Context, string, RateLimitPolicy) (RateLimitResult, error) {
+	return RateLimitResult{}, errors.New("store unavailable")
+}
+
+// newRateLimitedRouter serves GET /limited behind the policy; the X-User header stands in for ValidateToken.
+func newRateLimitedRouter(store RateLimitStore, policy RateLimitPolicy) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			id, _ := strconv.Atoi(user)
+			c.Set("user_id", uint(id))
+		}
+	})
+	r.GET("/limited", NewRateLimitMiddleware(store).Limit(policy), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func getLimited(r *gin.Engine, user string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	if user != "" {
+		req.Header.Set("X-User", user)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	policy := RateLimitPolicy{Name: "test", Limit: 3, Window: time.Hour, KeyBy: RateLimitByUser}
+
+	t.Run("requests over the limit are rejected with Retry-After", func(t *testing.T) {
+		r := newRateLimitedRouter(NewMemoryRateLimitStore(), policy)
+		for i := 0; i < policy.Limit; i++ {
+			w := getLimited(r, "1")
+			if w.Code != http.StatusOK {
+				t.Fatalf("request %d: status %d, want 200", i+1, w.Code)
+			}
+			if remaining := w.Header().Get("RateLimit-Remaining"); remaining != strconv.Itoa(policy.Limit-i-1) {
+				t.Fatalf("request %d: RateLimit-Remaining %q, want %d", i+1, remaining, policy.Limit-i-1)
+			}
+		}
+
+		w := getLimited(r, "1")
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("request over the limit: status %d, want 429", w.Code)
+		}
+		if retryAfter, _ := strconv.Atoi(w.Header().Get("Retry-After")); retryAfter <= 0 {
+			t.Fatalf("Retry-After = %q, want a positive number of seconds", w.Header().Get("Retry-After"))
+		}
+	})
+
+	t.Run("each user has a bucket of their own", func(t *testing.T) {
+		r := newRateLimitedRouter(NewMemoryRateLimitStore(), policy)
+		for i := 0; i < policy.Limit; i++ {
+			getLimited(r, "1")
+		}
+		if w := getLimited(r, "2"); w.Code != http.StatusOK {
+			t.Fatalf("another user: status %d, want 200", w.Code)
+		}
+		if w := getLimited(r, ""); w.Code != http.StatusOK {
+			t.Fatalf("anonymous request: status %d, want 200", w.Code)
+		}
+	})
+
+	t.Run("an unavailable store lets requests through", func(t *testing.T) {
+		r := newRateLimitedRouter(failingRateLimitStore{}, policy)
+		for i := 0; i <= policy.Limit; i++ {
+			if w := getLimited(r, "1"); w.Code != http.StatusOK {
+				t.Fatalf("request %d: status %d, want 200", i+1, w.Code)
+			}
+		}
+	})
+
+	t.Run("an invalid policy panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("Limit accepted a policy without a limit")
+			}
+		}()
+		NewRateLimitMiddleware(NewMemoryRateLimitStore()).Limit(RateLimitPolicy{Name: "invalid", Window: time.Minute})
+	})
+}
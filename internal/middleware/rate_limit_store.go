@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitStore keeps token bucket state for RateLimitMiddleware.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error)
+}
+
+// bucketResult computes the result for a bucket holding tokens after a take attempt.
+func bucketResult(allowed bool, tokens float64, policy RateLimitPolicy) RateLimitResult {
+	perToken := policy.Window / time.Duration(policy.Limit)
+	result := RateLimitResult{
+		Allowed:    allowed,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: time.Duration((float64(policy.Limit) - tokens) * float64(perToken)),
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration((1 - tokens) * float64(perToken))
+	}
+	return result
+}
+
+// ------------------------
+// IN-MEMORY STORE
+// ------------------------
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	window    time.Duration
+}
+
+// MemoryRateLimitStore keeps buckets in process memory. It is suitable for a
+// single instance; use RedisRateLimitStore when running several replicas.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{buckets: make(map[string]*memoryBucket), lastSweep: time.Now()}
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.sweep(now)
+
+	capacity := float64(policy.Limit)
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: capacity, updatedAt: now, window: policy.Window}
+		s.buckets[key] = b
+	}
+
+	rate := capacity / float64(policy.Window)
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updatedAt))*rate)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+	return bucketResult(allowed, b.tokens, policy), nil
+}
+
+// sweep drops buckets that have been idle long enough to be full again.
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.Sub(b.updatedAt) > b.window {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// ------------------------
+// REDIS-COMPATIBLE STORE
+// ------------------------
+
+// RedisScripter is the subset of a Redis client needed by RedisRateLimitStore.
+// Any Redis-compatible server supporting EVAL works; wrap your client of choice
+// (e.g. go-redis: client.Eval(ctx, script, keys, args...).Result()).
+type RedisScripter interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// tokenBucketScript refills and takes from a bucket atomically.
+// KEYS[1] = bucket key, ARGV = capacity, refill rate per ms, now in ms, ttl in ms.
+const tokenBucketScript = `
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local ttl = tonumber(ARGV[4])
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], ttl)
+return {allowed, tostring(tokens)}
+`
+
+// RedisRateLimitStore keeps buckets in a Redis-compatible server so limits are
+// shared between API instances.
+type RedisRateLimitStore struct {
+	client RedisScripter
+}
+
+func NewRedisRateLimitStore(client RedisScripter) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, policy RateLimitPolicy) (RateLimitResult, error) {
+	capacity := float64(policy.Limit)
+	ratePerMs := capacity / float64(policy.Window.Milliseconds())
+	now := time.Now().UnixMilli()
+
+	reply, err := s.client.Eval(ctx, tokenBucketScript, []string{key},
+		policy.Limit, strconv.FormatFloat(ratePerMs, 'f', -1, 64), now, policy.Window.Milliseconds())
+	if err != nil {
+		return RateLimitResult{}, err
+	}
+
+	values, ok := reply.([]interface{})
+	if !ok || len(values) != 2 {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script reply: %v", reply)
+	}
+	allowed, ok := values[0].(int64)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script reply: %v", reply)
+	}
+	tokensStr, ok := values[1].(string)
+	if !ok {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script reply: %v", reply)
+	}
+	tokens, err := strconv.ParseFloat(tokensStr, 64)
+	if err != nil {
+		return RateLimitResult{}, fmt.Errorf("unexpected rate limit script reply: %w", err)
+	}
+
+	return bucketResult(allowed == 1, tokens, policy), nil
+}
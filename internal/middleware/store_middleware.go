@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StoreHeader selects which of the user's stores a seller request acts on.
+const StoreHeader = "X-Store-ID"
+
+type StoreMiddleware struct {
+	storeRepo repository.StoreRepository
+	staffRepo repository.StoreStaffRepository
+}
+
+func NewStoreMiddleware(storeRepo repository.StoreRepository, staffRepo repository.StoreStaffRepository) *StoreMiddleware {
+	return &StoreMiddleware{storeRepo: storeRepo, staffRepo: staffRepo}
+}
+
+// ResolveStore sets "store_id" in the context to the active store of the
+// authenticated user: the store named by the X-Store-ID header, or the user's
+// only store when they own exactly one. Active staff may select the stores they
+// work at; their individual permissions are enforced by the usecases. Must run
+// after ValidateToken.
+func (m *StoreMiddleware) ResolveStore() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("user_id")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			c.Abort()
+			return
+		}
+
+		if header := c.GetHeader(StoreHeader); header != "" {
+			storeID, err := strconv.ParseUint(header, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + StoreHeader + " header"})
+				c.Abort()
+				return
+			}
+
+			store, err := m.storeRepo.FindByID(uint(storeID))
+			if err != nil || (store.UserID != userID.(uint) && !m.isActiveStaff(store.ID, userID.(uint))) {
+				c.JSON(http.StatusForbidden, gin.H{"error": "You do not have access to this store"})
+				c.Abort()
+				return
+			}
+
+			c.Set("store_id", store.ID)
+			c.Next()
+			return
+		}
+
+		stores, err := m.storeRepo.FindByUserID(userID.(uint))
+		if err == nil && len(stores) == 1 {
+			c.Set("store_id", stores[0].ID)
+		}
+
+		c.Next()
+	}
+}
+
+func (m *StoreMiddleware) isActiveStaff(storeID, userID uint) bool {
+	staff, err := m.staffRepo.FindByStoreAndUser(storeID, userID)
+	return err == nil && staff.Status == domain.StoreStaffStatusActive
+}
@@ -12,7 +12,11 @@ type CategoryRepository interface {
 	FindByID(id uint) (*domain.Category, error)
 	Update(category *domain.Category) error
 	Delete(id uint) error
-	FindAll(filter domain.CategoryFilter) ([]domain.Category, int64, error)
+	FindAll(filter domain.CategoryFilter) ([]domain.Category, domain.PageInfo, error)
+	ListAll() ([]domain.Category, error)
+	NameExists(name string, parentID *uint, excludeID uint) (bool, error)
+	CountChildren(id uint) (int64, error)
+	CountProducts(id uint) (int64, error)
 }
 
 // categoryRepository implements the CategoryRepository interface.
@@ -48,7 +52,7 @@ func (r *categoryRepository) Delete(id uint) error {
 }
 
 // FindAll retrieves all categories from the database with pagination and filtering.
-func (r *categoryRepository) FindAll(filter domain.CategoryFilter) ([]domain.Category, int64, error) {
+func (r *categoryRepository) FindAll(filter domain.CategoryFilter) ([]domain.Category, domain.PageInfo, error) {
 	var categories []domain.Category
 	query := r.db.Model(&domain.Category{})
 
@@ -58,16 +62,50 @@ func (r *categoryRepository) FindAll(filter domain.CategoryFilter) ([]domain.Cat
 		query = query.Where("name LIKE ? OR description LIKE ?", searchPattern, searchPattern)
 	}
 
-	// Get total count before applying pagination
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
+	// Apply pagination, oldest categories first
+	page, err := paginate(query, r.db.Model(&domain.Category{}), []sortKey{{SQL: "categories.id"}}, pageRequest{
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		Cursor:    filter.Cursor,
+		SkipCount: filter.SkipCount,
+		Sort:      "id",
+	}, &categories, func(c domain.Category) uint { return c.ID })
+	return categories, page, err
+}
+
+// ListAll retrieves every category without pagination, e.g. to build the category tree.
+func (r *categoryRepository) ListAll() ([]domain.Category, error) {
+	var categories []domain.Category
+	err := r.db.Order("name ASC").Find(&categories).Error
+	return categories, err
+}
+
+// NameExists checks if a sibling category (same parent) already uses the name.
+func (r *categoryRepository) NameExists(name string, parentID *uint, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Model(&domain.Category{}).Where("name = ?", name)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
 	}
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
 
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.Limit
-	query = query.Limit(filter.Limit).Offset(offset)
+// CountChildren counts the direct subcategories of a category.
+func (r *categoryRepository) CountChildren(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
 
-	err := query.Find(&categories).Error
-	return categories, totalCount, err
-}
\ No newline at end of file
+// CountProducts counts the products assigned to a category.
+func (r *categoryRepository) CountProducts(id uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Product{}).Where("category_id = ?", id).Count(&count).Error
+	return count, err
+}
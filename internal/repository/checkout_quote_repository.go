@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrQuoteUsed is returned when a checkout quote has already been committed.
+var ErrQuoteUsed = errors.New("quote has already been used")
+
+// CheckoutQuoteRepository defines the interface for checkout quote data operations.
+type CheckoutQuoteRepository interface {
+	Create(quote *domain.CheckoutQuote) error
+	FindByID(id string) (*domain.CheckoutQuote, error)
+	Claim(id string) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+// checkoutQuoteRepository implements the CheckoutQuoteRepository interface.
+type checkoutQuoteRepository struct {
+	db *gorm.DB
+}
+
+// NewCheckoutQuoteRepository creates a new instance of CheckoutQuoteRepository.
+func NewCheckoutQuoteRepository(db *gorm.DB) CheckoutQuoteRepository {
+	return &checkoutQuoteRepository{db: db}
+}
+
+// Create a new checkout quote in the database.
+func (r *checkoutQuoteRepository) Create(quote *domain.CheckoutQuote) error {
+	return r.db.Create(quote).Error
+}
+
+// FindByID retrieves a checkout quote by its ID.
+func (r *checkoutQuoteRepository) FindByID(id string) (*domain.CheckoutQuote, error) {
+	var quote domain.CheckoutQuote
+	err := r.db.Where("id = ?", id).First(&quote).Error
+	return &quote, err
+}
+
+// Claim marks a quote as used in a single conditional update, so only one order can commit it.
+func (r *checkoutQuoteRepository) Claim(id string) error {
+	result := r.db.Model(&domain.CheckoutQuote{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrQuoteUsed
+	}
+	return nil
+}
+
+// DeleteExpired deletes the quotes that expired before the given time without being used,
+// returning how many were deleted. Used quotes are kept, as orders reference them.
+func (r *checkoutQuoteRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ? AND used_at IS NULL", before).Delete(&domain.CheckoutQuote{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors returned when flash sale units can't be reserved.
+var (
+	ErrFlashSaleSoldOut      = errors.New("flash sale sold out")
+	ErrFlashSaleLimitReached = errors.New("flash sale limit per buyer reached")
+)
+
+// DiscountCampaignRepository defines the interface for discount campaign data operations.
+type DiscountCampaignRepository interface {
+	Create(campaign *domain.DiscountCampaign) error
+	FindByID(id uint) (*domain.DiscountCampaign, error)
+	FindByStoreID(storeID uint) ([]domain.DiscountCampaign, error)
+	FindRunning(storeIDs []uint, now time.Time) ([]domain.DiscountCampaign, error)
+	Update(campaign *domain.DiscountCampaign) error
+	Delete(id uint) error
+	ReserveFlashSale(id, userID uint, quantity int) error
+	CountUserReserved(campaignID, userID uint) (int, error)
+}
+
+// discountCampaignRepository implements the DiscountCampaignRepository interface.
+type discountCampaignRepository struct {
+	db *gorm.DB
+}
+
+// NewDiscountCampaignRepository creates a new instance of DiscountCampaignRepository.
+func NewDiscountCampaignRepository(db *gorm.DB) DiscountCampaignRepository {
+	return &discountCampaignRepository{db: db}
+}
+
+// Create a new discount campaign in the database.
+func (r *discountCampaignRepository) Create(campaign *domain.DiscountCampaign) error {
+	return r.db.Create(campaign).Error
+}
+
+// FindByID retrieves a discount campaign by its ID.
+func (r *discountCampaignRepository) FindByID(id uint) (*domain.DiscountCampaign, error) {
+	var campaign domain.DiscountCampaign
+	err := r.db.First(&campaign, id).Error
+	return &campaign, err
+}
+
+// FindByStoreID retrieves all campaigns of a store, latest start first.
+func (r *discountCampaignRepository) FindByStoreID(storeID uint) ([]domain.DiscountCampaign, error) {
+	var campaigns []domain.DiscountCampaign
+	err := r.db.Where("store_id = ?", storeID).Order("starts_at DESC, id DESC").Find(&campaigns).Error
+	return campaigns, err
+}
+
+// FindRunning retrieves the campaigns of the stores that run at the given time,
+// leaving out flash sales that are sold out.
+func (r *discountCampaignRepository) FindRunning(storeIDs []uint, now time.Time) ([]domain.DiscountCampaign, error) {
+	var campaigns []domain.DiscountCampaign
+	if len(storeIDs) == 0 {
+		return campaigns, nil
+	}
+	err := r.db.Where("store_id IN ? AND starts_at <= ? AND ends_at > ?", storeIDs, now, now).
+		Where("type <> ? OR quota = 0 OR sold_quantity < quota", domain.DiscountTypeFlashSale).
+		Order("id ASC").Find(&campaigns).Error
+	return campaigns, err
+}
+
+// Update an existing discount campaign in the database.
+func (r *discountCampaignRepository) Update(campaign *domain.DiscountCampaign) error {
+	return r.db.Omit("sold_quantity").Save(campaign).Error
+}
+
+// Delete soft-deletes a discount campaign; orders keep referencing it.
+func (r *discountCampaignRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.DiscountCampaign{}, id).Error
+}
+
+// ReserveFlashSale counts units as sold to the buyer and to the campaign in one transaction. Both
+// are conditional updates, so concurrent orders can't go over the per buyer limit or the quota.
+// ErrFlashSaleLimitReached or ErrFlashSaleSoldOut is returned when the units can't be reserved.
+func (r *discountCampaignRepository) ReserveFlashSale(id, userID uint, quantity int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		reservation := domain.FlashSaleReservation{CampaignID: id, UserID: userID}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reservation).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&domain.FlashSaleReservation{}).
+			Where("campaign_id = ? AND user_id = ? AND quantity + ? <= (SELECT per_user_limit FROM discount_campaigns WHERE id = ?)", id, userID, quantity, id).
+			Update("quantity", gorm.Expr("quantity + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrFlashSaleLimitReached
+		}
+
+		result = tx.Model(&domain.DiscountCampaign{}).
+			Where("id = ? AND (quota = 0 OR sold_quantity + ? <= quota)", id, quantity).
+			Update("sold_quantity", gorm.Expr("sold_quantity + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrFlashSaleSoldOut
+		}
+		return nil
+	})
+}
+
+// CountUserReserved returns the units of a campaign reserved by a user's orders.
+func (r *discountCampaignRepository) CountUserReserved(campaignID, userID uint) (int, error) {
+	var total int
+	err := r.db.Model(&domain.FlashSaleReservation{}).
+		Select("COALESCE(SUM(quantity), 0)").
+		Where("campaign_id = ? AND user_id = ?", campaignID, userID).
+		Scan(&total).Error
+	return total, err
+}
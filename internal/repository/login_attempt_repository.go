@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// LoginAttemptRepository defines the interface for failed login audit records.
+type LoginAttemptRepository interface {
+	Create(attempt *domain.LoginAttempt) error
+	CountByIPSince(ip string, since time.Time) (int64, error)
+	FindLatestByIP(ip string) (*domain.LoginAttempt, error)
+}
+
+// loginAttemptRepository implements the LoginAttemptRepository interface.
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository creates a new instance of LoginAttemptRepository.
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+// Create a new login attempt record in the database.
+func (r *loginAttemptRepository) Create(attempt *domain.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// CountByIPSince counts the logins from an IP address that failed on their credentials since the given time.
+func (r *loginAttemptRepository) CountByIPSince(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.LoginAttempt{}).
+		Where("ip_address = ? AND created_at >= ? AND reason IN ?", ip, since, domain.LoginCredentialFailures).
+		Count(&count).Error
+	return count, err
+}
+
+// FindLatestByIP retrieves the most recent login from an IP address that failed on its credentials.
+func (r *loginAttemptRepository) FindLatestByIP(ip string) (*domain.LoginAttempt, error) {
+	var attempt domain.LoginAttempt
+	err := r.db.Where("ip_address = ? AND reason IN ?", ip, domain.LoginCredentialFailures).Order("created_at DESC").First(&attempt).Error
+	return &attempt, err
+}
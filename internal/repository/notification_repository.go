@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// NotificationRepository defines the interface for notification data operations.
+type NotificationRepository interface {
+	Create(notification *domain.Notification) error
+	FindByUserID(userID uint, unreadOnly bool) ([]domain.Notification, error)
+	MarkRead(id, userID uint) error
+}
+
+// notificationRepository implements the NotificationRepository interface.
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository.
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// Create a new notification in the database.
+func (r *notificationRepository) Create(notification *domain.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+// Maximum number of notifications listed
+const maxNotifications = 100
+
+// FindByUserID retrieves the latest notifications of a user, newest first.
+func (r *notificationRepository) FindByUserID(userID uint, unreadOnly bool) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("id DESC").Limit(maxNotifications).Find(&notifications).Error
+	return notifications, err
+}
+
+// MarkRead marks a notification of the user as read. gorm.ErrRecordNotFound is returned
+// when the user has no such notification.
+func (r *notificationRepository) MarkRead(id, userID uint) error {
+	var notification domain.Notification
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		return err
+	}
+	if notification.ReadAt != nil {
+		return nil
+	}
+	return r.db.Model(&notification).Update("read_at", time.Now()).Error
+}
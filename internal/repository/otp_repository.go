@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrOTPClosed is returned when an OTP has been consumed or has no attempts left.
+var ErrOTPClosed = errors.New("one-time password is no longer valid")
+
+// OTPRepository defines the interface for one-time password data operations.
+type OTPRepository interface {
+	Create(otp *domain.OTP) error
+	FindActive(phone, purpose string) (*domain.OTP, error)
+	Attempt(id uint, maxAttempts int) error
+	Consume(id uint) error
+	InvalidateActive(phone, purpose string) error
+}
+
+// otpRepository implements the OTPRepository interface.
+type otpRepository struct {
+	db *gorm.DB
+}
+
+// NewOTPRepository creates a new instance of OTPRepository.
+func NewOTPRepository(db *gorm.DB) OTPRepository {
+	return &otpRepository{db: db}
+}
+
+// Create a new OTP in the database.
+func (r *otpRepository) Create(otp *domain.OTP) error {
+	return r.db.Create(otp).Error
+}
+
+// FindActive retrieves the latest unconsumed OTP for a phone and purpose.
+func (r *otpRepository) FindActive(phone, purpose string) (*domain.OTP, error) {
+	var otp domain.OTP
+	err := r.db.Where("phone = ? AND purpose = ? AND consumed_at IS NULL", phone, purpose).
+		Order("created_at DESC").First(&otp).Error
+	return &otp, err
+}
+
+// Attempt counts a verification attempt against an OTP in a single conditional update, so concurrent
+// guesses can't go over maxAttempts. ErrOTPClosed is returned when the OTP is consumed or its attempts
+// are used up.
+func (r *otpRepository) Attempt(id uint, maxAttempts int) error {
+	result := r.db.Model(&domain.OTP{}).
+		Where("id = ? AND attempts < ? AND consumed_at IS NULL", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPClosed
+	}
+	return nil
+}
+
+// Consume marks an OTP as consumed in a single conditional update, so a code can complete only
+// one login. ErrOTPClosed is returned when it was consumed already.
+func (r *otpRepository) Consume(id uint) error {
+	result := r.db.Model(&domain.OTP{}).Where("id = ? AND consumed_at IS NULL", id).Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOTPClosed
+	}
+	return nil
+}
+
+// InvalidateActive marks every unconsumed OTP for a phone and purpose as consumed.
+func (r *otpRepository) InvalidateActive(phone, purpose string) error {
+	return r.db.Model(&domain.OTP{}).
+		Where("phone = ? AND purpose = ? AND consumed_at IS NULL", phone, purpose).
+		Update("consumed_at", time.Now()).Error
+}
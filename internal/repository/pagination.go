@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortKey is one expression of a listing's sort order. The last key of a listing must be
+// its primary key, so a cursor row marks an unambiguous position.
+type sortKey struct {
+	SQL  string
+	Vars []interface{}
+	Desc bool
+}
+
+// pageRequest holds the pagination parameters shared by the list filters.
+type pageRequest struct {
+	Page      int
+	Limit     int
+	Cursor    string
+	SkipCount bool
+	Sort      string // Name of the sort order, recorded in cursors so they aren't reused with another order
+}
+
+// paginate counts the rows of query (unless skipped) and loads one page of it into dest, sorted by keys.
+// Without a cursor the page is selected by offset; with one, by seeking past the cursor row (keyset),
+// whose sort key values are read through boundary. The returned page info carries the cursors of the
+// neighbouring pages, so clients can switch from page mode to cursors at any point.
+func paginate[T any](query, boundary *gorm.DB, keys []sortKey, req pageRequest, dest *[]T, idOf func(T) uint) (domain.PageInfo, error) {
+	info := domain.PageInfo{CountSkipped: req.SkipCount}
+	if !req.SkipCount {
+		if err := query.Count(&info.TotalCount).Error; err != nil {
+			return info, err
+		}
+	}
+
+	var cursor domain.Cursor
+	if req.Cursor != "" {
+		var err error
+		cursor, err = domain.DecodeCursor(req.Cursor)
+		if err != nil {
+			return info, err
+		}
+		if cursor.Sort != req.Sort {
+			return info, fmt.Errorf("%w: it does not match the sort order", domain.ErrInvalidCursor)
+		}
+
+		values, err := boundaryValues(boundary, keys, cursor.ID)
+		if err != nil {
+			return info, err
+		}
+		query = query.Clauses(clause.Where{Exprs: []clause.Expression{keysetCondition(keys, values, cursor.Backward)}})
+	} else {
+		query = query.Offset((req.Page - 1) * req.Limit)
+	}
+
+	// One extra row tells whether there is a page beyond this one
+	err := query.Clauses(orderByKeys(keys, cursor.Backward)).Limit(req.Limit + 1).Find(dest).Error
+	if err != nil {
+		return info, err
+	}
+
+	items := *dest
+	hasMore := len(items) > req.Limit
+	if hasMore {
+		items = items[:req.Limit]
+	}
+	if cursor.Backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	*dest = items
+
+	if len(items) > 0 {
+		first := domain.Cursor{ID: idOf(items[0]), Backward: true, Sort: req.Sort}
+		last := domain.Cursor{ID: idOf(items[len(items)-1]), Sort: req.Sort}
+		if cursor.Backward {
+			// Paging backwards from a cursor, the cursor row itself follows this page
+			info.NextCursor = last.Encode()
+			if hasMore {
+				info.PrevCursor = first.Encode()
+			}
+		} else {
+			if hasMore {
+				info.NextCursor = last.Encode()
+			}
+			if req.Cursor != "" || req.Page > 1 {
+				info.PrevCursor = first.Encode()
+			}
+		}
+	}
+	return info, nil
+}
+
+// boundaryValues reads the sort key values of the cursor row, including soft-deleted rows.
+func boundaryValues(boundary *gorm.DB, keys []sortKey, id uint) ([]interface{}, error) {
+	exprs := make([]string, len(keys))
+	var vars []interface{}
+	for i, key := range keys {
+		exprs[i] = key.SQL
+		vars = append(vars, key.Vars...)
+	}
+
+	values := make([]interface{}, len(keys))
+	ptrs := make([]interface{}, len(keys))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	// The last key is the primary key
+	err := boundary.Unscoped().
+		Clauses(clause.Select{Expression: clause.Expr{SQL: strings.Join(exprs, ", "), Vars: vars, WithoutParentheses: true}}).
+		Where(keys[len(keys)-1].SQL+" = ?", id).
+		Row().Scan(ptrs...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrInvalidCursor
+	}
+	return values, err
+}
+
+// keysetCondition matches the rows sorted after the boundary values (or before them, when backward).
+func keysetCondition(keys []sortKey, values []interface{}, backward bool) clause.Expr {
+	var conditions []string
+	var vars []interface{}
+	for i, key := range keys {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, keys[j].SQL+" = ?")
+			vars = append(append(vars, keys[j].Vars...), values[j])
+		}
+		op := ">"
+		if key.Desc != backward {
+			op = "<"
+		}
+		parts = append(parts, key.SQL+" "+op+" ?")
+		vars = append(append(vars, key.Vars...), values[i])
+		conditions = append(conditions, "("+strings.Join(parts, " AND ")+")")
+	}
+	return clause.Expr{SQL: "(" + strings.Join(conditions, " OR ") + ")", Vars: vars, WithoutParentheses: true}
+}
+
+// orderByKeys sorts by the keys, reversed when paging backwards.
+func orderByKeys(keys []sortKey, backward bool) clause.OrderBy {
+	columns := make([]string, len(keys))
+	var vars []interface{}
+	for i, key := range keys {
+		columns[i] = key.SQL + " ASC"
+		if key.Desc != backward {
+			columns[i] = key.SQL + " DESC"
+		}
+		vars = append(vars, key.Vars...)
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: strings.Join(columns, ", "), Vars: vars, WithoutParentheses: true}}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ProductImportRepository defines the interface for product import job data operations.
+type ProductImportRepository interface {
+	Create(job *domain.ProductImportJob) error
+	FindByID(id uint) (*domain.ProductImportJob, error)
+	Update(job *domain.ProductImportJob) error
+	FailUnfinished(message string) error
+}
+
+// productImportRepository implements the ProductImportRepository interface.
+type productImportRepository struct {
+	db *gorm.DB
+}
+
+// NewProductImportRepository creates a new instance of ProductImportRepository.
+func NewProductImportRepository(db *gorm.DB) ProductImportRepository {
+	return &productImportRepository{db: db}
+}
+
+// Create a new import job in the database.
+func (r *productImportRepository) Create(job *domain.ProductImportJob) error {
+	return r.db.Create(job).Error
+}
+
+// FindByID retrieves an import job by its ID.
+func (r *productImportRepository) FindByID(id uint) (*domain.ProductImportJob, error) {
+	var job domain.ProductImportJob
+	err := r.db.First(&job, id).Error
+	return &job, err
+}
+
+// Update saves the progress of an import job.
+func (r *productImportRepository) Update(job *domain.ProductImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// FailUnfinished marks pending and running jobs as failed, e.g. after a restart interrupted them.
+func (r *productImportRepository) FailUnfinished(message string) error {
+	return r.db.Model(&domain.ProductImportJob{}).
+		Where("status IN ?", []string{domain.ProductImportStatusPending, domain.ProductImportStatusRunning}).
+		Updates(map[string]interface{}{"status": domain.ProductImportStatusFailed, "message": message}).Error
+}
@@ -1,20 +1,30 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"mini-project-ostore/internal/domain"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProductRepository defines the interface for product data operations.
 type ProductRepository interface {
 	Create(product *domain.Product) error
 	FindByID(id uint) (*domain.Product, error)
+	FindDetailByID(id uint) (*domain.Product, error)
+	FindByStoreID(storeID uint) ([]domain.Product, error)
 	Update(product *domain.Product) error
 	Delete(id uint) error
-	GetProducts(filter domain.ProductFilter) ([]domain.Product, int64, error)
-	SKUExists(sku string, excludeID uint) (bool, error)
+	GetProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error)
+	GetProductFacets(filter domain.ProductFilter) (*domain.ProductFacets, error)
+	SKUExists(storeID uint, sku string, excludeID uint) (bool, error)
 	SlugExists(slug string, excludeID uint) (bool, error)
+	FindIDBySlug(slug string) (uint, error)
+	RecordSlugChange(productID uint, oldSlug, newSlug string) error
 }
 
 // productRepository implements the ProductRepository interface.
@@ -39,9 +49,29 @@ func (r *productRepository) FindByID(id uint) (*domain.Product, error) {
 	return &product, err
 }
 
-// Update an existing product in the database.
+// FindDetailByID retrieves a product with its category, store settings, options and variants for the product page.
+func (r *productRepository) FindDetailByID(id uint) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.Preload("Category").Preload("Store.Settings").
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Variants").
+		First(&product, id).Error
+	return &product, err
+}
+
+// FindByStoreID retrieves all products of a store with their options and variants, oldest first, e.g. for an export.
+func (r *productRepository) FindByStoreID(storeID uint) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.db.Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Where("store_id = ?", storeID).Order("id ASC").Find(&products).Error
+	return products, err
+}
+
+// Update an existing product in the database. The stock is left out, as it only changes
+// through the inventory ledger (StockMovementRepository.Apply).
 func (r *productRepository) Update(product *domain.Product) error {
-	return r.db.Save(product).Error
+	return r.db.Omit("stock").Save(product).Error
 }
 
 // Delete a product by its ID (soft delete).
@@ -49,60 +79,212 @@ func (r *productRepository) Delete(id uint) error {
 	return r.db.Delete(&domain.Product{}, id).Error
 }
 
+// Aggregated review ratings and sold quantities per product, joined for rating and best-selling filters and sorting
+const (
+	productRatingsJoin = "LEFT JOIN (SELECT product_id, AVG(rating) AS avg_rating FROM reviews WHERE deleted_at IS NULL GROUP BY product_id) AS product_ratings ON product_ratings.product_id = products.id"
+	productSalesJoin   = "LEFT JOIN (SELECT transaction_items.product_id, SUM(transaction_items.quantity) AS sold FROM transaction_items JOIN transactions ON transactions.id = transaction_items.transaction_id AND transactions.deleted_at IS NULL WHERE transactions.status <> 'cancelled' GROUP BY transaction_items.product_id) AS product_sales ON product_sales.product_id = products.id"
+)
+
 // GetProducts retrieves products based on the provided filter.
-func (r *productRepository) GetProducts(filter domain.ProductFilter) ([]domain.Product, int64, error) {
+func (r *productRepository) GetProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error) {
 	var products []domain.Product
-	query := r.db.Model(&domain.Product{})
+	query := r.filteredQuery(filter)
+	boundary := r.db.Model(&domain.Product{})
+
+	// Apply sorting; search hits keep their relevance order unless another sort is requested.
+	// Newest first breaks ties, and the ID makes the order unique for cursors.
+	sort := filter.Sort
+	var keys []sortKey
+	switch sort {
+	case domain.ProductSortPriceAsc:
+		keys = append(keys, sortKey{SQL: "products.price"})
+	case domain.ProductSortPriceDesc:
+		keys = append(keys, sortKey{SQL: "products.price", Desc: true})
+	case domain.ProductSortBestSelling:
+		query = query.Joins(productSalesJoin)
+		boundary = boundary.Joins(productSalesJoin)
+		keys = append(keys, sortKey{SQL: "COALESCE(product_sales.sold, 0)", Desc: true})
+	case domain.ProductSortRating:
+		if filter.MinRating <= 0 {
+			query = query.Joins(productRatingsJoin)
+		}
+		boundary = boundary.Joins(productRatingsJoin)
+		keys = append(keys, sortKey{SQL: "COALESCE(product_ratings.avg_rating, 0)", Desc: true})
+	case "":
+		sort = domain.ProductSortNewest
+		if len(filter.ProductIDs) > 0 {
+			sort = "relevance"
+			keys = append(keys, sortKey{SQL: "FIELD(products.id, ?)", Vars: []interface{}{filter.ProductIDs}})
+		}
+	}
+	keys = append(keys, sortKey{SQL: "products.created_at", Desc: true}, sortKey{SQL: "products.id", Desc: true})
+
+	page, err := paginate(query, boundary, keys, pageRequest{
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		Cursor:    filter.Cursor,
+		SkipCount: filter.SkipCount,
+		Sort:      sort,
+	}, &products, func(p domain.Product) uint { return p.ID })
+	return products, page, err
+}
+
+// GetProductFacets counts the products matching the filter per category and per price bucket.
+func (r *productRepository) GetProductFacets(filter domain.ProductFilter) (*domain.ProductFacets, error) {
+	facets := &domain.ProductFacets{
+		Categories:  []domain.CategoryFacet{},
+		PriceRanges: make([]domain.PriceRangeFacet, len(domain.PriceFacetBoundaries)+1),
+	}
+
+	// Category counts ignore the category filter, so other categories can still be picked
+	categoryFilter := filter
+	categoryFilter.CategoryID = 0
+	categoryFilter.CategoryIDs = nil
+	err := r.filteredQuery(categoryFilter).
+		Select("products.category_id, categories.name, COUNT(*) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Group("products.category_id, categories.name").
+		Order("count DESC").
+		Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Price bucket counts ignore the price range filter
+	bucketSQL := "CASE"
+	bucketVars := make([]interface{}, 0, len(domain.PriceFacetBoundaries))
+	for i, boundary := range domain.PriceFacetBoundaries {
+		bucketSQL += fmt.Sprintf(" WHEN products.price < ? THEN %d", i)
+		bucketVars = append(bucketVars, boundary)
+	}
+	bucketSQL += fmt.Sprintf(" ELSE %d END", len(domain.PriceFacetBoundaries))
+
+	priceFilter := filter
+	priceFilter.MinPrice = 0
+	priceFilter.MaxPrice = 0
+	var buckets []struct {
+		Bucket int
+		Count  int64
+	}
+	err = r.filteredQuery(priceFilter).
+		Select(bucketSQL+" AS bucket, COUNT(*) AS count", bucketVars...).
+		Group("bucket").
+		Scan(&buckets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range facets.PriceRanges {
+		if i > 0 {
+			facets.PriceRanges[i].Min = domain.PriceFacetBoundaries[i-1]
+		}
+		if i < len(domain.PriceFacetBoundaries) {
+			facets.PriceRanges[i].Max = domain.PriceFacetBoundaries[i]
+		}
+	}
+	for _, bucket := range buckets {
+		if bucket.Bucket >= 0 && bucket.Bucket < len(facets.PriceRanges) {
+			facets.PriceRanges[bucket.Bucket].Count = bucket.Count
+		}
+	}
+	return facets, nil
+}
+
+// filteredQuery builds the product listing query with all conditions of the filter applied.
+func (r *productRepository) filteredQuery(filter domain.ProductFilter) *gorm.DB {
+	return productFilterQuery(r.db, filter)
+}
+
+// productFilterQuery builds a products query with all conditions of the filter applied.
+// Product searchers share it so search hits are taken from the filtered listing.
+func productFilterQuery(db *gorm.DB, filter domain.ProductFilter) *gorm.DB {
+	// Stores are always joined so products of stores that aren't public can be hidden;
+	// columns are qualified with the table name to avoid ambiguity.
+	query := db.Model(&domain.Product{}).Joins("JOIN stores ON products.store_id = stores.id AND stores.deleted_at IS NULL")
+
+	// Hide products of stores awaiting review or suspended (see domain.Store.IsPublic)
+	if !filter.IncludeUnverifiedStores {
+		query = query.Where("stores.status = ?", domain.StoreStatusVerified)
+	}
+
+	// Hide products that aren't published (yet), evaluated at query time so schedules apply by themselves
+	if !filter.IncludeUnpublished {
+		now := time.Now()
+		query = query.Where("(products.status = ? OR (products.status = ? AND products.publish_at <= ?)) AND (products.unpublish_at IS NULL OR products.unpublish_at > ?)",
+			domain.ProductStatusActive, domain.ProductStatusScheduled, now, now)
+	}
+	if filter.Status != "" {
+		query = query.Where("products.status = ?", filter.Status)
+	}
 
 	// Apply search filter
 	if filter.Search != "" {
 		searchPattern := "%" + filter.Search + "%"
-		query = query.Where("name LIKE ? OR description LIKE ? OR sku LIKE ? OR slug LIKE ?", searchPattern, searchPattern, searchPattern, searchPattern)
+		query = query.Where("products.name LIKE ? OR products.description LIKE ? OR products.sku LIKE ? OR products.slug LIKE ?", searchPattern, searchPattern, searchPattern, searchPattern)
 	}
 
-	// Apply category ID filter
-	if filter.CategoryID != 0 {
-		query = query.Where("category_id = ?", filter.CategoryID)
+	// Apply category ID filter, including subcategories when expanded
+	if len(filter.CategoryIDs) > 0 {
+		query = query.Where("products.category_id IN ?", filter.CategoryIDs)
+	} else if filter.CategoryID != 0 {
+		query = query.Where("products.category_id = ?", filter.CategoryID)
 	}
 
 	// Apply price range filter
 	if filter.MinPrice > 0 {
-		query = query.Where("price >= ?", filter.MinPrice)
+		query = query.Where("products.price >= ?", filter.MinPrice)
 	}
 	if filter.MaxPrice > 0 {
-		query = query.Where("price <= ?", filter.MaxPrice)
+		query = query.Where("products.price <= ?", filter.MaxPrice)
 	}
 
 	// Apply store ID filter
 	if filter.StoreID != 0 {
-		query = query.Where("products.store_id = ?", filter.StoreID) // Use products.store_id to avoid ambiguity if JOINs are present
+		query = query.Where("products.store_id = ?", filter.StoreID)
 	}
 
 	// Apply user ID filter (via stores)
 	if filter.UserID != 0 {
-		query = query.Joins("JOIN stores ON products.store_id = stores.id").Where("stores.user_id = ?", filter.UserID)
+		query = query.Where("stores.user_id = ?", filter.UserID)
 	}
 
-	// Get total count before applying pagination
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
+	// Restrict to search hits
+	if len(filter.ProductIDs) > 0 {
+		query = query.Where("products.id IN ?", filter.ProductIDs)
 	}
 
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.Limit
-	query = query.Limit(filter.Limit).Offset(offset)
+	// Apply stock filter
+	if filter.InStock {
+		query = query.Where("products.stock > 0")
+	}
 
-	err := query.Find(&products).Error
-	return products, totalCount, err
-}
+	// Apply availability and location filters (via the store settings)
+	if filter.Available || filter.ProvinceID != 0 || filter.CityID != 0 {
+		query = query.Joins("LEFT JOIN store_settings ON store_settings.store_id = stores.id")
+	}
+	if filter.Available {
+		query = query.Where("NOT (COALESCE(store_settings.vacation_mode, FALSE) AND (store_settings.vacation_until IS NULL OR store_settings.vacation_until > ?))", time.Now())
+	}
+	if filter.ProvinceID != 0 {
+		query = query.Where("store_settings.origin_province_id = ?", filter.ProvinceID)
+	}
+	if filter.CityID != 0 {
+		query = query.Where("store_settings.origin_city_id = ?", filter.CityID)
+	}
 
+	// Apply rating filter
+	if filter.MinRating > 0 {
+		query = query.Joins(productRatingsJoin).Where("COALESCE(product_ratings.avg_rating, 0) >= ?", filter.MinRating)
+	}
 
+	return query
+}
 
-// SKUExists checks if a product with the given SKU already exists.
-func (r *productRepository) SKUExists(sku string, excludeID uint) (bool, error) {
+// SKUExists checks if a product of the store already uses the given SKU.
+// Deleted products are included, as they still hold the SKU in the unique index.
+func (r *productRepository) SKUExists(storeID uint, sku string, excludeID uint) (bool, error) {
 	var count int64
-	query := r.db.Model(&domain.Product{}).Where("sku = ?", sku)
+	query := r.db.Unscoped().Model(&domain.Product{}).Where("store_id = ? AND sku = ?", storeID, sku)
 	if excludeID > 0 {
 		query = query.Where("id != ?", excludeID)
 	}
@@ -110,13 +292,55 @@ func (r *productRepository) SKUExists(sku string, excludeID uint) (bool, error)
 	return count > 0, err
 }
 
-// SlugExists checks if a product with the given slug already exists.
+// SlugExists checks if the slug is used by another product, either as its current slug
+// (including deleted products) or as a previous slug that still redirects to it.
 func (r *productRepository) SlugExists(slug string, excludeID uint) (bool, error) {
 	var count int64
-	query := r.db.Model(&domain.Product{}).Where("slug = ?", slug)
+	query := r.db.Unscoped().Model(&domain.Product{}).Where("slug = ?", slug)
 	if excludeID > 0 {
 		query = query.Where("id != ?", excludeID)
 	}
+	if err := query.Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	query = r.db.Model(&domain.ProductSlugHistory{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("product_id != ?", excludeID)
+	}
 	err := query.Count(&count).Error
 	return count > 0, err
-}
\ No newline at end of file
+}
+
+// FindIDBySlug resolves a slug to a product ID, by the current slug first and then by the previous slugs.
+func (r *productRepository) FindIDBySlug(slug string) (uint, error) {
+	var product domain.Product
+	err := r.db.Select("id").Where("slug = ?", slug).First(&product).Error
+	if err == nil {
+		return product.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	var history domain.ProductSlugHistory
+	if err := r.db.Where("slug = ?", slug).First(&history).Error; err != nil {
+		return 0, err
+	}
+	return history.ProductID, nil
+}
+
+// RecordSlugChange keeps the previous slug of a product for redirects. The new slug is removed
+// from the history, as it is the current slug again.
+func (r *productRepository) RecordSlugChange(productID uint, oldSlug, newSlug string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("slug = ?", newSlug).Delete(&domain.ProductSlugHistory{}).Error; err != nil {
+			return err
+		}
+		history := domain.ProductSlugHistory{ProductID: productID, Slug: oldSlug}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns([]string{"product_id", "created_at"}),
+		}).Create(&history).Error
+	})
+}
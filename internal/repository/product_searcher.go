@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"strings"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/pkg/search"
+
+	"gorm.io/gorm"
+)
+
+// ProductSearcher finds products matching the search query of a filter, best match first.
+// The other conditions of the filter are applied before limiting, so the hits are the best
+// matches within the listing. Index and Remove keep implementations with their own index
+// in sync with product changes.
+type ProductSearcher interface {
+	Search(filter domain.ProductFilter, limit int) ([]domain.ProductSearchHit, error)
+	Index(product *domain.Product) error
+	Remove(id uint) error
+}
+
+// Minimum FULLTEXT score kept, relative to the best FULLTEXT score. The ngram parser
+// matches any shared character pair, so weak matches are cut off.
+const mysqlSearchMinRelativeScore = 0.2
+
+// mysqlProductSearcher implements ProductSearcher with the FULLTEXT index on products
+// (ngram parser, so partial words and small typos still share n-grams with the text).
+type mysqlProductSearcher struct {
+	db *gorm.DB
+}
+
+// NewMySQLProductSearcher creates a ProductSearcher backed by MySQL FULLTEXT search.
+func NewMySQLProductSearcher(db *gorm.DB) ProductSearcher {
+	return &mysqlProductSearcher{db: db}
+}
+
+// Search ranks products by FULLTEXT relevance, with exact SKU matches first.
+func (s *mysqlProductSearcher) Search(filter domain.ProductFilter, limit int) ([]domain.ProductSearchHit, error) {
+	query := filter.Search
+	terms := search.Tokenize(query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+	text := strings.Join(terms, " ")
+
+	var rows []struct {
+		ID          uint
+		Name        string
+		Description string
+		Score       float64
+		SKUMatch    bool
+	}
+	err := productFilterQuery(s.db, searchScope(filter)).
+		Select("products.id, products.name, products.description, MATCH(products.name, products.description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score, products.sku = ? AS sku_match", text, query).
+		Where("MATCH(products.name, products.description) AGAINST (? IN NATURAL LANGUAGE MODE) OR products.sku = ?", text, query).
+		Order("sku_match DESC, score DESC, products.id").
+		Limit(limit).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// The cutoff is relative to the best text match; SKU matches are always kept
+	bestScore := 0.0
+	for _, row := range rows {
+		bestScore = max(bestScore, row.Score)
+	}
+
+	matcher := search.Matcher(query)
+	hits := make([]domain.ProductSearchHit, 0, len(rows))
+	for _, row := range rows {
+		if !row.SKUMatch && row.Score < bestScore*mysqlSearchMinRelativeScore {
+			continue
+		}
+		hit := domain.ProductSearchHit{ProductID: row.ID, Score: row.Score, Highlights: make(map[string]string)}
+		if name, ok := search.Highlight(row.Name, matcher); ok {
+			hit.Highlights["name"] = name
+		}
+		if description, ok := search.Highlight(row.Description, matcher); ok {
+			hit.Highlights["description"] = description
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// searchScope returns the filter without its search conditions, leaving the listing
+// conditions the hits must satisfy.
+func searchScope(filter domain.ProductFilter) domain.ProductFilter {
+	filter.Search = ""
+	filter.ProductIDs = nil
+	return filter
+}
+
+// Index is a no-op: MySQL maintains the FULLTEXT index itself.
+func (s *mysqlProductSearcher) Index(product *domain.Product) error {
+	return nil
+}
+
+// Remove is a no-op: MySQL maintains the FULLTEXT index itself.
+func (s *mysqlProductSearcher) Remove(id uint) error {
+	return nil
+}
+
+// Field weights of the in-process index
+const (
+	searchWeightName        = 3
+	searchWeightSKU         = 2
+	searchWeightDescription = 1
+)
+
+// memoryProductSearcher implements ProductSearcher with an in-process index, rebuilt
+// from the database at startup and updated on every product change.
+type memoryProductSearcher struct {
+	db    *gorm.DB
+	index *search.Index
+}
+
+// NewMemoryProductSearcher creates a ProductSearcher with an in-process index of all products.
+func NewMemoryProductSearcher(db *gorm.DB) (ProductSearcher, error) {
+	var products []domain.Product
+	if err := db.Select("id", "name", "sku", "description").Find(&products).Error; err != nil {
+		return nil, err
+	}
+
+	s := &memoryProductSearcher{db: db, index: search.NewIndex()}
+	for i := range products {
+		s.Index(&products[i])
+	}
+	return s, nil
+}
+
+// Search ranks products by BM25 relevance with prefix and typo-tolerant matching. The index
+// only holds the text, so the matches are checked against the listing conditions in the database.
+func (s *memoryProductSearcher) Search(filter domain.ProductFilter, limit int) ([]domain.ProductSearchHit, error) {
+	results := s.index.Search(filter.Search, 0)
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, 0, len(results))
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	var listed []uint
+	err := productFilterQuery(s.db, searchScope(filter)).
+		Where("products.id IN ?", ids).
+		Pluck("products.id", &listed).Error
+	if err != nil {
+		return nil, err
+	}
+	inListing := make(map[uint]bool, len(listed))
+	for _, id := range listed {
+		inListing[id] = true
+	}
+
+	hits := make([]domain.ProductSearchHit, 0, len(listed))
+	for _, result := range results {
+		if !inListing[result.ID] {
+			continue
+		}
+		if limit > 0 && len(hits) == limit {
+			break
+		}
+		hits = append(hits, domain.ProductSearchHit{ProductID: result.ID, Score: result.Score, Highlights: result.Highlights})
+	}
+	return hits, nil
+}
+
+// Index adds or replaces a product in the index.
+func (s *memoryProductSearcher) Index(product *domain.Product) error {
+	s.index.Add(product.ID,
+		search.Field{Name: "name", Text: product.Name, Weight: searchWeightName},
+		search.Field{Name: "sku", Text: product.SKU, Weight: searchWeightSKU},
+		search.Field{Name: "description", Text: product.Description, Weight: searchWeightDescription},
+	)
+	return nil
+}
+
+// Remove deletes a product from the index.
+func (s *memoryProductSearcher) Remove(id uint) error {
+	s.index.Remove(id)
+	return nil
+}
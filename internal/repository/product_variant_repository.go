@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProductVariantRepository defines the interface for product option and variant data operations.
+type ProductVariantRepository interface {
+	FindOptionsByProductID(productID uint) ([]domain.ProductOption, error)
+	FindVariantsByProductID(productID uint, withDeleted bool) ([]domain.ProductVariant, error)
+	FindVariantByID(id uint) (*domain.ProductVariant, error)
+	VariantSKUExists(storeID uint, sku string, excludeProductID uint) (bool, error)
+	ReplaceVariants(productID, actorID uint, options []domain.ProductOption, variants []domain.ProductVariant) error
+	MoveToStore(productID, storeID uint) error
+}
+
+// productVariantRepository implements the ProductVariantRepository interface.
+type productVariantRepository struct {
+	db *gorm.DB
+}
+
+// NewProductVariantRepository creates a new instance of ProductVariantRepository.
+func NewProductVariantRepository(db *gorm.DB) ProductVariantRepository {
+	return &productVariantRepository{db: db}
+}
+
+// FindOptionsByProductID retrieves the option types of a product in display order.
+func (r *productVariantRepository) FindOptionsByProductID(productID uint) ([]domain.ProductOption, error) {
+	var options []domain.ProductOption
+	err := r.db.Where("product_id = ?", productID).Order("position ASC").Find(&options).Error
+	return options, err
+}
+
+// FindVariantsByProductID retrieves the variants of a product, optionally including removed ones.
+func (r *productVariantRepository) FindVariantsByProductID(productID uint, withDeleted bool) ([]domain.ProductVariant, error) {
+	var variants []domain.ProductVariant
+	query := r.db
+	if withDeleted {
+		query = query.Unscoped()
+	}
+	err := query.Where("product_id = ?", productID).Order("id ASC").Find(&variants).Error
+	return variants, err
+}
+
+// FindVariantByID retrieves a variant by its ID.
+func (r *productVariantRepository) FindVariantByID(id uint) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := r.db.First(&variant, id).Error
+	return &variant, err
+}
+
+// VariantSKUExists checks if a variant SKU is used by another product of the store, including removed variants.
+func (r *productVariantRepository) VariantSKUExists(storeID uint, sku string, excludeProductID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&domain.ProductVariant{}).
+		Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("products.store_id = ? AND product_variants.sku = ?", storeID, sku)
+	if excludeProductID > 0 {
+		query = query.Where("product_variants.product_id != ?", excludeProductID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// MoveToStore moves the variants of a product, including removed ones, to the store the product moved to.
+func (r *productVariantRepository) MoveToStore(productID, storeID uint) error {
+	return r.db.Unscoped().Model(&domain.ProductVariant{}).Where("product_id = ?", productID).Update("store_id", storeID).Error
+}
+
+// ReplaceVariants replaces the options and variants of a product in a single transaction.
+// Variants with an ID are updated (and restored if removed), the others are created;
+// variants no longer listed are soft-deleted so past orders keep their reference.
+// The product stock is set to the total variant stock. The stock changes are recorded in the
+// inventory ledger as adjustments by the actor.
+func (r *productVariantRepository) ReplaceVariants(productID, actorID uint, options []domain.ProductOption, variants []domain.ProductVariant) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&product, productID).Error; err != nil {
+			return err
+		}
+		var current []domain.ProductVariant
+		if err := tx.Where("product_id = ?", productID).Find(&current).Error; err != nil {
+			return err
+		}
+		previousStock := make(map[uint]int, len(current))
+		for _, variant := range current {
+			previousStock[variant.ID] = variant.Stock
+		}
+
+		if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductOption{}).Error; err != nil {
+			return err
+		}
+		if len(options) > 0 {
+			if err := tx.Create(&options).Error; err != nil {
+				return err
+			}
+		}
+
+		var movements []domain.StockMovement
+		stock := product.Stock
+		record := func(variantID uint, quantity, variantStock int, reason string) {
+			stock += quantity
+			movement := domain.StockMovement{
+				ProductID:  productID,
+				Type:       domain.StockMovementAdjustment,
+				Quantity:   quantity,
+				StockAfter: stock,
+				Reason:     reason,
+				ActorID:    &actorID,
+			}
+			if variantID != 0 {
+				movement.VariantID = &variantID
+				movement.VariantStockAfter = &variantStock
+			}
+			movements = append(movements, movement)
+		}
+
+		keep := make([]uint, 0, len(variants))
+		totalStock := 0
+		for i := range variants {
+			variant := &variants[i]
+			if variant.ID != 0 {
+				variant.DeletedAt = gorm.DeletedAt{}
+				if err := tx.Unscoped().Save(variant).Error; err != nil {
+					return err
+				}
+			} else if err := tx.Create(variant).Error; err != nil {
+				return err
+			}
+			keep = append(keep, variant.ID)
+			totalStock += variant.Stock
+			if delta := variant.Stock - previousStock[variant.ID]; delta != 0 {
+				record(variant.ID, delta, variant.Stock, "variant stock set")
+			}
+			delete(previousStock, variant.ID)
+		}
+		for _, variant := range current {
+			if removedStock, ok := previousStock[variant.ID]; ok && removedStock != 0 {
+				record(variant.ID, -removedStock, 0, "variant removed")
+			}
+		}
+
+		removed := tx.Where("product_id = ?", productID)
+		if len(keep) > 0 {
+			removed = removed.Where("id NOT IN ?", keep)
+		}
+		if err := removed.Delete(&domain.ProductVariant{}).Error; err != nil {
+			return err
+		}
+
+		// Stock held by the product itself moves to the variants, and back when the last variant is removed
+		newStock := totalStock
+		if len(variants) == 0 {
+			newStock = product.Stock
+		}
+		if delta := newStock - stock; delta != 0 {
+			reason := "product stock moved to variants"
+			if len(variants) == 0 {
+				reason = "variant stock moved to the product"
+			}
+			record(0, delta, 0, reason)
+		}
+		if len(movements) > 0 {
+			if err := tx.Create(&movements).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&product).Update("stock", newStock).Error
+	})
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ErrRecoveryCodeInvalid is returned when a recovery code doesn't exist or was used already.
+var ErrRecoveryCodeInvalid = errors.New("invalid recovery code")
+
+// RecoveryCodeRepository defines the interface for two-factor recovery code data operations.
+type RecoveryCodeRepository interface {
+	ReplaceForUser(userID uint, codes []domain.RecoveryCode) error
+	DeleteByUserID(userID uint) error
+	Use(userID uint, codeHash string) error
+}
+
+// recoveryCodeRepository implements the RecoveryCodeRepository interface.
+type recoveryCodeRepository struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeRepository creates a new instance of RecoveryCodeRepository.
+func NewRecoveryCodeRepository(db *gorm.DB) RecoveryCodeRepository {
+	return &recoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser deletes all recovery codes of a user and stores the new set.
+func (r *recoveryCodeRepository) ReplaceForUser(userID uint, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// DeleteByUserID deletes all recovery codes of a user.
+func (r *recoveryCodeRepository) DeleteByUserID(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error
+}
+
+// Use marks an unused recovery code of a user as used in a single conditional update, so
+// concurrent requests can't both spend the same code. ErrRecoveryCodeInvalid is returned
+// when no unused code matches.
+func (r *recoveryCodeRepository) Use(userID uint, codeHash string) error {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecoveryCodeInvalid
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// ReviewRepository defines the interface for review data operations.
+type ReviewRepository interface {
+	RatingSummaryByStoreID(storeID uint) (*domain.RatingSummary, error)
+}
+
+// reviewRepository implements the ReviewRepository interface.
+type reviewRepository struct {
+	db *gorm.DB
+}
+
+// NewReviewRepository creates a new instance of ReviewRepository.
+func NewReviewRepository(db *gorm.DB) ReviewRepository {
+	return &reviewRepository{db: db}
+}
+
+// RatingSummaryByStoreID aggregates the reviews of all products of a store.
+func (r *reviewRepository) RatingSummaryByStoreID(storeID uint) (*domain.RatingSummary, error) {
+	var rows []struct {
+		Rating int
+		Total  int64
+	}
+	err := r.db.Model(&domain.Review{}).
+		Select("reviews.rating AS rating, COUNT(*) AS total").
+		Joins("JOIN products ON products.id = reviews.product_id AND products.deleted_at IS NULL").
+		Where("products.store_id = ?", storeID).
+		Group("reviews.rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &domain.RatingSummary{Distribution: map[int]int64{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}}
+	var sum int64
+	for _, row := range rows {
+		summary.Distribution[row.Rating] = row.Total
+		summary.Count += row.Total
+		sum += int64(row.Rating) * row.Total
+	}
+	if summary.Count > 0 {
+		summary.Average = float64(sum) / float64(summary.Count)
+	}
+	return summary, nil
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInsufficientStock is returned when a stock movement would make the stock negative.
+var ErrInsufficientStock = errors.New("not enough stock")
+
+// StockMovementRepository defines the interface for the inventory ledger.
+type StockMovementRepository interface {
+	Apply(movement *domain.StockMovement) error
+	SetStock(movement *domain.StockMovement, stock int) error
+	AttachTransaction(ids []uint, transactionID uint) error
+	FindAll(filter domain.StockMovementFilter) ([]domain.StockMovement, domain.PageInfo, error)
+	FindLowStock(storeID uint, threshold int) ([]domain.LowStockItem, error)
+}
+
+// stockMovementRepository implements the StockMovementRepository interface.
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+// NewStockMovementRepository creates a new instance of StockMovementRepository.
+func NewStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+// Apply records a stock movement and applies its quantity to the product stock, and to the variant
+// stock for a variant movement, in one transaction. The rows are locked so concurrent orders can't
+// oversell; ErrInsufficientStock is returned when the stock would become negative.
+func (r *stockMovementRepository) Apply(movement *domain.StockMovement) error {
+	return r.apply(movement, nil)
+}
+
+// SetStock records the movement that brings the product stock to an absolute level, such as a
+// counted stock. The quantity is worked out from the locked stock, so a concurrent order can't
+// make it stale; nothing is recorded when the stock is already at that level.
+func (r *stockMovementRepository) SetStock(movement *domain.StockMovement, stock int) error {
+	return r.apply(movement, &stock)
+}
+
+// apply records a movement under a lock on the product row; with a target stock the quantity
+// of the movement is set to reach it.
+func (r *stockMovementRepository) apply(movement *domain.StockMovement, target *int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var product domain.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").First(&product, movement.ProductID).Error; err != nil {
+			return err
+		}
+		if target != nil {
+			movement.Quantity = *target - product.Stock
+			if movement.Quantity == 0 {
+				movement.StockAfter = product.Stock
+				return nil
+			}
+		}
+		movement.StockAfter = product.Stock + movement.Quantity
+		if movement.StockAfter < 0 {
+			return ErrInsufficientStock
+		}
+
+		if movement.VariantID != nil {
+			var variant domain.ProductVariant
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "stock").
+				Where("product_id = ?", movement.ProductID).First(&variant, *movement.VariantID).Error; err != nil {
+				return err
+			}
+			variantStock := variant.Stock + movement.Quantity
+			if variantStock < 0 {
+				return ErrInsufficientStock
+			}
+			if err := tx.Model(&variant).Update("stock", variantStock).Error; err != nil {
+				return err
+			}
+			movement.VariantStockAfter = &variantStock
+		}
+
+		if err := tx.Model(&product).Update("stock", movement.StockAfter).Error; err != nil {
+			return err
+		}
+		return tx.Create(movement).Error
+	})
+}
+
+// AttachTransaction links sale movements recorded during checkout to the order once it is saved.
+func (r *stockMovementRepository) AttachTransaction(ids []uint, transactionID uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.StockMovement{}).Where("id IN ?", ids).Update("transaction_id", transactionID).Error
+}
+
+// FindAll retrieves the stock movements of a product, newest first, with filtering and pagination.
+func (r *stockMovementRepository) FindAll(filter domain.StockMovementFilter) ([]domain.StockMovement, domain.PageInfo, error) {
+	var movements []domain.StockMovement
+	query := r.db.Model(&domain.StockMovement{}).Where("product_id = ?", filter.ProductID)
+
+	if filter.VariantID != 0 {
+		query = query.Where("variant_id = ?", filter.VariantID)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	keys := []sortKey{{SQL: "stock_movements.id", Desc: true}}
+	page, err := paginate(query, r.db.Model(&domain.StockMovement{}), keys, pageRequest{
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		Cursor:    filter.Cursor,
+		SkipCount: filter.SkipCount,
+		Sort:      "newest",
+	}, &movements, func(m domain.StockMovement) uint { return m.ID })
+	return movements, page, err
+}
+
+// FindLowStock lists the products of a store without variants, and the variants of the others,
+// whose stock is at or below the threshold, lowest stock first.
+func (r *stockMovementRepository) FindLowStock(storeID uint, threshold int) ([]domain.LowStockItem, error) {
+	var items []domain.LowStockItem
+	err := r.db.Table("products").
+		Select("products.id AS product_id, products.name AS product_name, products.sku, products.stock").
+		Where("products.store_id = ? AND products.deleted_at IS NULL AND products.stock <= ?", storeID, threshold).
+		Where("NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.product_id = products.id AND v.deleted_at IS NULL)").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var variantItems []domain.LowStockItem
+	err = r.db.Table("product_variants").
+		Select("products.id AS product_id, products.name AS product_name, product_variants.sku, "+
+			"product_variants.id AS variant_id, product_variants.name AS variant_name, product_variants.stock").
+		Joins("JOIN products ON products.id = product_variants.product_id").
+		Where("products.store_id = ? AND products.deleted_at IS NULL AND product_variants.deleted_at IS NULL", storeID).
+		Where("product_variants.stock <= ?", threshold).
+		Scan(&variantItems).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items = append(items, variantItems...)
+	for i := range items {
+		items[i].Threshold = threshold
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Stock != items[j].Stock {
+			return items[i].Stock < items[j].Stock
+		}
+		return items[i].ProductID < items[j].ProductID
+	})
+	return items, nil
+}
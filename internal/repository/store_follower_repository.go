@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StoreFollowerRepository defines the interface for store follower data operations.
+type StoreFollowerRepository interface {
+	Follow(storeID, userID uint) error
+	Unfollow(storeID, userID uint) error
+	IsFollowing(storeID, userID uint) (bool, error)
+	CountByStoreID(storeID uint) (int64, error)
+	FindByUserID(userID uint) ([]domain.StoreFollower, error)
+}
+
+// storeFollowerRepository implements the StoreFollowerRepository interface.
+type storeFollowerRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreFollowerRepository creates a new instance of StoreFollowerRepository.
+func NewStoreFollowerRepository(db *gorm.DB) StoreFollowerRepository {
+	return &storeFollowerRepository{db: db}
+}
+
+// Follow records a user following a store. Following twice is a no-op.
+func (r *storeFollowerRepository) Follow(storeID, userID uint) error {
+	follower := domain.StoreFollower{StoreID: storeID, UserID: userID}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&follower).Error
+}
+
+// Unfollow removes a user's follow of a store.
+func (r *storeFollowerRepository) Unfollow(storeID, userID uint) error {
+	return r.db.Where("store_id = ? AND user_id = ?", storeID, userID).Delete(&domain.StoreFollower{}).Error
+}
+
+// IsFollowing checks if a user follows a store.
+func (r *storeFollowerRepository) IsFollowing(storeID, userID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.StoreFollower{}).Where("store_id = ? AND user_id = ?", storeID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// CountByStoreID counts the followers of a store.
+func (r *storeFollowerRepository) CountByStoreID(storeID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.StoreFollower{}).Where("store_id = ?", storeID).Count(&count).Error
+	return count, err
+}
+
+// FindByUserID retrieves the stores a user follows.
+func (r *storeFollowerRepository) FindByUserID(userID uint) ([]domain.StoreFollower, error) {
+	var followers []domain.StoreFollower
+	err := r.db.Preload("Store").Where("user_id = ?", userID).Order("created_at DESC").Find(&followers).Error
+	return followers, err
+}
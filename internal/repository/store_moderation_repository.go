@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StoreModerationRepository defines the interface for store verification data operations.
+type StoreModerationRepository interface {
+	CreateDocument(document *domain.StoreDocument) error
+	FindDocumentByID(id uint) (*domain.StoreDocument, error)
+	FindDocumentsByStoreID(storeID uint) ([]domain.StoreDocument, error)
+	FindLogsByStoreID(storeID uint) ([]domain.StoreModerationLog, error)
+	ApplyAction(store *domain.Store, log *domain.StoreModerationLog) error
+}
+
+// storeModerationRepository implements the StoreModerationRepository interface.
+type storeModerationRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreModerationRepository creates a new instance of StoreModerationRepository.
+func NewStoreModerationRepository(db *gorm.DB) StoreModerationRepository {
+	return &storeModerationRepository{db: db}
+}
+
+// CreateDocument stores a new KYC document record.
+func (r *storeModerationRepository) CreateDocument(document *domain.StoreDocument) error {
+	return r.db.Create(document).Error
+}
+
+// FindDocumentByID retrieves a KYC document by its ID.
+func (r *storeModerationRepository) FindDocumentByID(id uint) (*domain.StoreDocument, error) {
+	var document domain.StoreDocument
+	err := r.db.First(&document, id).Error
+	return &document, err
+}
+
+// FindDocumentsByStoreID retrieves all KYC documents of a store.
+func (r *storeModerationRepository) FindDocumentsByStoreID(storeID uint) ([]domain.StoreDocument, error) {
+	var documents []domain.StoreDocument
+	err := r.db.Where("store_id = ?", storeID).Order("created_at DESC").Find(&documents).Error
+	return documents, err
+}
+
+// FindLogsByStoreID retrieves the moderation history of a store, newest first.
+func (r *storeModerationRepository) FindLogsByStoreID(storeID uint) ([]domain.StoreModerationLog, error) {
+	var logs []domain.StoreModerationLog
+	err := r.db.Where("store_id = ?", storeID).Order("created_at DESC").Find(&logs).Error
+	return logs, err
+}
+
+// ApplyAction saves the store's new status together with its moderation log entry.
+func (r *storeModerationRepository) ApplyAction(store *domain.Store, log *domain.StoreModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(store).Error; err != nil {
+			return err
+		}
+		return tx.Create(log).Error
+	})
+}
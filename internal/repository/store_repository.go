@@ -10,10 +10,13 @@ import (
 type StoreRepository interface {
 	Create(store *domain.Store) error
 	FindByID(id uint) (*domain.Store, error)
+	FindBySlug(slug string) (*domain.Store, error)
 	Update(store *domain.Store) error
 	Delete(id uint) error
-	FindAll(filter domain.StoreFilter) ([]domain.Store, int64, error)
+	FindAll(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error)
 	FindByUserID(userID uint) ([]domain.Store, error)
+	CountByUserID(userID uint) (int64, error)
+	SlugExists(slug string, excludeID uint) (bool, error)
 	// Add other store-related methods here as needed
 }
 
@@ -39,6 +42,13 @@ func (r *storeRepository) FindByID(id uint) (*domain.Store, error) {
 	return &store, err
 }
 
+// FindBySlug retrieves a store by its slug.
+func (r *storeRepository) FindBySlug(slug string) (*domain.Store, error) {
+	var store domain.Store
+	err := r.db.Where("slug = ?", slug).First(&store).Error
+	return &store, err
+}
+
 // Update an existing store in the database.
 func (r *storeRepository) Update(store *domain.Store) error {
 	return r.db.Save(store).Error
@@ -51,7 +61,7 @@ func (r *storeRepository) Delete(id uint) error {
 }
 
 // FindAll retrieves all stores from the database with pagination and filtering.
-func (r *storeRepository) FindAll(filter domain.StoreFilter) ([]domain.Store, int64, error) {
+func (r *storeRepository) FindAll(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error) {
 	var stores []domain.Store
 	query := r.db.Model(&domain.Store{})
 
@@ -61,23 +71,44 @@ func (r *storeRepository) FindAll(filter domain.StoreFilter) ([]domain.Store, in
 		query = query.Where("name LIKE ? OR description LIKE ? OR address LIKE ?", searchPattern, searchPattern, searchPattern)
 	}
 
-	// Get total count before applying pagination
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
+	// Apply status filter
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
 	}
 
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.Limit
-	query = query.Limit(filter.Limit).Offset(offset)
-
-	err := query.Find(&stores).Error
-	return stores, totalCount, err
+	// Apply pagination, oldest stores first
+	page, err := paginate(query, r.db.Model(&domain.Store{}), []sortKey{{SQL: "stores.id"}}, pageRequest{
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		Cursor:    filter.Cursor,
+		SkipCount: filter.SkipCount,
+		Sort:      "id",
+	}, &stores, func(s domain.Store) uint { return s.ID })
+	return stores, page, err
 }
 
 // FindByUserID retrieves all stores for a specific user.
 func (r *storeRepository) FindByUserID(userID uint) ([]domain.Store, error) {
 	var stores []domain.Store
-	err := r.db.Where("user_id = ?", userID).Find(&stores).Error
+	err := r.db.Where("user_id = ?", userID).Order("id ASC").Find(&stores).Error
 	return stores, err
-}
\ No newline at end of file
+}
+
+// CountByUserID counts the stores owned by a user.
+func (r *storeRepository) CountByUserID(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Store{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+// SlugExists checks if a store with the given slug already exists.
+// Soft-deleted stores are included so their slugs are never reused.
+func (r *storeRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&domain.Store{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StoreSettingsRepository defines the interface for store settings data operations.
+type StoreSettingsRepository interface {
+	FindByStoreID(storeID uint) (*domain.StoreSettings, error)
+	Save(settings *domain.StoreSettings) error
+}
+
+// storeSettingsRepository implements the StoreSettingsRepository interface.
+type storeSettingsRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreSettingsRepository creates a new instance of StoreSettingsRepository.
+func NewStoreSettingsRepository(db *gorm.DB) StoreSettingsRepository {
+	return &storeSettingsRepository{db: db}
+}
+
+// FindByStoreID retrieves the settings of a store.
+func (r *storeSettingsRepository) FindByStoreID(storeID uint) (*domain.StoreSettings, error) {
+	var settings domain.StoreSettings
+	err := r.db.Where("store_id = ?", storeID).First(&settings).Error
+	return &settings, err
+}
+
+// Save creates or updates the settings of a store.
+func (r *storeSettingsRepository) Save(settings *domain.StoreSettings) error {
+	return r.db.Save(settings).Error
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// StoreStaffRepository defines the interface for store staff data operations.
+type StoreStaffRepository interface {
+	Create(staff *domain.StoreStaff) error
+	FindByID(id uint) (*domain.StoreStaff, error)
+	FindByStoreAndUser(storeID, userID uint) (*domain.StoreStaff, error)
+	FindByStoreID(storeID uint) ([]domain.StoreStaff, error)
+	FindByUserID(userID uint, status string) ([]domain.StoreStaff, error)
+	Update(staff *domain.StoreStaff) error
+	Delete(id uint) error
+}
+
+// storeStaffRepository implements the StoreStaffRepository interface.
+type storeStaffRepository struct {
+	db *gorm.DB
+}
+
+// NewStoreStaffRepository creates a new instance of StoreStaffRepository.
+func NewStoreStaffRepository(db *gorm.DB) StoreStaffRepository {
+	return &storeStaffRepository{db: db}
+}
+
+// Create a new staff membership in the database.
+func (r *storeStaffRepository) Create(staff *domain.StoreStaff) error {
+	return r.db.Create(staff).Error
+}
+
+// FindByID retrieves a staff membership by its ID.
+func (r *storeStaffRepository) FindByID(id uint) (*domain.StoreStaff, error) {
+	var staff domain.StoreStaff
+	err := r.db.First(&staff, id).Error
+	return &staff, err
+}
+
+// FindByStoreAndUser retrieves the membership of a user in a store.
+func (r *storeStaffRepository) FindByStoreAndUser(storeID, userID uint) (*domain.StoreStaff, error) {
+	var staff domain.StoreStaff
+	err := r.db.Where("store_id = ? AND user_id = ?", storeID, userID).First(&staff).Error
+	return &staff, err
+}
+
+// FindByStoreID retrieves all staff of a store with their users.
+func (r *storeStaffRepository) FindByStoreID(storeID uint) ([]domain.StoreStaff, error) {
+	var staff []domain.StoreStaff
+	err := r.db.Preload("User").Where("store_id = ?", storeID).Find(&staff).Error
+	return staff, err
+}
+
+// FindByUserID retrieves a user's memberships with their stores, optionally filtered by status.
+func (r *storeStaffRepository) FindByUserID(userID uint, status string) ([]domain.StoreStaff, error) {
+	var staff []domain.StoreStaff
+	query := r.db.Preload("Store").Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Find(&staff).Error
+	return staff, err
+}
+
+// Update an existing staff membership in the database.
+func (r *storeStaffRepository) Update(staff *domain.StoreStaff) error {
+	return r.db.Save(staff).Error
+}
+
+// Delete a staff membership by its ID.
+func (r *storeStaffRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.StoreStaff{}, id).Error
+}
@@ -10,7 +10,7 @@ import (
 type TransactionRepository interface {
 	Create(transaction *domain.Transaction) error
 	FindByID(id, userID uint) (*domain.Transaction, error) // Updated to include userID
-	FindAll(filter domain.TransactionFilter) ([]domain.Transaction, int64, error)
+	FindAll(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error)
 	Update(transaction *domain.Transaction) error
 	// Add other transaction-related methods here as needed
 }
@@ -39,7 +39,7 @@ func (r *transactionRepository) FindByID(id, userID uint) (*domain.Transaction,
 }
 
 // FindAll retrieves transactions based on the provided filter.
-func (r *transactionRepository) FindAll(filter domain.TransactionFilter) ([]domain.Transaction, int64, error) {
+func (r *transactionRepository) FindAll(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error) {
 	var transactions []domain.Transaction
 	query := r.db.Model(&domain.Transaction{}).Preload("Items.ProductLog").Preload("User").Preload("Address")
 
@@ -50,13 +50,12 @@ func (r *transactionRepository) FindAll(filter domain.TransactionFilter) ([]doma
 
 	// Apply StoreID filter
 	if filter.StoreID != 0 {
-		// This filter is complex as Transaction model doesn't directly have store_id.
-		// It would require joining through TransactionItems and Products, which adds complexity.
-		// For now, I'll omit this to avoid breaking changes if schema doesn't support directly.
-		// If needed, the join logic would look something like:
-		// query = query.Joins("JOIN transaction_items ti ON ti.transaction_id = transactions.id").
-		// 	Joins("JOIN products p ON p.id = ti.product_id").
-		// 	Where("p.store_id = ?", filter.StoreID)
+		// Transaction has no store_id; match transactions with at least one item from the store.
+		// A subquery avoids duplicate rows that a plain JOIN would produce.
+		query = query.Where("transactions.id IN (?)", r.db.Table("transaction_items ti").
+			Select("ti.transaction_id").
+			Joins("JOIN products p ON p.id = ti.product_id").
+			Where("p.store_id = ?", filter.StoreID))
 	}
 
 	// Apply Status filter
@@ -69,18 +68,16 @@ func (r *transactionRepository) FindAll(filter domain.TransactionFilter) ([]doma
 		query = query.Where("payment_method = ?", filter.PaymentMethod)
 	}
 
-	// Get total count before applying pagination
-	var totalCount int64
-	if err := query.Count(&totalCount).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Apply pagination
-	offset := (filter.Page - 1) * filter.Limit
-	query = query.Limit(filter.Limit).Offset(offset)
-
-	err := query.Find(&transactions).Error
-	return transactions, totalCount, err
+	// Apply pagination, newest transactions first
+	keys := []sortKey{{SQL: "transactions.created_at", Desc: true}, {SQL: "transactions.id", Desc: true}}
+	page, err := paginate(query, r.db.Model(&domain.Transaction{}), keys, pageRequest{
+		Page:      filter.Page,
+		Limit:     filter.Limit,
+		Cursor:    filter.Cursor,
+		SkipCount: filter.SkipCount,
+		Sort:      "newest",
+	}, &transactions, func(t domain.Transaction) uint { return t.ID })
+	return transactions, page, err
 }
 
 // Update an existing transaction in the database.
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// TxRepositories are repositories bound to one database transaction, for use cases that must
+// change several tables together.
+type TxRepositories struct {
+	Products          ProductRepository
+	Variants          ProductVariantRepository
+	StockMovements    StockMovementRepository
+	DiscountCampaigns DiscountCampaignRepository
+	Vouchers          VoucherRepository
+	CheckoutQuotes    CheckoutQuoteRepository
+	Transactions      TransactionRepository
+}
+
+// Transactor runs functions in a database transaction.
+type Transactor interface {
+	// Transaction runs fn with repositories bound to a new transaction, which is committed when
+	// fn returns nil and rolled back otherwise.
+	Transaction(fn func(repos TxRepositories) error) error
+}
+
+// transactor implements the Transactor interface.
+type transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new instance of Transactor.
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+// Transaction runs fn in a transaction. Repository methods that use a transaction of their own
+// run it as a savepoint, so everything fn does is committed or rolled back together.
+func (t *transactor) Transaction(fn func(repos TxRepositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(TxRepositories{
+			Products:          NewProductRepository(tx),
+			Variants:          NewProductVariantRepository(tx),
+			StockMovements:    NewStockMovementRepository(tx),
+			DiscountCampaigns: NewDiscountCampaignRepository(tx),
+			Vouchers:          NewVoucherRepository(tx),
+			CheckoutQuotes:    NewCheckoutQuoteRepository(tx),
+			Transactions:      NewTransactionRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrChallengeClosed is returned when a two-factor challenge has been completed or has no attempts left.
+var ErrChallengeClosed = errors.New("two-factor challenge is no longer valid")
+
+// TwoFactorChallengeRepository defines the interface for two-factor challenge attempt tracking.
+type TwoFactorChallengeRepository interface {
+	Attempt(challenge *domain.TwoFactorChallenge, maxAttempts int) error
+	Consume(id string) error
+}
+
+// twoFactorChallengeRepository implements the TwoFactorChallengeRepository interface.
+type twoFactorChallengeRepository struct {
+	db *gorm.DB
+}
+
+// NewTwoFactorChallengeRepository creates a new instance of TwoFactorChallengeRepository.
+func NewTwoFactorChallengeRepository(db *gorm.DB) TwoFactorChallengeRepository {
+	return &twoFactorChallengeRepository{db: db}
+}
+
+// Attempt counts a verification attempt against the challenge, recording the challenge on its first
+// attempt. The count is a single conditional update, so concurrent attempts can't go over maxAttempts.
+// ErrChallengeClosed is returned when the challenge is completed or its attempts are used up.
+func (r *twoFactorChallengeRepository) Attempt(challenge *domain.TwoFactorChallenge, maxAttempts int) error {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(challenge).Error; err != nil {
+		return err
+	}
+	result := r.db.Model(&domain.TwoFactorChallenge{}).
+		Where("id = ? AND user_id = ? AND attempts < ? AND consumed_at IS NULL", challenge.ID, challenge.UserID, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChallengeClosed
+	}
+	return nil
+}
+
+// Consume marks a challenge as completed in a single conditional update, so its token can
+// complete only one login. ErrChallengeClosed is returned when it was completed already.
+func (r *twoFactorChallengeRepository) Consume(id string) error {
+	result := r.db.Model(&domain.TwoFactorChallenge{}).Where("id = ? AND consumed_at IS NULL", id).Update("consumed_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrChallengeClosed
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// UserIdentityRepository defines the interface for external identity data operations.
+type UserIdentityRepository interface {
+	Create(identity *domain.UserIdentity) error
+	FindByProviderSubject(provider, subject string) (*domain.UserIdentity, error)
+}
+
+// userIdentityRepository implements the UserIdentityRepository interface.
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewUserIdentityRepository creates a new instance of UserIdentityRepository.
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+// Create a new identity link in the database.
+func (r *userIdentityRepository) Create(identity *domain.UserIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindByProviderSubject retrieves the identity for a provider's subject.
+func (r *userIdentityRepository) FindByProviderSubject(provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	return &identity, err
+}
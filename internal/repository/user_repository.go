@@ -2,6 +2,9 @@
 package repository
 
 import (
+	"fmt"
+	"time"
+
 	"mini-project-ostore/internal/domain"
 
 	"gorm.io/gorm"
@@ -15,6 +18,11 @@ type UserRepository interface {
 	Update(user *domain.User) error
 	EmailExists(email string, excludeID uint) (bool, error)
 	PhoneExists(phone string, excludeID uint) (bool, error)
+	FindAll(filter domain.UserFilter) ([]domain.User, int64, error)
+	Anonymize(id, adminID uint) error
+	IncrementFailedLogins(id uint) (int, error)
+	LockUntil(id uint, until time.Time) error
+	ResetFailedLogins(id uint) error
 }
 
 type userRepository struct {
@@ -70,3 +78,141 @@ func (r *userRepository) PhoneExists(phone string, excludeID uint) (bool, error)
 	err := query.Count(&count).Error
 	return count > 0, err
 }
+
+// FindAll retrieves users based on the provided filter.
+func (r *userRepository) FindAll(filter domain.UserFilter) ([]domain.User, int64, error) {
+	var users []domain.User
+	query := r.db.Model(&domain.User{})
+
+	// Apply search filter
+	if filter.Search != "" {
+		searchPattern := "%" + filter.Search + "%"
+		query = query.Where("name LIKE ? OR email LIKE ? OR phone LIKE ?", searchPattern, searchPattern, searchPattern)
+	}
+
+	// Apply role filter
+	switch filter.Role {
+	case domain.UserRoleAdmin:
+		query = query.Where("is_admin = ?", true)
+	case domain.UserRoleUser:
+		query = query.Where("is_admin = ?", false)
+	}
+
+	// Apply status filter
+	switch filter.Status {
+	case domain.UserStatusActive:
+		query = query.Where("suspended_at IS NULL")
+	case domain.UserStatusSuspended:
+		query = query.Where("suspended_at IS NOT NULL")
+	}
+
+	// Apply created date range
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+
+	// Get total count before applying pagination
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Apply pagination
+	offset := (filter.Page - 1) * filter.Limit
+	query = query.Order("id DESC").Limit(filter.Limit).Offset(offset)
+
+	err := query.Find(&users).Error
+	return users, totalCount, err
+}
+
+// Anonymize scrubs a user's personal data, removes linked credentials and
+// addresses, and soft-deletes the user. Orders keep referencing the user ID.
+// The user's stores are suspended, recorded as moderated by the admin, so they
+// and their products are no longer listed.
+func (r *userRepository) Anonymize(id, adminID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stores []domain.Store
+		if err := tx.Where("user_id = ? AND status <> ?", id, domain.StoreStatusSuspended).Find(&stores).Error; err != nil {
+			return err
+		}
+		for _, store := range stores {
+			log := domain.StoreModerationLog{
+				StoreID:    store.ID,
+				AdminID:    adminID,
+				Action:     domain.StoreActionSuspend,
+				FromStatus: store.Status,
+				ToStatus:   domain.StoreStatusSuspended,
+				Reason:     "owner account deleted",
+			}
+			if err := tx.Create(&log).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&store).Update("status", domain.StoreStatusSuspended).Error; err != nil {
+				return err
+			}
+		}
+
+		updates := map[string]interface{}{
+			"name":               "Deleted User",
+			"email":              fmt.Sprintf("deleted-%d@deleted.invalid", id),
+			"phone":              fmt.Sprintf("deleted-%d", id),
+			"password":           "",
+			"phone_verified_at":  nil,
+			"email_verified_at":  nil,
+			"totp_secret":        "",
+			"two_factor_enabled": false,
+		}
+		if err := tx.Model(&domain.User{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Address{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.UserIdentity{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.OTP{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.StoreStaff{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", id).Delete(&domain.StoreFollower{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.User{}, id).Error
+	})
+}
+
+// IncrementFailedLogins adds a failed login to the user's counter and returns the new count.
+// The increment is done by the database, so concurrent failures are all counted.
+func (r *userRepository) IncrementFailedLogins(id uint) (int, error) {
+	var count int
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).Where("id = ?", id).
+			UpdateColumn("failed_login_count", gorm.Expr("failed_login_count + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.User{}).Where("id = ?", id).Select("failed_login_count").Scan(&count).Error
+	})
+	return count, err
+}
+
+// LockUntil locks the user's account until the given time. An existing lockout is only ever
+// extended, so concurrent failures can't shorten it.
+func (r *userRepository) LockUntil(id uint, until time.Time) error {
+	return r.db.Model(&domain.User{}).Where("id = ? AND (locked_until IS NULL OR locked_until < ?)", id, until).
+		UpdateColumn("locked_until", until).Error
+}
+
+// ResetFailedLogins clears the user's failed login counter and lockout.
+func (r *userRepository) ResetFailedLogins(id uint) error {
+	return r.db.Model(&domain.User{}).Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{"failed_login_count": 0, "locked_until": nil}).Error
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+
+	"mini-project-ostore/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Errors returned when a voucher can't be redeemed any more.
+var (
+	ErrVoucherUsedUp       = errors.New("voucher has been fully used")
+	ErrVoucherLimitReached = errors.New("voucher usage limit per user reached")
+)
+
+// VoucherRepository defines the interface for voucher data operations and redemptions.
+type VoucherRepository interface {
+	Create(voucher *domain.Voucher) error
+	FindByID(id uint) (*domain.Voucher, error)
+	FindByCode(code string) (*domain.Voucher, error)
+	FindAll(storeID *uint) ([]domain.Voucher, error)
+	CodeExists(code string) (bool, error)
+	Update(voucher *domain.Voucher) error
+	CountUserRedemptions(voucherID, userID uint) (int64, error)
+	Redeem(redemption *domain.VoucherRedemption) error
+	AttachTransaction(redemptionID, transactionID uint) error
+}
+
+// voucherRepository implements the VoucherRepository interface.
+type voucherRepository struct {
+	db *gorm.DB
+}
+
+// NewVoucherRepository creates a new instance of VoucherRepository.
+func NewVoucherRepository(db *gorm.DB) VoucherRepository {
+	return &voucherRepository{db: db}
+}
+
+// Create a new voucher in the database.
+func (r *voucherRepository) Create(voucher *domain.Voucher) error {
+	return r.db.Create(voucher).Error
+}
+
+// FindByID retrieves a voucher by its ID.
+func (r *voucherRepository) FindByID(id uint) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+	err := r.db.First(&voucher, id).Error
+	return &voucher, err
+}
+
+// FindByCode retrieves a voucher by its code.
+func (r *voucherRepository) FindByCode(code string) (*domain.Voucher, error) {
+	var voucher domain.Voucher
+	err := r.db.Where("code = ?", code).First(&voucher).Error
+	return &voucher, err
+}
+
+// FindAll retrieves the vouchers of a store, or the platform vouchers when storeID is nil, newest first.
+func (r *voucherRepository) FindAll(storeID *uint) ([]domain.Voucher, error) {
+	var vouchers []domain.Voucher
+	query := r.db.Order("id DESC")
+	if storeID != nil {
+		query = query.Where("store_id = ?", *storeID)
+	} else {
+		query = query.Where("store_id IS NULL")
+	}
+	err := query.Find(&vouchers).Error
+	return vouchers, err
+}
+
+// CodeExists checks if a voucher already uses the code.
+// Deleted vouchers are included, as they still hold the code in the unique index.
+func (r *voucherRepository) CodeExists(code string) (bool, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&domain.Voucher{}).Where("code = ?", code).Count(&count).Error
+	return count > 0, err
+}
+
+// Update an existing voucher in the database. The usage count is left out,
+// as it only changes through Redeem.
+func (r *voucherRepository) Update(voucher *domain.Voucher) error {
+	return r.db.Omit("used_count").Save(voucher).Error
+}
+
+// CountUserRedemptions counts the redemptions of a voucher by a user.
+func (r *voucherRepository) CountUserRedemptions(voucherID, userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.VoucherRedemption{}).Where("voucher_id = ? AND user_id = ?", voucherID, userID).Count(&count).Error
+	return count, err
+}
+
+// Redeem records a redemption and counts it against the voucher limits in one transaction.
+// The voucher row is locked so concurrent orders can't go over the limits.
+func (r *voucherRepository) Redeem(redemption *domain.VoucherRedemption) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var voucher domain.Voucher
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&voucher, redemption.VoucherID).Error; err != nil {
+			return err
+		}
+		if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+			return ErrVoucherUsedUp
+		}
+		if voucher.PerUserLimit > 0 {
+			var used int64
+			if err := tx.Model(&domain.VoucherRedemption{}).Where("voucher_id = ? AND user_id = ?", voucher.ID, redemption.UserID).Count(&used).Error; err != nil {
+				return err
+			}
+			if used >= int64(voucher.PerUserLimit) {
+				return ErrVoucherLimitReached
+			}
+		}
+
+		if err := tx.Model(&voucher).Update("used_count", gorm.Expr("used_count + 1")).Error; err != nil {
+			return err
+		}
+		return tx.Create(redemption).Error
+	})
+}
+
+// AttachTransaction links a redemption made during checkout to the order once it is saved.
+func (r *voucherRepository) AttachTransaction(redemptionID, transactionID uint) error {
+	return r.db.Model(&domain.VoucherRedemption{}).Where("id = ?", redemptionID).Update("transaction_id", transactionID).Error
+}
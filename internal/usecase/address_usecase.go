@@ -10,7 +10,7 @@ import (
 type AddressUseCase interface {
 	Create(address *domain.Address) error
 	GetByID(id uint) (*domain.Address, error)
-	Update(address *domain.Address) error
+	Update(id, userID uint, changes domain.AddressUpdate) error
 	Delete(id uint) error
 	GetByUserID(userID uint) ([]domain.Address, error)
 }
@@ -41,29 +41,42 @@ func (uc *addressUseCase) GetByID(id uint) (*domain.Address, error) {
 	return uc.addressRepo.FindByID(id)
 }
 
-// Update an existing address.
-func (uc *addressUseCase) Update(address *domain.Address) error {
-	// First, check if the address exists.
-	existingAddress, err := uc.addressRepo.FindByID(address.ID)
-	if err != nil {
+// Update applies a partial update to an address of the user; fields left nil in changes are kept.
+func (uc *addressUseCase) Update(id, userID uint, changes domain.AddressUpdate) error {
+	// First, check if the address exists and belongs to the user.
+	existingAddress, err := uc.addressRepo.FindByID(id)
+	if err != nil || existingAddress.UserID != userID {
 		return errors.New("address not found")
 	}
 
-	// Ensure the UserID is not changed during update, or handle it explicitly if allowed.
-	if existingAddress.UserID != address.UserID {
-		return errors.New("cannot change UserID of an existing address")
+	// Update only the mutable fields present in the request.
+	if changes.Label != nil {
+		existingAddress.Label = *changes.Label
+	}
+	if changes.ReceiverName != nil {
+		existingAddress.ReceiverName = *changes.ReceiverName
+	}
+	if changes.Phone != nil {
+		existingAddress.Phone = *changes.Phone
+	}
+	if changes.ProvinceID != nil {
+		existingAddress.ProvinceID = *changes.ProvinceID
+	}
+	if changes.CityID != nil {
+		existingAddress.CityID = *changes.CityID
+	}
+	if changes.SubDistrictID != nil {
+		existingAddress.SubDistrictID = *changes.SubDistrictID
+	}
+	if changes.Detail != nil {
+		existingAddress.Detail = *changes.Detail
+	}
+	if changes.PostalCode != nil {
+		existingAddress.PostalCode = *changes.PostalCode
+	}
+	if changes.IsPrimary != nil {
+		existingAddress.IsPrimary = *changes.IsPrimary
 	}
-
-	// Update only the mutable fields.
-	existingAddress.Label = address.Label
-	existingAddress.ReceiverName = address.ReceiverName
-	existingAddress.Phone = address.Phone
-	existingAddress.ProvinceID = address.ProvinceID
-	existingAddress.CityID = address.CityID
-	existingAddress.SubDistrictID = address.SubDistrictID
-	existingAddress.Detail = address.Detail
-	existingAddress.PostalCode = address.PostalCode
-	existingAddress.IsPrimary = address.IsPrimary
 
 	return uc.addressRepo.Update(existingAddress)
 }
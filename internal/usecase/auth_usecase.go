@@ -3,6 +3,9 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
+	"time"
+
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
 	"mini-project-ostore/pkg/jwt"
@@ -10,18 +13,76 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+// Brute-force protection policy for password logins.
+const (
+	accountLockThreshold = 5                // Consecutive failures before an account is locked
+	accountLockBase      = time.Minute      // First lockout duration, doubled on each further failure
+	accountLockMax       = time.Hour        // Upper bound for account lockout
+	ipFailureWindow      = 15 * time.Minute // Window in which failures per IP are counted
+	ipFailureThreshold   = 20               // Failures per IP within the window before backoff applies
+	ipBackoffBase        = time.Minute      // First IP backoff, doubled on each further failure
+	ipBackoffMax         = time.Hour        // Upper bound for IP backoff
+)
+
+// LoginLockedError is returned when login is refused because the account
+// or the client IP address is temporarily locked out.
+type LoginLockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+// LoginResult is the outcome of a successful first login factor. When the user
+// has two-factor authentication enabled, Token is empty and ChallengeToken must
+// be exchanged through TwoFactorUseCase.VerifyChallenge for the final token.
+type LoginResult struct {
+	Token             string
+	ChallengeToken    string
+	TwoFactorRequired bool
+	User              *domain.User
+}
+
+// completeLogin issues the final access token, or a challenge token when a second factor is required.
+// Every login method goes through it, so a locked account can't log in another way.
+func completeLogin(user *domain.User) (*LoginResult, error) {
+	if user.SuspendedAt != nil {
+		return nil, errors.New("account suspended")
+	}
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return nil, &LoginLockedError{RetryAfter: time.Until(*user.LockedUntil)}
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := jwt.GenerateChallengeToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		return &LoginResult{ChallengeToken: challenge, TwoFactorRequired: true, User: user}, nil
+	}
+
+	token, err := jwt.GenerateToken(user.ID, user.IsAdmin, false)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: token, User: user}, nil
+}
+
 type AuthUseCase interface {
 	Register(user *domain.User) error
-	Login(email, password string) (string, *domain.User, error)
+	Login(email, password, ip, userAgent string) (*LoginResult, error)
+	UnlockAccount(userID uint) error
 }
 
 type authUseCase struct {
-	userRepo  repository.UserRepository
-	storeRepo repository.StoreRepository // Add StoreRepository dependency
+	userRepo         repository.UserRepository
+	storeRepo        repository.StoreRepository // Add StoreRepository dependency
+	loginAttemptRepo repository.LoginAttemptRepository
 }
 
-func NewAuthUseCase(userRepo repository.UserRepository, storeRepo repository.StoreRepository) AuthUseCase {
-	return &authUseCase{userRepo: userRepo, storeRepo: storeRepo}
+func NewAuthUseCase(userRepo repository.UserRepository, storeRepo repository.StoreRepository, loginAttemptRepo repository.LoginAttemptRepository) AuthUseCase {
+	return &authUseCase{userRepo: userRepo, storeRepo: storeRepo, loginAttemptRepo: loginAttemptRepo}
 }
 
 func (uc *authUseCase) Register(user *domain.User) error {
@@ -67,6 +128,10 @@ func (uc *authUseCase) Register(user *domain.User) error {
 		// PhotoProfile can be empty or a default image path
 	}
 
+	if err := assignStoreSlug(uc.storeRepo, store); err != nil {
+		return errors.New("failed to create default store for user: " + err.Error())
+	}
+
 	if err := uc.storeRepo.Create(store); err != nil {
 		// If store creation fails, consider rolling back user creation or logging an error
 		// For simplicity, we just return the error for now.
@@ -76,21 +141,112 @@ func (uc *authUseCase) Register(user *domain.User) error {
 	return nil // Successfully created user and default store
 }
 
-func (uc *authUseCase) Login(email, password string) (string, *domain.User, error) {
+func (uc *authUseCase) Login(email, password, ip, userAgent string) (*LoginResult, error) {
+	// Per-IP backoff applies before anything else so credential stuffing across
+	// many accounts from one address is slowed down too.
+	if retryAfter, err := uc.ipBackoff(ip); err != nil {
+		return nil, err
+	} else if retryAfter > 0 {
+		uc.recordFailedLogin(nil, email, ip, userAgent, domain.LoginFailureIPBlocked)
+		return nil, &LoginLockedError{RetryAfter: retryAfter}
+	}
+
 	user, err := uc.userRepo.FindByEmail(email)
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		uc.recordFailedLogin(nil, email, ip, userAgent, domain.LoginFailureUnknownEmail)
+		return nil, errors.New("invalid credentials")
+	}
+
+	now := time.Now()
+	if user.LockedUntil != nil && now.Before(*user.LockedUntil) {
+		uc.recordFailedLogin(&user.ID, email, ip, userAgent, domain.LoginFailureAccountLocked)
+		return nil, &LoginLockedError{RetryAfter: user.LockedUntil.Sub(now)}
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
 	if err != nil {
-		return "", nil, errors.New("invalid credentials")
+		uc.recordFailedLogin(&user.ID, email, ip, userAgent, domain.LoginFailureWrongPassword)
+
+		// The counter is incremented by the database and the lockout taken from its new value,
+		// so parallel attempts can't outrun the threshold
+		failures, err := uc.userRepo.IncrementFailedLogins(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if failures >= accountLockThreshold {
+			lockedUntil := now.Add(backoff(accountLockBase, accountLockMax, failures-accountLockThreshold))
+			if err := uc.userRepo.LockUntil(user.ID, lockedUntil); err != nil {
+				return nil, err
+			}
+		}
+		return nil, errors.New("invalid credentials")
 	}
 
-	token, err := jwt.GenerateToken(user.ID, user.IsAdmin)
+	if user.FailedLoginCount > 0 || user.LockedUntil != nil {
+		if err := uc.userRepo.ResetFailedLogins(user.ID); err != nil {
+			return nil, err
+		}
+		user.FailedLoginCount = 0
+		user.LockedUntil = nil
+	}
+
+	return completeLogin(user)
+}
+
+// UnlockAccount clears the failed login counter and lockout of a user.
+func (uc *authUseCase) UnlockAccount(userID uint) error {
+	if _, err := uc.userRepo.FindByID(userID); err != nil {
+		return errors.New("user not found")
+	}
+	return uc.userRepo.ResetFailedLogins(userID)
+}
+
+// ipBackoff returns how long the IP address must wait before its next login attempt.
+func (uc *authUseCase) ipBackoff(ip string) (time.Duration, error) {
+	count, err := uc.loginAttemptRepo.CountByIPSince(ip, time.Now().Add(-ipFailureWindow))
 	if err != nil {
-		return "", nil, err
+		return 0, err
 	}
+	if count < ipFailureThreshold {
+		return 0, nil
+	}
+
+	latest, err := uc.loginAttemptRepo.FindLatestByIP(ip)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := backoff(ipBackoffBase, ipBackoffMax, int(count)-ipFailureThreshold)
+	if wait := time.Until(latest.CreatedAt.Add(delay)); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
+// recordFailedLogin stores an audit record of a failed login; only credential failures count
+// towards the IP backoff. Errors are ignored so that a failing audit write never changes the
+// login outcome.
+func (uc *authUseCase) recordFailedLogin(userID *uint, email, ip, userAgent, reason string) {
+	uc.loginAttemptRepo.Create(&domain.LoginAttempt{
+		UserID:    userID,
+		Email:     email,
+		IPAddress: ip,
+		UserAgent: userAgent,
+		Reason:    reason,
+	})
+}
 
-	return token, user, nil
+// backoff returns base doubled step times, capped at max.
+func backoff(base, max time.Duration, step int) time.Duration {
+	if step < 0 {
+		step = 0
+	}
+	d := base
+	for i := 0; i < step; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
 }
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// fakeLoginUserRepo keeps one user in memory and hands out copies like the database would,
+// with the failed login counter updated atomically.
+type fakeLoginUserRepo struct {
+	repository.UserRepository
+	mu   sync.Mutex
+	user domain.User
+}
+
+func (r *fakeLoginUserRepo) FindByEmail(email string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.user.Email != email {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeLoginUserRepo) FindByID(id uint) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.user.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	user := r.user
+	return &user, nil
+}
+
+func (r *fakeLoginUserRepo) IncrementFailedLogins(id uint) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user.FailedLoginCount++
+	return r.user.FailedLoginCount, nil
+}
+
+func (r *fakeLoginUserRepo) LockUntil(id uint, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.user.LockedUntil == nil || r.user.LockedUntil.Before(until) {
+		r.user.LockedUntil = &until
+	}
+	return nil
+}
+
+func (r *fakeLoginUserRepo) ResetFailedLogins(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.user.FailedLoginCount = 0
+	r.user.LockedUntil = nil
+	return nil
+}
+
+func (r *fakeLoginUserRepo) snapshot() domain.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.user
+}
+
+// fakeLoginAttemptRepo records failed logins in memory.
+type fakeLoginAttemptRepo struct {
+	mu       sync.Mutex
+	attempts []domain.LoginAttempt
+}
+
+func (r *fakeLoginAttemptRepo) Create(attempt *domain.LoginAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt.CreatedAt = time.Now()
+	r.attempts = append(r.attempts, *attempt)
+	return nil
+}
+
+func (r *fakeLoginAttemptRepo) CountByIPSince(ip string, since time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, attempt := range r.attempts {
+		if attempt.IPAddress == ip && attempt.CreatedAt.After(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeLoginAttemptRepo) FindLatestByIP(ip string) (*domain.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.attempts) - 1; i >= 0; i-- {
+		if r.attempts[i].IPAddress == ip {
+			attempt := r.attempts[i]
+			return &attempt, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func TestLoginLockout(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	newUseCase := func() (*fakeLoginUserRepo, AuthUseCase) {
+		users := &fakeLoginUserRepo{user: domain.User{ID: 1, Email: "buyer@example.com", Password: string(hash)}}
+		return users, NewAuthUseCase(users, nil, &fakeLoginAttemptRepo{})
+	}
+
+	t.Run("the threshold locks the account, even for the right password", func(t *testing.T) {
+		users, uc := newUseCase()
+		for i := 0; i < accountLockThreshold; i++ {
+			if _, err := uc.Login("buyer@example.com", "wrong", "10.0.0.1", "test"); err == nil {
+				t.Fatalf("attempt %d: wrong password logged in", i+1)
+			}
+		}
+		if users.snapshot().LockedUntil == nil {
+			t.Fatalf("account not locked after %d failures", accountLockThreshold)
+		}
+
+		_, err := uc.Login("buyer@example.com", "secret123", "10.0.0.1", "test")
+		var locked *LoginLockedError
+		if !errors.As(err, &locked) || locked.RetryAfter <= 0 {
+			t.Fatalf("Login on a locked account = %v, want a LoginLockedError", err)
+		}
+	})
+
+	t.Run("parallel failures lock the account", func(t *testing.T) {
+		users, uc := newUseCase()
+		const attempts = 3 * accountLockThreshold
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				uc.Login("buyer@example.com", "wrong", "10.0.0.2", "test")
+			}()
+		}
+		wg.Wait()
+
+		// Attempts that found the account locked don't count, but the lock must be in place
+		user := users.snapshot()
+		if user.FailedLoginCount < accountLockThreshold || user.LockedUntil == nil {
+			t.Fatalf("after %d parallel failures: count %d, locked until %v", attempts, user.FailedLoginCount, user.LockedUntil)
+		}
+	})
+
+	t.Run("a successful login resets the counter", func(t *testing.T) {
+		users, uc := newUseCase()
+		for i := 0; i < accountLockThreshold-1; i++ {
+			uc.Login("buyer@example.com", "wrong", "10.0.0.3", "test")
+		}
+		if _, err := uc.Login("buyer@example.com", "secret123", "10.0.0.3", "test"); err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if count := users.snapshot().FailedLoginCount; count != 0 {
+			t.Fatalf("failed login count after success = %d, want 0", count)
+		}
+	})
+
+	t.Run("an unlocked account can log in again", func(t *testing.T) {
+		_, uc := newUseCase()
+		for i := 0; i < accountLockThreshold; i++ {
+			uc.Login("buyer@example.com", "wrong", "10.0.0.4", "test")
+		}
+		if err := uc.UnlockAccount(1); err != nil {
+			t.Fatalf("UnlockAccount: %v", err)
+		}
+		if _, err := uc.Login("buyer@example.com", "secret123", "10.0.0.4", "test"); err != nil {
+			t.Fatalf("Login after unlock: %v", err)
+		}
+	})
+}
@@ -4,15 +4,23 @@ import (
 	"errors"
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
+	"regexp"
 )
 
+// Maximum nesting of categories, counting the root level
+const maxCategoryDepth = 5
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,49}$`)
+
 // CategoryUseCase defines the interface for category-related business logic.
 type CategoryUseCase interface {
 	Create(category *domain.Category) error
 	GetByID(id uint) (*domain.Category, error)
 	Update(category *domain.Category) error
 	Delete(id uint) error
-	GetCategories(filter domain.CategoryFilter) ([]domain.Category, int64, error)
+	GetCategories(filter domain.CategoryFilter) ([]domain.Category, domain.PageInfo, error)
+	GetTree() ([]domain.Category, error)
+	GetAttributeSchema(id uint) ([]domain.CategoryAttribute, error)
 }
 
 // categoryUseCase implements the CategoryUseCase interface.
@@ -25,10 +33,15 @@ func NewCategoryUseCase(categoryRepo repository.CategoryRepository) CategoryUseC
 	return &categoryUseCase{categoryRepo: categoryRepo}
 }
 
-// Create a new category.
+// Create a new category, optionally under a parent category.
 func (uc *categoryUseCase) Create(category *domain.Category) error {
-	// Optional: Add business logic validation here.
-	// For example, check for duplicate category names before creating.
+	categories, err := uc.categoryRepo.ListAll()
+	if err != nil {
+		return err
+	}
+	if err := uc.validate(category, categories); err != nil {
+		return err
+	}
 	return uc.categoryRepo.Create(category)
 }
 
@@ -37,7 +50,8 @@ func (uc *categoryUseCase) GetByID(id uint) (*domain.Category, error) {
 	return uc.categoryRepo.FindByID(id)
 }
 
-// Update an existing category.
+// Update an existing category. The category is expected to carry all fields, including
+// ParentID (nil for a root category) and Attributes.
 func (uc *categoryUseCase) Update(category *domain.Category) error {
 	// First, check if the category exists.
 	existingCategory, err := uc.categoryRepo.FindByID(category.ID)
@@ -45,24 +59,295 @@ func (uc *categoryUseCase) Update(category *domain.Category) error {
 		return errors.New("category not found")
 	}
 
+	categories, err := uc.categoryRepo.ListAll()
+	if err != nil {
+		return err
+	}
+	if err := uc.validate(category, categories); err != nil {
+		return err
+	}
+
 	// Update only the mutable fields.
 	existingCategory.Name = category.Name
 	existingCategory.Description = category.Description
+	existingCategory.ParentID = category.ParentID
+	existingCategory.Attributes = category.Attributes
 
 	return uc.categoryRepo.Update(existingCategory)
 }
 
-// Delete a category by its ID.
+// Delete a category by its ID. Categories with subcategories or products are protected.
 func (uc *categoryUseCase) Delete(id uint) error {
 	// First, check if the category exists.
 	_, err := uc.categoryRepo.FindByID(id)
 	if err != nil {
 		return errors.New("category not found")
 	}
+
+	children, err := uc.categoryRepo.CountChildren(id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return errors.New("category still has subcategories; move or delete them first")
+	}
+
+	products, err := uc.categoryRepo.CountProducts(id)
+	if err != nil {
+		return err
+	}
+	if products > 0 {
+		return errors.New("category still has products; move them to another category first")
+	}
+
 	return uc.categoryRepo.Delete(id)
 }
 
 // GetCategories retrieves all categories with pagination and filtering.
-func (uc *categoryUseCase) GetCategories(filter domain.CategoryFilter) ([]domain.Category, int64, error) {
+func (uc *categoryUseCase) GetCategories(filter domain.CategoryFilter) ([]domain.Category, domain.PageInfo, error) {
 	return uc.categoryRepo.FindAll(filter)
-}
\ No newline at end of file
+}
+
+// GetTree retrieves all categories nested under their parents.
+func (uc *categoryUseCase) GetTree() ([]domain.Category, error) {
+	categories, err := uc.categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return buildCategoryTree(categories, nil), nil
+}
+
+// GetAttributeSchema retrieves the attributes products of a category must follow,
+// including those inherited from its ancestors.
+func (uc *categoryUseCase) GetAttributeSchema(id uint) ([]domain.CategoryAttribute, error) {
+	return categoryAttributeSchema(uc.categoryRepo, id)
+}
+
+// validate checks the parent, sibling name and attribute schema of a new or updated category.
+func (uc *categoryUseCase) validate(category *domain.Category, categories []domain.Category) error {
+	byID := indexCategories(categories)
+
+	if category.ParentID != nil {
+		if *category.ParentID == category.ID && category.ID != 0 {
+			return errors.New("a category cannot be its own parent")
+		}
+		if _, ok := byID[*category.ParentID]; !ok {
+			return errors.New("parent category not found")
+		}
+		if category.ID != 0 {
+			for _, id := range categoryDescendantIDs(categories, category.ID) {
+				if id == *category.ParentID {
+					return errors.New("a category cannot be moved under its own subcategory")
+				}
+			}
+		}
+		depth := len(categoryAncestors(byID, *category.ParentID)) + categorySubtreeHeight(categories, category.ID)
+		if depth > maxCategoryDepth {
+			return errors.New("categories can be nested at most 5 levels deep")
+		}
+	}
+
+	exists, err := uc.categoryRepo.NameExists(category.Name, category.ParentID, category.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("category name already exists at this level")
+	}
+
+	// Attribute keys must be unique along every path from the root to a leaf
+	taken := make(map[string]bool)
+	if category.ParentID != nil {
+		for _, ancestor := range categoryAncestors(byID, *category.ParentID) {
+			for _, attribute := range ancestor.Attributes {
+				taken[attribute.Key] = true
+			}
+		}
+	}
+	if category.ID != 0 {
+		for _, id := range categoryDescendantIDs(categories, category.ID) {
+			if id == category.ID {
+				continue
+			}
+			for _, attribute := range byID[id].Attributes {
+				taken[attribute.Key] = true
+			}
+		}
+	}
+	return validateCategoryAttributes(category.Attributes, taken)
+}
+
+// validateCategoryAttributes checks an attribute schema; taken holds keys defined by related categories.
+func validateCategoryAttributes(attributes []domain.CategoryAttribute, taken map[string]bool) error {
+	seen := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		if !attributeKeyPattern.MatchString(attribute.Key) {
+			return errors.New("attribute key must be lowercase letters, digits or underscores: " + attribute.Key)
+		}
+		if seen[attribute.Key] || taken[attribute.Key] {
+			return errors.New("attribute key is already defined for this category or a related one: " + attribute.Key)
+		}
+		seen[attribute.Key] = true
+
+		switch attribute.Type {
+		case domain.AttributeTypeString, domain.AttributeTypeNumber, domain.AttributeTypeBoolean:
+		case domain.AttributeTypeEnum:
+			if len(attribute.Options) == 0 {
+				return errors.New("enum attribute needs options: " + attribute.Key)
+			}
+		default:
+			return errors.New("attribute type must be string, number, boolean or enum: " + attribute.Key)
+		}
+	}
+	return nil
+}
+
+// categoryAttributeSchema returns the attributes of a category and its ancestors, root first.
+func categoryAttributeSchema(categoryRepo repository.CategoryRepository, id uint) ([]domain.CategoryAttribute, error) {
+	categories, err := categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	byID := indexCategories(categories)
+	if _, ok := byID[id]; !ok {
+		return nil, errors.New("category not found")
+	}
+
+	var schema []domain.CategoryAttribute
+	for _, category := range categoryAncestors(byID, id) {
+		schema = append(schema, category.Attributes...)
+	}
+	return schema, nil
+}
+
+// validateProductAttributes checks product attribute values against a category schema.
+func validateProductAttributes(schema []domain.CategoryAttribute, values map[string]interface{}) error {
+	known := make(map[string]bool, len(schema))
+	for _, attribute := range schema {
+		known[attribute.Key] = true
+		value, ok := values[attribute.Key]
+		if !ok || value == nil {
+			if attribute.Required {
+				return errors.New("attribute " + attribute.Key + " is required for this category")
+			}
+			continue
+		}
+
+		valid := false
+		switch attribute.Type {
+		case domain.AttributeTypeString:
+			_, valid = value.(string)
+		case domain.AttributeTypeNumber:
+			_, valid = value.(float64)
+		case domain.AttributeTypeBoolean:
+			_, valid = value.(bool)
+		case domain.AttributeTypeEnum:
+			if s, isString := value.(string); isString {
+				for _, option := range attribute.Options {
+					if s == option {
+						valid = true
+						break
+					}
+				}
+			}
+		}
+		if !valid {
+			return errors.New("attribute " + attribute.Key + " must be a valid " + attribute.Type)
+		}
+	}
+
+	for key := range values {
+		if !known[key] {
+			return errors.New("attribute " + key + " is not defined for this category")
+		}
+	}
+	return nil
+}
+
+// categoryIDsWithDescendants returns the category and all its subcategories, for product filtering.
+func categoryIDsWithDescendants(categoryRepo repository.CategoryRepository, id uint) ([]uint, error) {
+	categories, err := categoryRepo.ListAll()
+	if err != nil {
+		return nil, err
+	}
+	return categoryDescendantIDs(categories, id), nil
+}
+
+// expandCategoryFilter extends a category filter to the category's subcategories.
+func expandCategoryFilter(categoryRepo repository.CategoryRepository, filter *domain.ProductFilter) error {
+	if filter.CategoryID == 0 {
+		return nil
+	}
+	ids, err := categoryIDsWithDescendants(categoryRepo, filter.CategoryID)
+	if err != nil {
+		return err
+	}
+	filter.CategoryIDs = ids
+	return nil
+}
+
+func indexCategories(categories []domain.Category) map[uint]domain.Category {
+	byID := make(map[uint]domain.Category, len(categories))
+	for _, category := range categories {
+		byID[category.ID] = category
+	}
+	return byID
+}
+
+// categoryAncestors returns the path from the root down to the category itself.
+func categoryAncestors(byID map[uint]domain.Category, id uint) []domain.Category {
+	var path []domain.Category
+	for current, ok := byID[id]; ok; {
+		path = append([]domain.Category{current}, path...)
+		if current.ParentID == nil || len(path) > len(byID) {
+			break
+		}
+		current, ok = byID[*current.ParentID]
+	}
+	return path
+}
+
+// categoryDescendantIDs returns the category ID followed by the IDs of all its subcategories.
+func categoryDescendantIDs(categories []domain.Category, id uint) []uint {
+	children := make(map[uint][]uint)
+	for _, category := range categories {
+		if category.ParentID != nil {
+			children[*category.ParentID] = append(children[*category.ParentID], category.ID)
+		}
+	}
+
+	ids := []uint{id}
+	for i := 0; i < len(ids); i++ {
+		ids = append(ids, children[ids[i]]...)
+	}
+	return ids
+}
+
+// categorySubtreeHeight returns the number of levels of a category's subtree, counting the category itself.
+func categorySubtreeHeight(categories []domain.Category, id uint) int {
+	if id == 0 {
+		return 1
+	}
+	height := 1
+	for _, category := range categories {
+		if category.ParentID != nil && *category.ParentID == id {
+			if h := categorySubtreeHeight(categories, category.ID) + 1; h > height {
+				height = h
+			}
+		}
+	}
+	return height
+}
+
+// buildCategoryTree nests the categories under the given parent (nil for the roots).
+func buildCategoryTree(categories []domain.Category, parentID *uint) []domain.Category {
+	var nodes []domain.Category
+	for _, category := range categories {
+		if (parentID == nil && category.ParentID == nil) || (parentID != nil && category.ParentID != nil && *category.ParentID == *parentID) {
+			id := category.ID
+			category.Children = buildCategoryTree(categories, &id)
+			nodes = append(nodes, category)
+		}
+	}
+	return nodes
+}
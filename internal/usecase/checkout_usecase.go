@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutUseCase defines the interface for pricing orders before they are placed.
+type CheckoutUseCase interface {
+	Preview(userID, addressID uint, items []domain.TransactionItem, courier, voucherCode string) (*domain.CheckoutQuote, error)
+	DeleteExpiredQuotes() (int64, error)
+}
+
+// checkoutUseCase implements the CheckoutUseCase interface.
+type checkoutUseCase struct {
+	quoteRepo    repository.CheckoutQuoteRepository
+	addressRepo  repository.AddressRepository
+	storeRepo    repository.StoreRepository
+	settingsRepo repository.StoreSettingsRepository
+	pricer       orderPricer
+	quoteTTL     time.Duration
+}
+
+// NewCheckoutUseCase creates a new instance of CheckoutUseCase.
+func NewCheckoutUseCase(
+	quoteRepo repository.CheckoutQuoteRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	addressRepo repository.AddressRepository,
+	storeRepo repository.StoreRepository,
+	settingsRepo repository.StoreSettingsRepository,
+	discountRepo repository.DiscountCampaignRepository,
+	voucherRepo repository.VoucherRepository,
+	couriers []domain.CourierRate,
+	taxRate float64,
+	quoteTTL time.Duration,
+) CheckoutUseCase {
+	return &checkoutUseCase{
+		quoteRepo:    quoteRepo,
+		addressRepo:  addressRepo,
+		storeRepo:    storeRepo,
+		settingsRepo: settingsRepo,
+		pricer: orderPricer{
+			productRepo:  productRepo,
+			variantRepo:  variantRepo,
+			storeRepo:    storeRepo,
+			settingsRepo: settingsRepo,
+			discountRepo: discountRepo,
+			voucherRepo:  voucherRepo,
+			couriers:     couriers,
+			taxRate:      taxRate,
+		},
+		quoteTTL: quoteTTL,
+	}
+}
+
+// Preview prices an order to the given address like checkout does, without reserving stock or
+// redeeming the voucher, and saves the result as a quote the order can be placed with.
+// Without a courier the cheapest one is used.
+func (uc *checkoutUseCase) Preview(userID, addressID uint, items []domain.TransactionItem, courier, voucherCode string) (*domain.CheckoutQuote, error) {
+	address, err := uc.addressRepo.FindByID(addressID)
+	if err != nil || address.UserID != userID {
+		return nil, errors.New("address not found")
+	}
+	now := time.Now()
+	lines, err := uc.pricer.price(userID, items, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Items are grouped by store, each store shipping its own package
+	quote := &domain.CheckoutQuote{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		AddressID: address.ID,
+		ExpiresAt: now.Add(uc.quoteTTL),
+	}
+	storeIndex := make(map[uint]int)
+	for _, line := range lines {
+		i, ok := storeIndex[line.Product.StoreID]
+		if !ok {
+			store, err := uc.storeRepo.FindByID(line.Product.StoreID)
+			if err != nil {
+				return nil, errors.New("store not found for product " + line.Product.Name)
+			}
+			i = len(quote.Stores)
+			storeIndex[store.ID] = i
+			quote.Stores = append(quote.Stores, domain.CheckoutQuoteStore{StoreID: store.ID, StoreName: store.Name})
+		}
+
+		quoted := domain.CheckoutQuoteLine{
+			ProductID:    line.Product.ID,
+			Name:         line.Product.Name,
+			Quantity:     line.Quantity,
+			RegularPrice: line.RegularPrice,
+			Price:        line.Price,
+			Total:        line.Price * float64(line.Quantity),
+		}
+		if line.Variant != nil {
+			quoted.VariantID = &line.Variant.ID
+			quoted.VariantName = line.Variant.Name
+		}
+		if line.Campaign != nil {
+			quoted.CampaignID = &line.Campaign.ID
+		}
+
+		store := &quote.Stores[i]
+		store.Lines = append(store.Lines, quoted)
+		store.Subtotal += quoted.Total
+		store.Weight += line.Product.Weight * float64(line.Quantity)
+		quote.Subtotal += quoted.Total
+		quote.CampaignDiscount += (line.RegularPrice - line.Price) * float64(line.Quantity)
+	}
+
+	if err := uc.pricer.quoteShipping(quote, address, courier); err != nil {
+		return nil, err
+	}
+
+	if voucherCode != "" {
+		voucher, voucherQuote, err := uc.pricer.quoteVoucher(voucherCode, userID, lines, quote.ShippingCost, now)
+		if err != nil {
+			return nil, err
+		}
+		quote.VoucherCode = voucher.Code
+		quote.VoucherDiscount = voucherQuote.Discount
+		quote.ShippingDiscount = voucherQuote.ShippingDiscount
+	}
+
+	quote.CampaignDiscount = math.Round(quote.CampaignDiscount*100) / 100
+	quote.Tax = uc.pricer.tax(quote.Subtotal - quote.VoucherDiscount)
+	quote.GrandTotal = quote.Subtotal - quote.VoucherDiscount + quote.ShippingCost - quote.ShippingDiscount + quote.Tax
+
+	if err := uc.quoteRepo.Create(quote); err != nil {
+		return nil, err
+	}
+	return quote, nil
+}
+
+// DeleteExpiredQuotes deletes the quotes that expired without an order and returns how many were deleted.
+func (uc *checkoutUseCase) DeleteExpiredQuotes() (int64, error) {
+	return uc.quoteRepo.DeleteExpired(time.Now())
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// DiscountUseCase defines the interface for seller discount campaigns and flash sales.
+type DiscountUseCase interface {
+	Create(campaign *domain.DiscountCampaign, userID uint) error
+	GetCampaigns(storeID, userID uint) ([]domain.DiscountCampaign, error)
+	End(storeID, campaignID, userID uint) error
+}
+
+// Longest a campaign may run
+const maxCampaignDuration = 90 * 24 * time.Hour
+
+// discountUseCase implements the DiscountUseCase interface.
+type discountUseCase struct {
+	discountRepo repository.DiscountCampaignRepository
+	productRepo  repository.ProductRepository
+	variantRepo  repository.ProductVariantRepository
+	storeRepo    repository.StoreRepository
+	staffRepo    repository.StoreStaffRepository
+}
+
+// NewDiscountUseCase creates a new instance of DiscountUseCase.
+func NewDiscountUseCase(
+	discountRepo repository.DiscountCampaignRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	storeRepo repository.StoreRepository,
+	staffRepo repository.StoreStaffRepository,
+) DiscountUseCase {
+	return &discountUseCase{
+		discountRepo: discountRepo,
+		productRepo:  productRepo,
+		variantRepo:  variantRepo,
+		storeRepo:    storeRepo,
+		staffRepo:    staffRepo,
+	}
+}
+
+// Create sets up a discount campaign for a store the user manages products of.
+func (uc *discountUseCase) Create(campaign *domain.DiscountCampaign, userID uint) error {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, campaign.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
+	}
+
+	if !campaign.EndsAt.After(campaign.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if !campaign.EndsAt.After(time.Now()) {
+		return errors.New("ends_at must be in the future")
+	}
+	if campaign.EndsAt.Sub(campaign.StartsAt) > maxCampaignDuration {
+		return errors.New("a campaign can run for at most 90 days")
+	}
+
+	if campaign.SalePrice != nil && campaign.Percentage != 0 {
+		return errors.New("set either sale_price or percentage, not both")
+	}
+	if campaign.SalePrice == nil && (campaign.Percentage <= 0 || campaign.Percentage >= 100) {
+		return errors.New("percentage must be greater than 0 and less than 100")
+	}
+
+	switch campaign.Type {
+	case domain.DiscountTypeStoreSale:
+		if campaign.SalePrice != nil {
+			return errors.New("a store sale gives a percentage off")
+		}
+		if campaign.ProductID != nil || campaign.VariantID != nil {
+			return errors.New("a store sale applies to all products of the store")
+		}
+	case domain.DiscountTypeSale, domain.DiscountTypeFlashSale:
+		if err := uc.checkCampaignProduct(campaign); err != nil {
+			return err
+		}
+	default:
+		return errors.New("type must be sale, store_sale or flash_sale")
+	}
+
+	if campaign.Type == domain.DiscountTypeFlashSale {
+		if campaign.PerUserLimit < 1 {
+			return errors.New("per_user_limit is required for a flash sale")
+		}
+		if campaign.Quota < 0 {
+			return errors.New("quota cannot be negative")
+		}
+	} else {
+		campaign.Quota, campaign.PerUserLimit = 0, 0
+	}
+
+	campaign.SoldQuantity = 0
+	return uc.discountRepo.Create(campaign)
+}
+
+// checkCampaignProduct checks that the product, and the variant when set, belong to the campaign's store,
+// and that a fixed sale price is below the regular price.
+func (uc *discountUseCase) checkCampaignProduct(campaign *domain.DiscountCampaign) error {
+	if campaign.ProductID == nil {
+		return errors.New("product_id is required for a " + campaign.Type)
+	}
+	product, err := uc.productRepo.FindByID(*campaign.ProductID)
+	if err != nil || product.StoreID != campaign.StoreID {
+		return errors.New("product not found in the store")
+	}
+
+	regular := product.Price
+	if campaign.VariantID != nil {
+		variant, err := uc.variantRepo.FindVariantByID(*campaign.VariantID)
+		if err != nil || variant.ProductID != product.ID {
+			return errors.New("variant not found for product")
+		}
+		regular = variant.EffectivePrice(product)
+	}
+
+	if campaign.SalePrice != nil && (*campaign.SalePrice <= 0 || *campaign.SalePrice >= regular) {
+		return errors.New("sale_price must be greater than 0 and below the regular price")
+	}
+	return nil
+}
+
+// GetCampaigns lists the campaigns of a store, latest start first.
+func (uc *discountUseCase) GetCampaigns(storeID, userID uint) ([]domain.DiscountCampaign, error) {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+	return uc.discountRepo.FindByStoreID(storeID)
+}
+
+// End stops a campaign. Campaigns that already ran are kept, as orders reference them;
+// ones that haven't started yet are removed.
+func (uc *discountUseCase) End(storeID, campaignID, userID uint) error {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
+	}
+	campaign, err := uc.discountRepo.FindByID(campaignID)
+	if err != nil || campaign.StoreID != storeID {
+		return errors.New("campaign not found")
+	}
+
+	now := time.Now()
+	if campaign.StartsAt.After(now) {
+		return uc.discountRepo.Delete(campaign.ID)
+	}
+	if !campaign.EndsAt.After(now) {
+		return errors.New("campaign has already ended")
+	}
+	campaign.EndsAt = now
+	return uc.discountRepo.Update(campaign)
+}
+
+// priceList holds the campaigns running at one moment, to price products consistently
+// in listings, on the product page and at checkout.
+type priceList struct {
+	campaigns []domain.DiscountCampaign
+}
+
+// loadPriceList loads the campaigns of the stores running at the given time.
+func loadPriceList(discountRepo repository.DiscountCampaignRepository, storeIDs []uint, now time.Time) (*priceList, error) {
+	campaigns, err := discountRepo.FindRunning(storeIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	return &priceList{campaigns: campaigns}, nil
+}
+
+// price returns the regular and the charged price of a product, or of its variant when given,
+// with the campaign giving the lowest price; the campaign is nil when no discount applies.
+func (l *priceList) price(product *domain.Product, variant *domain.ProductVariant) (float64, float64, *domain.DiscountCampaign) {
+	regular := product.Price
+	if variant != nil {
+		regular = variant.EffectivePrice(product)
+	}
+
+	best := regular
+	var campaign *domain.DiscountCampaign
+	for i := range l.campaigns {
+		if !l.campaigns[i].Covers(product, variant) {
+			continue
+		}
+		if price := l.campaigns[i].PriceFor(regular); price < best {
+			best, campaign = price, &l.campaigns[i]
+		}
+	}
+	return regular, best, campaign
+}
+
+// apply sets the sale price of a product and of its loaded variants.
+func (l *priceList) apply(product *domain.Product) {
+	if _, price, campaign := l.price(product, nil); campaign != nil {
+		product.SalePrice, product.Discount = &price, campaign.Applied()
+	}
+	for i := range product.Variants {
+		variant := &product.Variants[i]
+		if _, price, campaign := l.price(product, variant); campaign != nil {
+			variant.SalePrice, variant.Discount = &price, campaign.Applied()
+		}
+	}
+}
+
+// applyDiscounts sets the sale prices of listed products from the campaigns running now.
+func applyDiscounts(discountRepo repository.DiscountCampaignRepository, products []domain.Product) error {
+	seen := make(map[uint]bool)
+	var storeIDs []uint
+	for _, product := range products {
+		if !seen[product.StoreID] {
+			seen[product.StoreID] = true
+			storeIDs = append(storeIDs, product.StoreID)
+		}
+	}
+	if len(storeIDs) == 0 {
+		return nil
+	}
+
+	list, err := loadPriceList(discountRepo, storeIDs, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := range products {
+		list.apply(&products[i])
+	}
+	return nil
+}
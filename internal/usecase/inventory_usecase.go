@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"errors"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// InventoryUseCase defines the interface for seller stock management through the inventory ledger.
+type InventoryUseCase interface {
+	AdjustStock(movement *domain.StockMovement, userID uint) (bool, error)
+	GetStockMovements(filter domain.StockMovementFilter, userID uint) ([]domain.StockMovement, domain.PageInfo, error)
+	GetLowStock(storeID, userID uint) ([]domain.LowStockItem, error)
+}
+
+// inventoryUseCase implements the InventoryUseCase interface.
+type inventoryUseCase struct {
+	stockRepo    repository.StockMovementRepository
+	productRepo  repository.ProductRepository
+	variantRepo  repository.ProductVariantRepository
+	storeRepo    repository.StoreRepository
+	staffRepo    repository.StoreStaffRepository
+	settingsRepo repository.StoreSettingsRepository
+	lowStock     lowStockNotifier
+}
+
+// NewInventoryUseCase creates a new instance of InventoryUseCase.
+func NewInventoryUseCase(
+	stockRepo repository.StockMovementRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	storeRepo repository.StoreRepository,
+	staffRepo repository.StoreStaffRepository,
+	settingsRepo repository.StoreSettingsRepository,
+	notificationRepo repository.NotificationRepository,
+) InventoryUseCase {
+	return &inventoryUseCase{
+		stockRepo:    stockRepo,
+		productRepo:  productRepo,
+		variantRepo:  variantRepo,
+		storeRepo:    storeRepo,
+		staffRepo:    staffRepo,
+		settingsRepo: settingsRepo,
+		lowStock:     lowStockNotifier{notificationRepo: notificationRepo, storeRepo: storeRepo, settingsRepo: settingsRepo},
+	}
+}
+
+// AdjustStock records a restock, return or adjustment by the seller and applies it to the stock.
+// Sales and cancellations are only recorded by checkout. It reports whether the stock is now
+// at or below the store's low-stock threshold; the owner is notified when it falls to it.
+func (uc *inventoryUseCase) AdjustStock(movement *domain.StockMovement, userID uint) (bool, error) {
+	product, err := uc.productRepo.FindByID(movement.ProductID)
+	if err != nil {
+		return false, errors.New("product not found")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return false, err
+	}
+
+	switch movement.Type {
+	case domain.StockMovementRestock, domain.StockMovementReturn:
+		if movement.Quantity <= 0 {
+			return false, errors.New("quantity must be greater than 0 for a " + movement.Type)
+		}
+	case domain.StockMovementAdjustment:
+		if movement.Quantity == 0 {
+			return false, errors.New("quantity of an adjustment cannot be 0")
+		}
+		if movement.Reason == "" {
+			return false, errors.New("reason is required for an adjustment")
+		}
+	default:
+		return false, errors.New("type must be restock, return or adjustment")
+	}
+
+	// Products with variants keep their stock per variant
+	variants, err := uc.variantRepo.FindVariantsByProductID(product.ID, false)
+	if err != nil {
+		return false, err
+	}
+	if len(variants) > 0 && movement.VariantID == nil {
+		return false, errors.New("choose a variant of the product")
+	}
+	var variant *domain.ProductVariant
+	if movement.VariantID != nil {
+		for i := range variants {
+			if variants[i].ID == *movement.VariantID {
+				variant = &variants[i]
+			}
+		}
+		if variant == nil {
+			return false, errors.New("variant not found for product")
+		}
+	}
+
+	movement.ActorID = &userID
+	movement.TransactionID = nil
+	if err := uc.stockRepo.Apply(movement); err != nil {
+		if errors.Is(err, repository.ErrInsufficientStock) {
+			return false, errors.New("the stock cannot become negative")
+		}
+		return false, err
+	}
+
+	return uc.lowStock.notify(product, variant, movement), nil
+}
+
+// GetStockMovements retrieves the stock movement history of a product, newest first.
+func (uc *inventoryUseCase) GetStockMovements(filter domain.StockMovementFilter, userID uint) ([]domain.StockMovement, domain.PageInfo, error) {
+	product, err := uc.productRepo.FindByID(filter.ProductID)
+	if err != nil {
+		return nil, domain.PageInfo{}, errors.New("product not found")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	if filter.Type != "" && !domain.IsValidStockMovementType(filter.Type) {
+		return nil, domain.PageInfo{}, errors.New("invalid stock movement type")
+	}
+	return uc.stockRepo.FindAll(filter)
+}
+
+// GetLowStock lists the products and variants of a store at or below its low-stock threshold.
+func (uc *inventoryUseCase) GetLowStock(storeID, userID uint) ([]domain.LowStockItem, error) {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+	return uc.stockRepo.FindLowStock(storeID, findStoreSettings(uc.settingsRepo, storeID).LowStockThreshold)
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// NotificationUseCase defines the interface for the notification inbox of users.
+type NotificationUseCase interface {
+	GetNotifications(userID uint, unreadOnly bool) ([]domain.Notification, error)
+	MarkRead(id, userID uint) error
+}
+
+// notificationUseCase implements the NotificationUseCase interface.
+type notificationUseCase struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewNotificationUseCase creates a new instance of NotificationUseCase.
+func NewNotificationUseCase(notificationRepo repository.NotificationRepository) NotificationUseCase {
+	return &notificationUseCase{notificationRepo: notificationRepo}
+}
+
+// GetNotifications retrieves the latest notifications of a user, newest first.
+func (uc *notificationUseCase) GetNotifications(userID uint, unreadOnly bool) ([]domain.Notification, error) {
+	return uc.notificationRepo.FindByUserID(userID, unreadOnly)
+}
+
+// MarkRead marks a notification of the user as read.
+func (uc *notificationUseCase) MarkRead(id, userID uint) error {
+	if err := uc.notificationRepo.MarkRead(id, userID); err != nil {
+		return errors.New("notification not found")
+	}
+	return nil
+}
+
+// lowStockNotifier notifies store owners when stock runs low.
+type lowStockNotifier struct {
+	notificationRepo repository.NotificationRepository
+	storeRepo        repository.StoreRepository
+	settingsRepo     repository.StoreSettingsRepository
+}
+
+// notify notifies the owner of the product's store when the movement takes the stock of the product,
+// or of its variant, to the store's low-stock threshold. Only the movement crossing the threshold
+// notifies, so later sales don't repeat it. It reports whether the stock is at or below the threshold.
+// Failing to notify is logged, as the stock change itself has been made.
+func (n *lowStockNotifier) notify(product *domain.Product, variant *domain.ProductVariant, movement *domain.StockMovement) bool {
+	stock := movement.StockAfter
+	if movement.VariantStockAfter != nil {
+		stock = *movement.VariantStockAfter
+	}
+	threshold := findStoreSettings(n.settingsRepo, product.StoreID).LowStockThreshold
+	if stock > threshold || stock-movement.Quantity <= threshold {
+		return stock <= threshold
+	}
+
+	store, err := n.storeRepo.FindByID(product.StoreID)
+	if err != nil {
+		log.Printf("low stock: failed to find store %d: %v", product.StoreID, err)
+		return true
+	}
+	name := product.Name
+	notification := &domain.Notification{
+		UserID:    store.UserID,
+		Type:      domain.NotificationLowStock,
+		StoreID:   &store.ID,
+		ProductID: &product.ID,
+	}
+	if variant != nil {
+		name += " (" + variant.Name + ")"
+		notification.VariantID = &variant.ID
+	}
+	notification.Message = fmt.Sprintf("%s is running low: %d left", name, stock)
+	if err := n.notificationRepo.Create(notification); err != nil {
+		log.Printf("low stock: failed to notify the owner of store %d: %v", store.ID, err)
+	}
+	return true
+}
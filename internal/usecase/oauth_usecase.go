@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/jwt"
+	"mini-project-ostore/pkg/oauth"
+)
+
+// OAuthUseCase defines the interface for social login through external providers.
+type OAuthUseCase interface {
+	AuthCodeURL(provider, state string) (string, error)
+	Login(ctx context.Context, provider, code string) (*LoginResult, error)
+	LinkCodeURL(provider string, userID uint) (authURL, state string, err error)
+	Link(ctx context.Context, provider, code, state string) error
+}
+
+// oauthUseCase implements the OAuthUseCase interface.
+type oauthUseCase struct {
+	userRepo     repository.UserRepository
+	identityRepo repository.UserIdentityRepository
+	providers    map[string]oauth.Provider
+}
+
+// NewOAuthUseCase creates a new instance of OAuthUseCase for the given providers.
+func NewOAuthUseCase(userRepo repository.UserRepository, identityRepo repository.UserIdentityRepository, providers ...oauth.Provider) OAuthUseCase {
+	byName := make(map[string]oauth.Provider, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+	}
+	return &oauthUseCase{userRepo: userRepo, identityRepo: identityRepo, providers: byName}
+}
+
+// AuthCodeURL returns the provider consent URL for logging in, carrying the given state.
+func (uc *oauthUseCase) AuthCodeURL(provider, state string) (string, error) {
+	p, ok := uc.providers[provider]
+	if !ok {
+		return "", errors.New("unsupported login provider")
+	}
+	return p.AuthCodeURL(state, oauth.FlowLogin), nil
+}
+
+// Login completes the provider flow the same way as AuthUseCase.Login.
+// A known identity logs in its linked user; otherwise the identity is linked to the
+// existing user with the same email, as long as the provider verified that email.
+// The provider's verification also marks the local email verified.
+// Accounts are not created here because users must register with a phone number.
+func (uc *oauthUseCase) Login(ctx context.Context, provider, code string) (*LoginResult, error) {
+	p, ok := uc.providers[provider]
+	if !ok {
+		return nil, errors.New("unsupported login provider")
+	}
+
+	info, err := p.Exchange(ctx, code, oauth.FlowLogin)
+	if err != nil {
+		return nil, errors.New("failed to authenticate with provider")
+	}
+
+	var user *domain.User
+	identity, err := uc.identityRepo.FindByProviderSubject(provider, info.Subject)
+	if err == nil {
+		user, err = uc.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, errors.New("user not found")
+		}
+	} else {
+		if !info.EmailVerified || info.Email == "" {
+			return nil, errors.New("provider email is not verified")
+		}
+
+		user, err = uc.userRepo.FindByEmail(info.Email)
+		if err != nil {
+			return nil, errors.New("no account registered for this email, please register first")
+		}
+
+		if err := uc.identityRepo.Create(&domain.UserIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  info.Subject,
+			Email:    info.Email,
+		}); err != nil {
+			return nil, err
+		}
+		if err := uc.markEmailVerified(user, info); err != nil {
+			return nil, err
+		}
+	}
+
+	return completeLogin(user)
+}
+
+// LinkCodeURL returns the provider consent URL for linking the provider to the logged-in user,
+// and its state, which identifies the user on the link callback.
+func (uc *oauthUseCase) LinkCodeURL(provider string, userID uint) (string, string, error) {
+	p, ok := uc.providers[provider]
+	if !ok {
+		return "", "", errors.New("unsupported login provider")
+	}
+	state, err := jwt.GenerateLinkState(userID)
+	if err != nil {
+		return "", "", errors.New("failed to generate state")
+	}
+	return p.AuthCodeURL(state, oauth.FlowLink), state, nil
+}
+
+// Link completes the link flow started with LinkCodeURL, linking the provider identity to the
+// user of the state. When the provider verified the user's own email, the email is marked verified.
+func (uc *oauthUseCase) Link(ctx context.Context, provider, code, state string) error {
+	p, ok := uc.providers[provider]
+	if !ok {
+		return errors.New("unsupported login provider")
+	}
+
+	claims, err := jwt.ValidateLinkState(state)
+	if err != nil {
+		return errors.New("invalid or expired OAuth state")
+	}
+	user, err := uc.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	info, err := p.Exchange(ctx, code, oauth.FlowLink)
+	if err != nil {
+		return errors.New("failed to authenticate with provider")
+	}
+
+	identity, err := uc.identityRepo.FindByProviderSubject(provider, info.Subject)
+	if err == nil {
+		if identity.UserID != user.ID {
+			return errors.New("this provider account is already linked to another user")
+		}
+	} else if err := uc.identityRepo.Create(&domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  info.Subject,
+		Email:    info.Email,
+	}); err != nil {
+		return err
+	}
+
+	return uc.markEmailVerified(user, info)
+}
+
+// markEmailVerified marks the user's email verified when the provider verified that same email.
+func (uc *oauthUseCase) markEmailVerified(user *domain.User, info *oauth.UserInfo) error {
+	if !info.EmailVerified || !strings.EqualFold(info.Email, user.Email) || user.EmailVerifiedAt != nil {
+		return nil
+	}
+	now := time.Now()
+	user.EmailVerifiedAt = &now
+	return uc.userRepo.Update(user)
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/oauth"
+
+	"gorm.io/gorm"
+)
+
+// fakeUserRepo keeps users in memory; methods the OAuth flow doesn't use panic through the nil interface.
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[uint]*domain.User
+}
+
+func (r *fakeUserRepo) FindByID(id uint) (*domain.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) FindByEmail(email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeUserRepo) Update(user *domain.User) error {
+	r.users[user.ID] = user
+	return nil
+}
+
+type fakeIdentityRepo struct {
+	identities []domain.UserIdentity
+}
+
+func (r *fakeIdentityRepo) Create(identity *domain.UserIdentity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) FindByProviderSubject(provider, subject string) (*domain.UserIdentity, error) {
+	for i := range r.identities {
+		if r.identities[i].Provider == provider && r.identities[i].Subject == subject {
+			return &r.identities[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// fakeAuthorize runs the consent step of the fake provider and returns the authorization code.
+func fakeAuthorize(t *testing.T, server *httptest.Server, email string, emailVerified bool) string {
+	t.Helper()
+	q := url.Values{}
+	q.Set("login_hint", email)
+	q.Set("redirect_uri", "http://localhost/callback")
+	if !emailVerified {
+		q.Set("email_verified", "false")
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(server.URL + "/authorize?" + q.Encode())
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize redirect: %v", err)
+	}
+	return location.Query().Get("code")
+}
+
+func TestOAuthLogin(t *testing.T) {
+	fake := oauth.NewFakeOIDCServer()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	provider := oauth.NewOIDCProvider(fake.Config("fake", server.URL, "http://localhost/callback", "http://localhost/link/callback"))
+
+	verifiedAt := time.Now()
+	newUseCase := func() (*fakeUserRepo, *fakeIdentityRepo, OAuthUseCase) {
+		users := &fakeUserRepo{users: map[uint]*domain.User{
+			1: {ID: 1, Email: "linked@example.com"},
+			2: {ID: 2, Email: "verified@example.com", EmailVerifiedAt: &verifiedAt},
+			3: {ID: 3, Email: "unverified@example.com"},
+		}}
+		identities := &fakeIdentityRepo{}
+		return users, identities, NewOAuthUseCase(users, identities, provider)
+	}
+
+	t.Run("known identity logs in its user", func(t *testing.T) {
+		_, identities, uc := newUseCase()
+		code := fakeAuthorize(t, server, "linked@example.com", false)
+		info, err := provider.Exchange(context.Background(), code, oauth.FlowLogin)
+		if err != nil {
+			t.Fatalf("exchange: %v", err)
+		}
+		identities.identities = append(identities.identities, domain.UserIdentity{UserID: 1, Provider: "fake", Subject: info.Subject})
+
+		// The provider remembers the subject per email, so a second consent maps to the same identity
+		result, err := uc.Login(context.Background(), "fake", fakeAuthorize(t, server, "linked@example.com", false))
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if result.User.ID != 1 || result.Token == "" {
+			t.Fatalf("Login returned user %d, token %q; want user 1 with a token", result.User.ID, result.Token)
+		}
+	})
+
+	t.Run("verified email links the identity", func(t *testing.T) {
+		_, identities, uc := newUseCase()
+		result, err := uc.Login(context.Background(), "fake", fakeAuthorize(t, server, "verified@example.com", true))
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if result.User.ID != 2 {
+			t.Fatalf("Login returned user %d; want 2", result.User.ID)
+		}
+		if len(identities.identities) != 1 || identities.identities[0].UserID != 2 {
+			t.Fatalf("identities = %+v; want one identity for user 2", identities.identities)
+		}
+	})
+
+	t.Run("provider verification links and verifies the local email", func(t *testing.T) {
+		users, identities, uc := newUseCase()
+		result, err := uc.Login(context.Background(), "fake", fakeAuthorize(t, server, "unverified@example.com", true))
+		if err != nil {
+			t.Fatalf("Login: %v", err)
+		}
+		if result.User.ID != 3 {
+			t.Fatalf("Login returned user %d; want 3", result.User.ID)
+		}
+		if len(identities.identities) != 1 || identities.identities[0].UserID != 3 {
+			t.Fatalf("identities = %+v; want one identity for user 3", identities.identities)
+		}
+		if users.users[3].EmailVerifiedAt == nil {
+			t.Fatal("email of user 3 not marked verified")
+		}
+	})
+
+	t.Run("unverified provider email is rejected", func(t *testing.T) {
+		_, identities, uc := newUseCase()
+		_, err := uc.Login(context.Background(), "fake", fakeAuthorize(t, server, "verified@example.com", false))
+		if err == nil || !strings.Contains(err.Error(), "not verified") {
+			t.Fatalf("Login error = %v; want provider email not verified", err)
+		}
+		if len(identities.identities) != 0 {
+			t.Fatalf("identities = %+v; want none", identities.identities)
+		}
+	})
+
+	t.Run("unknown email is rejected", func(t *testing.T) {
+		_, identities, uc := newUseCase()
+		_, err := uc.Login(context.Background(), "fake", fakeAuthorize(t, server, "nobody@example.com", true))
+		if err == nil || !strings.Contains(err.Error(), "no account registered") {
+			t.Fatalf("Login error = %v; want no account registered", err)
+		}
+		if len(identities.identities) != 0 {
+			t.Fatalf("identities = %+v; want none", identities.identities)
+		}
+	})
+
+	t.Run("linking while logged in verifies the email", func(t *testing.T) {
+		users, identities, uc := newUseCase()
+		authURL, state, err := uc.LinkCodeURL("fake", 3)
+		if err != nil {
+			t.Fatalf("LinkCodeURL: %v", err)
+		}
+		if !strings.Contains(authURL, url.QueryEscape("http://localhost/link/callback")) {
+			t.Fatalf("LinkCodeURL = %q; want the link callback as redirect", authURL)
+		}
+		if err := uc.Link(context.Background(), "fake", fakeAuthorize(t, server, "unverified@example.com", true), state); err != nil {
+			t.Fatalf("Link: %v", err)
+		}
+		if len(identities.identities) != 1 || identities.identities[0].UserID != 3 {
+			t.Fatalf("identities = %+v; want one identity for user 3", identities.identities)
+		}
+		if users.users[3].EmailVerifiedAt == nil {
+			t.Fatal("email of user 3 not marked verified")
+		}
+	})
+	t.Run("linking needs the state of a link flow", func(t *testing.T) {
+		_, identities, uc := newUseCase()
+		if err := uc.Link(context.Background(), "fake", fakeAuthorize(t, server, "unverified@example.com", true), "forged"); err == nil {
+			t.Fatal("Link with a forged state succeeded")
+		}
+		if len(identities.identities) != 0 {
+			t.Fatalf("identities = %+v; want none", identities.identities)
+		}
+	})
+}
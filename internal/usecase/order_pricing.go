@@ -0,0 +1,283 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// orderPricer checks and prices the items of an order, for checkout and for its previews.
+type orderPricer struct {
+	productRepo  repository.ProductRepository
+	variantRepo  repository.ProductVariantRepository
+	storeRepo    repository.StoreRepository
+	settingsRepo repository.StoreSettingsRepository
+	discountRepo repository.DiscountCampaignRepository
+	voucherRepo  repository.VoucherRepository
+	couriers     []domain.CourierRate // Shipping rates, for orders shipped to an address
+	taxRate      float64              // Share of the items total after discounts
+}
+
+// orderLine is an item of an order with its product and price.
+type orderLine struct {
+	Product      *domain.Product
+	Variant      *domain.ProductVariant
+	Quantity     int
+	RegularPrice float64
+	Price        float64                  // Charged per unit
+	Campaign     *domain.DiscountCampaign // Giving Price, nil without a discount
+}
+
+// price loads the products of the items and prices them with the best running discount campaign.
+func (p *orderPricer) price(userID uint, items []domain.TransactionItem, now time.Time) ([]orderLine, error) {
+	lines, storeIDs, err := p.load(items, now)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := loadPriceList(p.discountRepo, storeIDs, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		line := &lines[i]
+		line.RegularPrice, line.Price, line.Campaign = prices.price(line.Product, line.Variant)
+	}
+	if err := p.checkFlashSaleLimits(userID, lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// priceQuoted loads the products of the items of a quote and prices them as quoted, with the
+// campaigns of the quote, so they are honoured until it expires.
+func (p *orderPricer) priceQuoted(userID uint, quote *domain.CheckoutQuote, items []domain.TransactionItem, now time.Time) ([]orderLine, error) {
+	lines, _, err := p.load(items, now)
+	if err != nil {
+		return nil, err
+	}
+	campaigns := make(map[uint]*domain.DiscountCampaign)
+	for i, quoted := range quote.Lines() {
+		line := &lines[i]
+		line.RegularPrice, line.Price = quoted.RegularPrice, quoted.Price
+		if quoted.CampaignID == nil {
+			continue
+		}
+		if _, ok := campaigns[*quoted.CampaignID]; !ok {
+			campaign, err := p.discountRepo.FindByID(*quoted.CampaignID)
+			if err != nil {
+				return nil, errors.New("discount of product " + line.Product.Name + " is no longer available")
+			}
+			campaigns[campaign.ID] = campaign
+		}
+		line.Campaign = campaigns[*quoted.CampaignID]
+	}
+	if err := p.checkFlashSaleLimits(userID, lines); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// load loads the products and variants of the items and checks they can be ordered. Only verified
+// stores sell, and stores on vacation don't accept orders; the buyer gets the seller's auto-reply.
+// It returns the stores ordered from.
+func (p *orderPricer) load(items []domain.TransactionItem, now time.Time) ([]orderLine, []uint, error) {
+	lines := make([]orderLine, len(items))
+	checkedStores := make(map[uint]bool)
+	var storeIDs []uint
+	for i, item := range items {
+		product, err := p.productRepo.FindByID(item.ProductID)
+		if err != nil {
+			return nil, nil, errors.New("product not found for item")
+		}
+		if !product.IsPublished(now) {
+			return nil, nil, errors.New("product " + product.Name + " is not available")
+		}
+		variant, err := p.checkVariantSelection(product, item.VariantID)
+		if err != nil {
+			return nil, nil, err
+		}
+		lines[i] = orderLine{Product: product, Variant: variant, Quantity: item.Quantity}
+
+		if checkedStores[product.StoreID] {
+			continue
+		}
+		checkedStores[product.StoreID] = true
+		storeIDs = append(storeIDs, product.StoreID)
+		store, err := p.storeRepo.FindByID(product.StoreID)
+		if err != nil || !store.IsPublic() {
+			return nil, nil, errors.New("product " + product.Name + " is not available")
+		}
+		settings := findStoreSettings(p.settingsRepo, product.StoreID)
+		if settings.OnVacation(now) {
+			return nil, nil, errors.New("store is on vacation: " + settings.VacationMessage)
+		}
+	}
+	return lines, storeIDs, nil
+}
+
+// checkFlashSaleLimits checks the per buyer limit of flash sales up front, counting the earlier orders
+// and the other items of this one. The limit is enforced again when the units are reserved.
+func (p *orderPricer) checkFlashSaleLimits(userID uint, lines []orderLine) error {
+	bought := make(map[uint]int)
+	for _, line := range lines {
+		campaign := line.Campaign
+		if campaign == nil || campaign.Type != domain.DiscountTypeFlashSale {
+			continue
+		}
+		if _, ok := bought[campaign.ID]; !ok {
+			count, err := p.discountRepo.CountUserReserved(campaign.ID, userID)
+			if err != nil {
+				return err
+			}
+			bought[campaign.ID] = count
+		}
+		bought[campaign.ID] += line.Quantity
+		if bought[campaign.ID] > campaign.PerUserLimit {
+			return fmt.Errorf("flash sale %s is limited to %d per buyer", campaign.Name, campaign.PerUserLimit)
+		}
+	}
+	return nil
+}
+
+// quoteShipping lists the shipping options of every store package and charges the chosen courier,
+// or the courier that is cheapest for the whole order.
+func (p *orderPricer) quoteShipping(quote *domain.CheckoutQuote, address *domain.Address, courier string) error {
+	if len(p.couriers) == 0 {
+		return errors.New("no couriers available")
+	}
+	totals := make([]float64, len(p.couriers))
+	for i := range quote.Stores {
+		store := &quote.Stores[i]
+		settings := findStoreSettings(p.settingsRepo, store.StoreID)
+		sameCity := settings.OriginCityID != 0 && settings.OriginCityID == address.CityID
+		for j, rate := range p.couriers {
+			cost := rate.Cost(store.Weight, sameCity)
+			totals[j] += cost
+			store.ShippingOptions = append(store.ShippingOptions, domain.ShippingOption{
+				Courier:       rate.Code,
+				Name:          rate.Name,
+				Cost:          cost,
+				EstimatedDays: settings.MinProcessingDays + rate.Days,
+			})
+		}
+	}
+
+	chosen := -1
+	for j, rate := range p.couriers {
+		if (courier == "" && (chosen < 0 || totals[j] < totals[chosen])) || rate.Code == courier {
+			chosen = j
+		}
+	}
+	if chosen < 0 {
+		return errors.New("courier " + courier + " is not available")
+	}
+
+	quote.Courier = p.couriers[chosen].Code
+	quote.ShippingCost = totals[chosen]
+	for i := range quote.Stores {
+		quote.Stores[i].ShippingCost = quote.Stores[i].ShippingOptions[chosen].Cost
+	}
+	return nil
+}
+
+// shipping works out the shipping of the priced items to the address like a checkout preview does,
+// with the chosen courier or the cheapest one. It returns the courier and the cost.
+func (p *orderPricer) shipping(lines []orderLine, address *domain.Address, courier string) (string, float64, error) {
+	quote := &domain.CheckoutQuote{}
+	storeIndex := make(map[uint]int)
+	for _, line := range lines {
+		i, ok := storeIndex[line.Product.StoreID]
+		if !ok {
+			i = len(quote.Stores)
+			storeIndex[line.Product.StoreID] = i
+			quote.Stores = append(quote.Stores, domain.CheckoutQuoteStore{StoreID: line.Product.StoreID})
+		}
+		quote.Stores[i].Weight += line.Product.Weight * float64(line.Quantity)
+	}
+	if err := p.quoteShipping(quote, address, courier); err != nil {
+		return "", 0, err
+	}
+	return quote.Courier, quote.ShippingCost, nil
+}
+
+// tax returns the tax on the items total after discounts, rounded to cents.
+func (p *orderPricer) tax(amount float64) float64 {
+	return math.Round(amount*p.taxRate*100) / 100
+}
+
+// checkVariantSelection requires a variant of the product when it has variants, and none otherwise.
+// It returns the selected variant.
+func (p *orderPricer) checkVariantSelection(product *domain.Product, variantID *uint) (*domain.ProductVariant, error) {
+	if variantID == nil {
+		variants, err := p.variantRepo.FindVariantsByProductID(product.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(variants) > 0 {
+			return nil, errors.New("choose a variant for product " + product.Name)
+		}
+		return nil, nil
+	}
+
+	variant, err := p.variantRepo.FindVariantByID(*variantID)
+	if err != nil || variant.ProductID != product.ID {
+		return nil, errors.New("variant not found for product " + product.Name)
+	}
+	if !variant.IsAvailable {
+		return nil, errors.New("variant " + variant.Name + " is not available")
+	}
+	return variant, nil
+}
+
+// quoteVoucher checks that a voucher can be used by the buyer on the priced items and works out its discounts.
+// It has no side effects; the voucher limits are enforced again when it is redeemed.
+func (p *orderPricer) quoteVoucher(code string, userID uint, lines []orderLine, shippingCost float64, now time.Time) (*domain.Voucher, *domain.VoucherQuote, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	voucher, err := p.voucherRepo.FindByCode(code)
+	if err != nil {
+		return nil, nil, errors.New("voucher not found")
+	}
+	if now.Before(voucher.StartsAt) {
+		return nil, nil, errors.New("voucher is not valid yet")
+	}
+	if !now.Before(voucher.EndsAt) {
+		return nil, nil, errors.New("voucher has expired")
+	}
+	if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+		return nil, nil, repository.ErrVoucherUsedUp
+	}
+	if voucher.PerUserLimit > 0 {
+		used, err := p.voucherRepo.CountUserRedemptions(voucher.ID, userID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if used >= int64(voucher.PerUserLimit) {
+			return nil, nil, repository.ErrVoucherLimitReached
+		}
+	}
+
+	// Store vouchers only count the items of their store
+	quote := &domain.VoucherQuote{Code: voucher.Code, ShippingCost: shippingCost}
+	for _, line := range lines {
+		amount := line.Price * float64(line.Quantity)
+		quote.Subtotal += amount
+		if voucher.StoreID == nil || *voucher.StoreID == line.Product.StoreID {
+			quote.EligibleSubtotal += amount
+		}
+	}
+	if quote.EligibleSubtotal == 0 {
+		return nil, nil, errors.New("voucher doesn't apply to the items of this order")
+	}
+	if quote.EligibleSubtotal < voucher.MinSpend {
+		return nil, nil, fmt.Errorf("voucher requires a minimum spend of %.2f", voucher.MinSpend)
+	}
+
+	quote.Discount, quote.ShippingDiscount = voucher.Discounts(quote.EligibleSubtotal, shippingCost)
+	quote.Total = quote.Subtotal + shippingCost - quote.Discount - quote.ShippingDiscount
+	return voucher, quote, nil
+}
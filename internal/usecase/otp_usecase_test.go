@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// fakeOTPRepo keeps codes in memory with the conditional updates of the real repository.
+type fakeOTPRepo struct {
+	repository.OTPRepository
+	mu   sync.Mutex
+	otps []domain.OTP
+}
+
+func (r *fakeOTPRepo) FindActive(phone, purpose string) (*domain.OTP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := len(r.otps) - 1; i >= 0; i-- {
+		if r.otps[i].Phone == phone && r.otps[i].Purpose == purpose && r.otps[i].ConsumedAt == nil {
+			otp := r.otps[i]
+			return &otp, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *fakeOTPRepo) Attempt(id uint, maxAttempts int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	otp := &r.otps[id-1]
+	if otp.Attempts >= maxAttempts || otp.ConsumedAt != nil {
+		return repository.ErrOTPClosed
+	}
+	otp.Attempts++
+	return nil
+}
+
+func (r *fakeOTPRepo) Consume(id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	otp := &r.otps[id-1]
+	if otp.ConsumedAt != nil {
+		return repository.ErrOTPClosed
+	}
+	now := time.Now()
+	otp.ConsumedAt = &now
+	return nil
+}
+
+func TestVerifyLoginOTP(t *testing.T) {
+	const phone = "081234567890"
+	newUseCase := func(expiresAt time.Time) (*fakeUserRepo, OTPUseCase) {
+		users := &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1, Phone: phone}}}
+		otps := &fakeOTPRepo{otps: []domain.OTP{{
+			ID:        1,
+			UserID:    1,
+			Phone:     phone,
+			Purpose:   domain.OTPPurposeLogin,
+			CodeHash:  hashOTPCode("123456"),
+			ExpiresAt: expiresAt,
+		}}}
+		return users, NewOTPUseCase(otps, users, nil)
+	}
+
+	t.Run("a code logs in once and verifies the phone", func(t *testing.T) {
+		users, uc := newUseCase(time.Now().Add(otpTTL))
+		result, err := uc.VerifyLoginOTP(phone, "123456")
+		if err != nil {
+			t.Fatalf("VerifyLoginOTP: %v", err)
+		}
+		if result.Token == "" || users.users[1].PhoneVerifiedAt == nil {
+			t.Fatalf("VerifyLoginOTP: token %q, phone verified at %v; want both", result.Token, users.users[1].PhoneVerifiedAt)
+		}
+		if _, err := uc.VerifyLoginOTP(phone, "123456"); err == nil {
+			t.Fatal("a used code logged in again")
+		}
+	})
+
+	t.Run("the code is closed once its attempts are used up", func(t *testing.T) {
+		_, uc := newUseCase(time.Now().Add(otpTTL))
+		for i := 0; i < otpMaxAttempts; i++ {
+			if _, err := uc.VerifyLoginOTP(phone, "000000"); err == nil {
+				t.Fatalf("attempt %d: wrong code logged in", i+1)
+			}
+		}
+		if _, err := uc.VerifyLoginOTP(phone, "123456"); err == nil {
+			t.Fatal("the right code logged in after the attempts were used up")
+		}
+	})
+
+	t.Run("parallel verifications complete one login", func(t *testing.T) {
+		_, uc := newUseCase(time.Now().Add(otpTTL))
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			logins int
+		)
+		// Every guess is right, yet the code completes a single login
+		for i := 0; i < 3*otpMaxAttempts; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := uc.VerifyLoginOTP(phone, "123456"); err == nil {
+					mu.Lock()
+					logins++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		if logins != 1 {
+			t.Fatalf("parallel verifications logged in %d times, want 1", logins)
+		}
+	})
+
+	t.Run("an expired code is refused", func(t *testing.T) {
+		_, uc := newUseCase(time.Now().Add(-time.Second))
+		if _, err := uc.VerifyLoginOTP(phone, "123456"); err == nil {
+			t.Fatal("an expired code logged in")
+		}
+	})
+}
@@ -0,0 +1,496 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/spreadsheet"
+)
+
+// ProductImportUseCase defines the interface for bulk product import and export.
+type ProductImportUseCase interface {
+	StartImport(storeID, userID uint, fileName string, data []byte) (*domain.ProductImportJob, error)
+	GetImportJob(id, userID uint) (*domain.ProductImportJob, error)
+	Export(storeID, userID uint, format string) ([]byte, error)
+}
+
+const (
+	maxImportRows          = 5000
+	maxImportErrors        = 1000 // Further rejected rows are only counted
+	importProgressInterval = 25   // Rows between progress saves
+	maxConcurrentImports   = 2    // Further imports wait for a free slot
+)
+
+// Columns an import file must have; the others are optional
+var requiredImportColumns = []string{"sku", "name", "category_id", "price", "stock"}
+
+// productImportUseCase implements the ProductImportUseCase interface.
+type productImportUseCase struct {
+	importRepo  repository.ProductImportRepository
+	productRepo repository.ProductRepository
+	storeRepo   repository.StoreRepository
+	staffRepo   repository.StoreStaffRepository
+	productUC   ProductUseCase
+
+	slots         chan struct{} // Held by the running imports
+	mu            sync.Mutex
+	importingFrom map[uint]bool // Stores with an import waiting or running
+}
+
+// NewProductImportUseCase creates a new instance of ProductImportUseCase.
+// Imported rows go through productUC.Create, so they get the same checks as products created one by one.
+func NewProductImportUseCase(
+	importRepo repository.ProductImportRepository,
+	productRepo repository.ProductRepository,
+	storeRepo repository.StoreRepository,
+	staffRepo repository.StoreStaffRepository,
+	productUC ProductUseCase,
+) ProductImportUseCase {
+	return &productImportUseCase{
+		importRepo:  importRepo,
+		productRepo: productRepo,
+		storeRepo:   storeRepo,
+		staffRepo:   staffRepo,
+		productUC:   productUC,
+
+		slots:         make(chan struct{}, maxConcurrentImports),
+		importingFrom: make(map[uint]bool),
+	}
+}
+
+// importRow is a non-blank row of an import file with its line number.
+type importRow struct {
+	Line  int
+	Cells []string
+}
+
+// StartImport checks the file layout and queues its rows for import into the store in the background.
+// The returned job can be polled for progress and the per-row error report. A store has one import
+// at a time, and only a few imports run at once across stores; the others wait as pending.
+func (uc *productImportUseCase) StartImport(storeID, userID uint, fileName string, data []byte) (*domain.ProductImportJob, error) {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+
+	format, err := spreadsheet.FormatOf(fileName)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := spreadsheet.Read(format, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("the file is empty")
+	}
+
+	columns, err := importColumns(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var products []importRow
+	for i, cells := range rows[1:] {
+		if !spreadsheet.IsBlankRow(cells) {
+			products = append(products, importRow{Line: i + 2, Cells: cells})
+		}
+	}
+	if len(products) == 0 {
+		return nil, errors.New("the file has no product rows")
+	}
+	if len(products) > maxImportRows {
+		return nil, fmt.Errorf("the file has %d product rows, the maximum is %d", len(products), maxImportRows)
+	}
+
+	if !uc.claimStore(storeID) {
+		return nil, errors.New("an import is already in progress for this store")
+	}
+
+	job := &domain.ProductImportJob{
+		StoreID:   storeID,
+		UserID:    userID,
+		FileName:  fileName,
+		Format:    format,
+		Status:    domain.ProductImportStatusPending,
+		TotalRows: len(products),
+		Errors:    []domain.ProductImportError{},
+	}
+	if err := uc.importRepo.Create(job); err != nil {
+		uc.releaseStore(storeID)
+		return nil, err
+	}
+
+	// The background run works on its own copy so the caller can serialize the returned job safely
+	running := *job
+	running.Errors = []domain.ProductImportError{}
+	go func() {
+		defer uc.releaseStore(storeID)
+		uc.slots <- struct{}{}
+		defer func() { <-uc.slots }()
+		uc.run(&running, columns, products)
+	}()
+
+	return job, nil
+}
+
+// claimStore marks the store as importing, reporting false when it already is.
+func (uc *productImportUseCase) claimStore(storeID uint) bool {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.importingFrom[storeID] {
+		return false
+	}
+	uc.importingFrom[storeID] = true
+	return true
+}
+
+func (uc *productImportUseCase) releaseStore(storeID uint) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.importingFrom, storeID)
+}
+
+// run imports the rows one by one, saving the progress every few rows.
+func (uc *productImportUseCase) run(job *domain.ProductImportJob, columns map[string]int, rows []importRow) {
+	defer func() {
+		if r := recover(); r != nil {
+			job.Status = domain.ProductImportStatusFailed
+			job.Message = fmt.Sprint("import stopped unexpectedly: ", r)
+			uc.finish(job)
+		}
+	}()
+
+	startedAt := time.Now()
+	job.Status = domain.ProductImportStatusRunning
+	job.StartedAt = &startedAt
+	uc.saveProgress(job)
+
+	for _, row := range rows {
+		product, err := parseImportRow(job.StoreID, columns, row.Cells)
+		if err == nil {
+			err = uc.importProduct(product, job.UserID)
+		}
+
+		if err != nil {
+			job.FailedRows++
+			if len(job.Errors) < maxImportErrors {
+				job.Errors = append(job.Errors, domain.ProductImportError{Row: row.Line, SKU: importCell(columns, row.Cells, "sku"), Message: err.Error()})
+			}
+		} else {
+			job.SucceededRows++
+		}
+		job.ProcessedRows++
+
+		if job.ProcessedRows%importProgressInterval == 0 {
+			uc.saveProgress(job)
+		}
+	}
+
+	job.Status = domain.ProductImportStatusCompleted
+	uc.finish(job)
+}
+
+// importProduct creates a parsed product, then sets its options and variants. The stock of a
+// product with variants is theirs, so it starts without stock of its own.
+func (uc *productImportUseCase) importProduct(product *domain.Product, userID uint) error {
+	options, variants := product.Options, product.Variants
+	product.Options, product.Variants = nil, nil
+	if len(variants) > 0 {
+		product.Stock = 0
+	}
+
+	if err := uc.productUC.Create(product, userID); err != nil {
+		return err
+	}
+	if len(options) == 0 && len(variants) == 0 {
+		return nil
+	}
+	if _, err := uc.productUC.SetVariants(product.ID, userID, options, variants); err != nil {
+		return errors.New("product was created without its variants: " + err.Error())
+	}
+	return nil
+}
+
+func (uc *productImportUseCase) finish(job *domain.ProductImportJob) {
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	uc.saveProgress(job)
+}
+
+// saveProgress stores the job state; the import goes on when saving fails, as rows are already committed.
+func (uc *productImportUseCase) saveProgress(job *domain.ProductImportJob) {
+	if err := uc.importRepo.Update(job); err != nil {
+		log.Printf("product import: failed to save progress of job %d: %v", job.ID, err)
+	}
+}
+
+// GetImportJob retrieves an import job of a store the user manages products of.
+func (uc *productImportUseCase) GetImportJob(id, userID uint) (*domain.ProductImportJob, error) {
+	job, err := uc.importRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("import job not found")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, job.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Export writes all products of the store in the import file layout.
+func (uc *productImportUseCase) Export(storeID, userID uint, format string) ([]byte, error) {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+	if _, ok := spreadsheet.ContentTypes[format]; !ok {
+		return nil, errors.New("unsupported export format, use csv or xlsx")
+	}
+
+	products, err := uc.productRepo.FindByStoreID(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(products)+1)
+	rows = append(rows, domain.ProductImportColumns)
+	for _, p := range products {
+		attributes := ""
+		if len(p.Attributes) > 0 {
+			data, err := json.Marshal(p.Attributes)
+			if err != nil {
+				return nil, err
+			}
+			attributes = string(data)
+		}
+		weight := ""
+		if p.Weight > 0 {
+			weight = strconv.FormatFloat(p.Weight, 'f', -1, 64)
+		}
+		options, variants, err := exportVariants(&p)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, []string{
+			p.SKU,
+			p.Slug,
+			p.Name,
+			strconv.FormatUint(uint64(p.CategoryID), 10),
+			p.Description,
+			strconv.FormatFloat(p.Price, 'f', -1, 64),
+			strconv.Itoa(p.Stock),
+			weight,
+			p.Images,
+			strconv.FormatBool(p.IsAvailable),
+			attributes,
+			p.Status,
+			exportTime(p.PublishAt),
+			exportTime(p.UnpublishAt),
+			options,
+			variants,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := spreadsheet.Write(format, &buf, rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// importOption and importVariant are the layouts of the options and variants columns.
+type importOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type importVariant struct {
+	SKU         string            `json:"sku"`
+	Options     map[string]string `json:"options"`
+	Price       *float64          `json:"price,omitempty"`
+	Stock       int               `json:"stock"`
+	Image       string            `json:"image,omitempty"`
+	IsAvailable *bool             `json:"is_available,omitempty"` // Available when left out
+}
+
+// exportVariants returns the options and variants columns of a product, empty without variants.
+func exportVariants(product *domain.Product) (string, string, error) {
+	if len(product.Options) == 0 && len(product.Variants) == 0 {
+		return "", "", nil
+	}
+
+	options := make([]importOption, 0, len(product.Options))
+	for _, option := range product.Options {
+		options = append(options, importOption{Name: option.Name, Values: option.Values})
+	}
+	variants := make([]importVariant, 0, len(product.Variants))
+	for _, variant := range product.Variants {
+		isAvailable := variant.IsAvailable
+		variants = append(variants, importVariant{
+			SKU:         variant.SKU,
+			Options:     variant.Options,
+			Price:       variant.Price,
+			Stock:       variant.Stock,
+			Image:       variant.Image,
+			IsAvailable: &isAvailable,
+		})
+	}
+
+	optionsData, err := json.Marshal(options)
+	if err != nil {
+		return "", "", err
+	}
+	variantsData, err := json.Marshal(variants)
+	if err != nil {
+		return "", "", err
+	}
+	return string(optionsData), string(variantsData), nil
+}
+
+func exportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// importColumns maps the header names (case-insensitive) to their column index.
+func importColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := columns[name]; ok {
+			return nil, errors.New("duplicate column: " + name)
+		}
+		columns[name] = i
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, errors.New("missing column: " + name)
+		}
+	}
+	return columns, nil
+}
+
+func importCell(columns map[string]int, cells []string, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(cells) {
+		return ""
+	}
+	return strings.TrimSpace(cells[i])
+}
+
+// parseImportRow validates a row like the create product request and builds the product.
+func parseImportRow(storeID uint, columns map[string]int, cells []string) (*domain.Product, error) {
+	cell := func(name string) string { return importCell(columns, cells, name) }
+
+	product := &domain.Product{
+		StoreID:     storeID,
+		SKU:         cell("sku"),
+		Slug:        cell("slug"),
+		Name:        cell("name"),
+		Description: cell("description"),
+		Images:      cell("images"),
+		IsAvailable: true,
+	}
+	if n := utf8.RuneCountInString(product.SKU); n < 3 || n > 50 {
+		return nil, errors.New("sku must be 3 to 50 characters")
+	}
+	if n := utf8.RuneCountInString(product.Slug); n > 0 && (n < 3 || n > 255) { // Generated from the name when empty
+		return nil, errors.New("slug must be 3 to 255 characters")
+	}
+	if n := utf8.RuneCountInString(product.Name); n < 3 || n > 200 {
+		return nil, errors.New("name must be 3 to 200 characters")
+	}
+
+	categoryID, err := strconv.ParseUint(cell("category_id"), 10, 32)
+	if err != nil || categoryID == 0 {
+		return nil, errors.New("invalid category_id")
+	}
+	product.CategoryID = uint(categoryID)
+
+	if product.Price, err = strconv.ParseFloat(cell("price"), 64); err != nil || product.Price <= 0 {
+		return nil, errors.New("price must be a number greater than 0")
+	}
+	if product.Stock, err = strconv.Atoi(cell("stock")); err != nil || product.Stock < 0 {
+		return nil, errors.New("stock must be a whole number of at least 0")
+	}
+	if weight := cell("weight"); weight != "" {
+		if product.Weight, err = strconv.ParseFloat(weight, 64); err != nil || product.Weight <= 0 {
+			return nil, errors.New("weight must be a number greater than 0")
+		}
+	}
+	if available := cell("is_available"); available != "" {
+		if product.IsAvailable, err = strconv.ParseBool(available); err != nil {
+			return nil, errors.New("is_available must be true or false")
+		}
+	}
+	if attributes := cell("attributes"); attributes != "" {
+		if err := json.Unmarshal([]byte(attributes), &product.Attributes); err != nil {
+			return nil, errors.New("attributes must be a JSON object")
+		}
+	}
+
+	// The status is checked when the product is created; new products are active by default
+	product.Status = cell("status")
+	if product.PublishAt, err = parseImportTime(cell("publish_at")); err != nil {
+		return nil, errors.New("publish_at must be an RFC 3339 time")
+	}
+	if product.UnpublishAt, err = parseImportTime(cell("unpublish_at")); err != nil {
+		return nil, errors.New("unpublish_at must be an RFC 3339 time")
+	}
+
+	// Options and variants are set once the product is created, see importProduct
+	if options := cell("options"); options != "" {
+		var parsed []importOption
+		if err := json.Unmarshal([]byte(options), &parsed); err != nil {
+			return nil, errors.New("options must be a JSON array")
+		}
+		for _, option := range parsed {
+			product.Options = append(product.Options, domain.ProductOption{Name: option.Name, Values: option.Values})
+		}
+	}
+	if variants := cell("variants"); variants != "" {
+		var parsed []importVariant
+		if err := json.Unmarshal([]byte(variants), &parsed); err != nil {
+			return nil, errors.New("variants must be a JSON array")
+		}
+		for _, variant := range parsed {
+			isAvailable := true
+			if variant.IsAvailable != nil {
+				isAvailable = *variant.IsAvailable
+			}
+			product.Variants = append(product.Variants, domain.ProductVariant{
+				SKU:         variant.SKU,
+				Options:     variant.Options,
+				Price:       variant.Price,
+				Stock:       variant.Stock,
+				Image:       variant.Image,
+				IsAvailable: isAvailable,
+			})
+		}
+	}
+	return product, nil
+}
+
+func parseImportTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
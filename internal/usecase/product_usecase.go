@@ -2,18 +2,30 @@ package usecase
 
 import (
 	"errors"
+	"log"
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/slug"
+	"strings"
+	"time"
 )
 
 // ProductUseCase defines the interface for product-related business logic.
 type ProductUseCase interface {
 	Create(product *domain.Product, userID uint) error
 	GetByID(id uint) (*domain.Product, error)
-	Update(product *domain.Product, userID uint) error
+	GetBySlug(key string) (*domain.Product, error)
+	Update(id uint, changes domain.ProductUpdate, userID uint) error
 	Delete(id uint, userID uint) error
-	GetProducts(filter domain.ProductFilter) ([]domain.Product, int64, error)
-	GetUserProducts(filter domain.ProductFilter) ([]domain.Product, int64, error)
+	GetProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error)
+	GetUserProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error)
+	GetProductFacets(filter domain.ProductFilter) (*domain.ProductFacets, error)
+	SetVariants(productID, userID uint, options []domain.ProductOption, variants []domain.ProductVariant) (*domain.Product, error)
+	SetStatus(id, userID uint, status string, publishAt, unpublishAt *time.Time) (*domain.Product, error)
+
+	// Admin
+	Ban(id uint, reason string) (*domain.Product, error)
+	Unban(id uint) (*domain.Product, error)
 }
 
 // productUseCase implements the ProductUseCase interface.
@@ -22,6 +34,11 @@ type productUseCase struct {
 	storeRepo    repository.StoreRepository
 	userRepo     repository.UserRepository
 	categoryRepo repository.CategoryRepository
+	staffRepo    repository.StoreStaffRepository
+	variantRepo  repository.ProductVariantRepository
+	discountRepo repository.DiscountCampaignRepository
+	searcher     repository.ProductSearcher
+	transactor   repository.Transactor
 }
 
 // NewProductUseCase creates a new instance of ProductUseCase.
@@ -30,29 +47,37 @@ func NewProductUseCase(
 	storeRepo repository.StoreRepository,
 	userRepo repository.UserRepository,
 	categoryRepo repository.CategoryRepository,
+	staffRepo repository.StoreStaffRepository,
+	variantRepo repository.ProductVariantRepository,
+	discountRepo repository.DiscountCampaignRepository,
+	searcher repository.ProductSearcher,
+	transactor repository.Transactor,
 ) ProductUseCase {
 	return &productUseCase{
 		productRepo:  productRepo,
 		storeRepo:    storeRepo,
 		userRepo:     userRepo,
 		categoryRepo: categoryRepo,
+		staffRepo:    staffRepo,
+		variantRepo:  variantRepo,
+		discountRepo: discountRepo,
+		searcher:     searcher,
+		transactor:   transactor,
 	}
 }
 
-// Create creates a new product for a user's store.
+// Create creates a new product for a store the user owns or manages products of.
 func (uc *productUseCase) Create(product *domain.Product, userID uint) error {
-	store, err := uc.storeRepo.FindByID(product.StoreID)
-	if err != nil {
-		return errors.New("store not found for the given StoreID")
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
 	}
 
-	// ✅ Validasi: store harus milik user yang login
-	if store.UserID != userID {
-		return errors.New("unauthorized: cannot create product for another user's store")
+	if err := uc.validateAttributes(product.CategoryID, product.Attributes); err != nil {
+		return err
 	}
 
-	// Check if SKU already exists
-	skuExists, err := uc.productRepo.SKUExists(product.SKU, 0)
+	// Check if SKU already exists in the store
+	skuExists, err := uc.productRepo.SKUExists(product.StoreID, product.SKU, 0)
 	if err != nil {
 		return err
 	}
@@ -60,126 +85,637 @@ func (uc *productUseCase) Create(product *domain.Product, userID uint) error {
 		return errors.New("product SKU already exists")
 	}
 
-	// Check if Slug already exists
-	slugExists, err := uc.productRepo.SlugExists(product.Slug, 0)
-	if err != nil {
+	if err := uc.assignSlug(product, product.Slug); err != nil {
 		return err
 	}
-	if slugExists {
-		return errors.New("product slug already exists")
+
+	// New products are listed right away unless created as a draft or scheduled
+	if product.Status == "" {
+		product.Status = domain.ProductStatusActive
+	}
+	if err := applyPublishStatus(product, product.Status, product.PublishAt, product.UnpublishAt); err != nil {
+		return err
 	}
 
-	return uc.productRepo.Create(product)
+	// The opening stock goes through the inventory ledger like any other stock change,
+	// in the same transaction so a product is never saved without it
+	openingStock := product.Stock
+	product.Stock = 0
+	err = uc.transactor.Transaction(func(repos repository.TxRepositories) error {
+		if err := repos.Products.Create(product); err != nil {
+			return err
+		}
+		if openingStock <= 0 {
+			return nil
+		}
+		movement := &domain.StockMovement{
+			ProductID: product.ID,
+			Type:      domain.StockMovementRestock,
+			Quantity:  openingStock,
+			Reason:    "opening stock",
+			ActorID:   &userID,
+		}
+		if err := repos.StockMovements.Apply(movement); err != nil {
+			return err
+		}
+		product.Stock = movement.StockAfter
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	uc.syncSearchIndex(product)
+	return nil
 }
 
-// GetByID retrieves a product by its ID.
+// GetByID retrieves a published product by its ID, with the store settings shown on the product page
+// (minimum processing days, vacation notice) and the sale prices of running discount campaigns.
 func (uc *productUseCase) GetByID(id uint) (*domain.Product, error) {
-	return uc.productRepo.FindByID(id)
+	product, err := uc.productRepo.FindDetailByID(id)
+	now := time.Now()
+	if err != nil || !product.IsPublished(now) || !product.Store.IsPublic() {
+		return nil, errors.New("product not found")
+	}
+
+	prices, err := loadPriceList(uc.discountRepo, []uint{product.StoreID}, now)
+	if err != nil {
+		return nil, err
+	}
+	prices.apply(product)
+	if product.Store.Settings != nil {
+		product.Store.PublicSettings = product.Store.Settings.Public()
+	}
+	return product, nil
 }
 
-// Update an existing product.
-func (uc *productUseCase) Update(product *domain.Product, userID uint) error {
-	existingProduct, err := uc.productRepo.FindByID(product.ID)
+// GetBySlug retrieves a product by its current slug or by a previous one;
+// the caller can compare the slugs to redirect old links.
+func (uc *productUseCase) GetBySlug(key string) (*domain.Product, error) {
+	id, err := uc.productRepo.FindIDBySlug(key)
 	if err != nil {
-		return errors.New("product not found")
+		return nil, errors.New("product not found")
 	}
+	return uc.GetByID(id)
+}
 
-	store, err := uc.storeRepo.FindByID(existingProduct.StoreID)
+// assignSlug sets the product slug. A requested slug is normalized and must be free; without one,
+// the slug is generated from the name with a numeric suffix ("-2", "-3", ...) on collision.
+// Slugs of digits only are refused, as product links also accept IDs.
+func (uc *productUseCase) assignSlug(product *domain.Product, requested string) error {
+	if requested != "" {
+		normalized := slug.Make(requested)
+		if normalized == "" {
+			return errors.New("invalid product slug")
+		}
+		if slug.IsNumeric(normalized) {
+			return errors.New("product slug cannot be only digits")
+		}
+		exists, err := uc.productRepo.SlugExists(normalized, product.ID)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return errors.New("product slug already exists")
+		}
+		product.Slug = normalized
+		return nil
+	}
+
+	base := slug.Make(product.Name)
+	if base == "" {
+		base = "produk"
+	} else if slug.IsNumeric(base) {
+		base = "produk-" + base
+	}
+	unique, err := slug.Unique(base, func(candidate string) (bool, error) {
+		return uc.productRepo.SlugExists(candidate, product.ID)
+	})
 	if err != nil {
-		return errors.New("store not found for product")
+		return err
 	}
+	product.Slug = unique
+	return nil
+}
 
-	// ✅ Validasi: user hanya boleh ubah produk dari toko miliknya
-	if store.UserID != userID {
-		return errors.New("unauthorized: cannot update product from another user's store")
+// Update applies a partial update to a product; fields left nil in changes are kept.
+func (uc *productUseCase) Update(id uint, changes domain.ProductUpdate, userID uint) error {
+	existingProduct, err := uc.productRepo.FindByID(id)
+	if err != nil {
+		return errors.New("product not found")
+	}
+
+	// ✅ Validasi: owner atau staff dengan izin manage_products
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, existingProduct.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
 	}
 
 	// Field update
-	if product.StoreID != 0 && product.StoreID != existingProduct.StoreID {
-		newStore, err := uc.storeRepo.FindByID(product.StoreID)
-		if err != nil {
-			return errors.New("new StoreID not found")
-		}
-		if newStore.UserID != userID {
-			return errors.New("unauthorized: cannot move product to another user's store")
+	categoryChanged := false
+	storeChanged := false
+	if changes.StoreID != nil && *changes.StoreID != existingProduct.StoreID {
+		if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, *changes.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+			return err
 		}
-		existingProduct.StoreID = product.StoreID
+		existingProduct.StoreID = *changes.StoreID
+		storeChanged = true
 	}
 
-	if product.CategoryID != 0 && product.CategoryID != existingProduct.CategoryID {
-		if _, err := uc.categoryRepo.FindByID(product.CategoryID); err != nil {
+	if changes.CategoryID != nil && *changes.CategoryID != existingProduct.CategoryID {
+		if _, err := uc.categoryRepo.FindByID(*changes.CategoryID); err != nil {
 			return errors.New("new CategoryID not found")
 		}
-		existingProduct.CategoryID = product.CategoryID
+		existingProduct.CategoryID = *changes.CategoryID
+		categoryChanged = true
 	}
 
-	if product.SKU != "" && product.SKU != existingProduct.SKU {
-		exists, _ := uc.productRepo.SKUExists(product.SKU, product.ID)
+	// Attributes are revalidated when they or the category change
+	if changes.Attributes != nil {
+		existingProduct.Attributes = changes.Attributes
+	}
+	if changes.Attributes != nil || categoryChanged {
+		if err := uc.validateAttributes(existingProduct.CategoryID, existingProduct.Attributes); err != nil {
+			return err
+		}
+	}
+
+	// SKUs are unique per store, so a moved product is checked against its new store
+	if (changes.SKU != nil && *changes.SKU != existingProduct.SKU) || storeChanged {
+		if changes.SKU != nil {
+			existingProduct.SKU = *changes.SKU
+		}
+		exists, err := uc.productRepo.SKUExists(existingProduct.StoreID, existingProduct.SKU, existingProduct.ID)
+		if err != nil {
+			return err
+		}
 		if exists {
 			return errors.New("product SKU already exists")
 		}
-		existingProduct.SKU = product.SKU
 	}
 
-	if product.Slug != "" && product.Slug != existingProduct.Slug {
-		exists, _ := uc.productRepo.SlugExists(product.Slug, product.ID)
-		if exists {
-			return errors.New("product slug already exists")
+	// The variants move along, so their SKUs, including those of removed variants, must be free in the new store
+	if storeChanged {
+		variants, err := uc.variantRepo.FindVariantsByProductID(existingProduct.ID, true)
+		if err != nil {
+			return err
+		}
+		for _, variant := range variants {
+			taken, err := uc.variantRepo.VariantSKUExists(existingProduct.StoreID, variant.SKU, existingProduct.ID)
+			if err != nil {
+				return err
+			}
+			productSKUTaken, err := uc.productRepo.SKUExists(existingProduct.StoreID, variant.SKU, existingProduct.ID)
+			if err != nil {
+				return err
+			}
+			if taken || productSKUTaken {
+				return errors.New("variant SKU already exists: " + variant.SKU)
+			}
+		}
+	}
+
+	if changes.Name != nil {
+		existingProduct.Name = *changes.Name
+	}
+
+	// The slug only changes on request; the previous one keeps redirecting to the product
+	previousSlug := existingProduct.Slug
+	if changes.Slug != nil {
+		if err := uc.assignSlug(existingProduct, *changes.Slug); err != nil {
+			return err
+		}
+	}
+
+	if changes.Description != nil {
+		existingProduct.Description = *changes.Description
+	}
+	if changes.Price != nil {
+		existingProduct.Price = *changes.Price
+	}
+
+	// A new stock level is recorded as an adjustment; products with variants keep their stock per variant
+	stockChanged := changes.Stock != nil
+	if stockChanged {
+		variants, err := uc.variantRepo.FindVariantsByProductID(existingProduct.ID, false)
+		if err != nil {
+			return err
+		}
+		if len(variants) > 0 {
+			return errors.New("the stock of a product with variants is set per variant")
+		}
+	}
+
+	if changes.Weight != nil {
+		existingProduct.Weight = *changes.Weight
+	}
+	if changes.Images != nil {
+		existingProduct.Images = *changes.Images
+	}
+	if changes.IsAvailable != nil {
+		existingProduct.IsAvailable = *changes.IsAvailable
+	}
+
+	// The variants of a moved product are saved with it; the adjustment is worked out from the
+	// locked stock, so orders placed meanwhile aren't undone
+	err = uc.transactor.Transaction(func(repos repository.TxRepositories) error {
+		if err := repos.Products.Update(existingProduct); err != nil {
+			return err
+		}
+		if storeChanged {
+			if err := repos.Variants.MoveToStore(existingProduct.ID, existingProduct.StoreID); err != nil {
+				return err
+			}
+		}
+		if !stockChanged {
+			return nil
+		}
+		movement := &domain.StockMovement{
+			ProductID: existingProduct.ID,
+			Type:      domain.StockMovementAdjustment,
+			Reason:    "stock updated",
+			ActorID:   &userID,
+		}
+		if err := repos.StockMovements.SetStock(movement, *changes.Stock); err != nil {
+			return err
+		}
+		existingProduct.Stock = movement.StockAfter
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if existingProduct.Slug != previousSlug {
+		if err := uc.productRepo.RecordSlugChange(existingProduct.ID, previousSlug, existingProduct.Slug); err != nil {
+			log.Printf("product %d: failed to keep previous slug %q: %v", existingProduct.ID, previousSlug, err)
 		}
-		existingProduct.Slug = product.Slug
 	}
+	uc.syncSearchIndex(existingProduct)
+	return nil
+}
 
-	if product.Name != "" {
-		existingProduct.Name = product.Name
+// SetStatus changes the publish status of a product: draft, active, scheduled (with a publish time
+// in the future) or archived. Listed products can also get a time to be hidden again.
+// Banned products can only be changed by an admin.
+func (uc *productUseCase) SetStatus(id, userID uint, status string, publishAt, unpublishAt *time.Time) (*domain.Product, error) {
+	product, err := uc.productRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
 	}
-	if product.Description != "" {
-		existingProduct.Description = product.Description
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
 	}
-	if product.Price != 0 {
-		existingProduct.Price = product.Price
+	if product.Status == domain.ProductStatusBanned {
+		return nil, errors.New("product is banned: " + product.BanReason)
 	}
-	if product.Stock != 0 {
-		existingProduct.Stock = product.Stock
+
+	if err := applyPublishStatus(product, status, publishAt, unpublishAt); err != nil {
+		return nil, err
 	}
-	if product.Weight != 0 {
-		existingProduct.Weight = product.Weight
+	if err := uc.productRepo.Update(product); err != nil {
+		return nil, err
 	}
-	if product.Images != "" {
-		existingProduct.Images = product.Images
+	return product, nil
+}
+
+// applyPublishStatus sets a status chosen by the seller with its schedule. The publish time only
+// applies to scheduled products, and the unpublish time only to the listed (active or scheduled) ones.
+func applyPublishStatus(product *domain.Product, status string, publishAt, unpublishAt *time.Time) error {
+	now := time.Now()
+	switch status {
+	case domain.ProductStatusScheduled:
+		if publishAt == nil || !publishAt.After(now) {
+			return errors.New("publish_at must be in the future for a scheduled product")
+		}
+	case domain.ProductStatusActive:
+		publishAt = nil
+	case domain.ProductStatusDraft, domain.ProductStatusArchived:
+		publishAt, unpublishAt = nil, nil
+	default:
+		return errors.New("status must be draft, active, scheduled or archived")
 	}
 
-	return uc.productRepo.Update(existingProduct)
+	if unpublishAt != nil {
+		if !unpublishAt.After(now) {
+			return errors.New("unpublish_at must be in the future")
+		}
+		if publishAt != nil && !unpublishAt.After(*publishAt) {
+			return errors.New("unpublish_at must be after publish_at")
+		}
+	}
+
+	product.Status = status
+	product.PublishAt = publishAt
+	product.UnpublishAt = unpublishAt
+	return nil
 }
 
-// Delete removes a product if it belongs to the user's store.
+// Ban takes a product down for violating the marketplace rules; the seller sees the reason
+// and can't publish it again until an admin lifts the ban.
+func (uc *productUseCase) Ban(id uint, reason string) (*domain.Product, error) {
+	product, err := uc.productRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Status == domain.ProductStatusBanned {
+		return nil, errors.New("product is already banned")
+	}
+
+	product.Status = domain.ProductStatusBanned
+	product.BanReason = reason
+	product.PublishAt = nil
+	product.UnpublishAt = nil
+	if err := uc.productRepo.Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Unban lifts the ban of a product. It comes back as a draft, so the seller can fix it before publishing.
+func (uc *productUseCase) Unban(id uint) (*domain.Product, error) {
+	product, err := uc.productRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if product.Status != domain.ProductStatusBanned {
+		return nil, errors.New("product is not banned")
+	}
+
+	product.Status = domain.ProductStatusDraft
+	product.BanReason = ""
+	if err := uc.productRepo.Update(product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// Delete removes a product if the user owns or manages products of its store.
 func (uc *productUseCase) Delete(id uint, userID uint) error {
 	product, err := uc.productRepo.FindByID(id)
 	if err != nil {
 		return errors.New("product not found")
 	}
 
-	store, err := uc.storeRepo.FindByID(product.StoreID)
-	if err != nil {
-		return errors.New("store not found for product")
+	// ✅ Validasi: owner atau staff dengan izin manage_products
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
 	}
 
-	// ✅ Validasi: hanya owner store bisa menghapus produk
-	if store.UserID != userID {
-		return errors.New("unauthorized: cannot delete another user's product")
+	if err := uc.productRepo.Delete(id); err != nil {
+		return err
+	}
+	if err := uc.searcher.Remove(id); err != nil {
+		log.Printf("search: failed to remove product %d from index: %v", id, err)
 	}
+	return nil
+}
 
-	return uc.productRepo.Delete(id)
+// syncSearchIndex refreshes the product in the search index. The product is already
+// saved at this point, so a failure is only logged rather than failing the request.
+func (uc *productUseCase) syncSearchIndex(product *domain.Product) {
+	if err := uc.searcher.Index(product); err != nil {
+		log.Printf("search: failed to index product %d: %v", product.ID, err)
+	}
 }
 
 // GetProducts retrieves products based on filters (public endpoint).
-func (uc *productUseCase) GetProducts(filter domain.ProductFilter) ([]domain.Product, int64, error) {
-	return uc.productRepo.GetProducts(filter)
+// Filtering by a category includes the products of its subcategories.
+func (uc *productUseCase) GetProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error) {
+	if err := expandCategoryFilter(uc.categoryRepo, &filter); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return uc.listProducts(filter)
+}
+
+// listProducts lists the products matching the filter with the sale prices of running discount campaigns.
+func (uc *productUseCase) listProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error) {
+	products, page, err := searchProducts(uc.searcher, filter, uc.productRepo.GetProducts)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	if err := applyDiscounts(uc.discountRepo, products); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return products, page, nil
+}
+
+// GetProductFacets counts the products matching the public listing filter per category and price bucket.
+func (uc *productUseCase) GetProductFacets(filter domain.ProductFilter) (*domain.ProductFacets, error) {
+	if err := expandCategoryFilter(uc.categoryRepo, &filter); err != nil {
+		return nil, err
+	}
+	// The facets drop the category and price conditions in turn, so the search hits mustn't be limited by them
+	searchFilter := filter
+	searchFilter.CategoryID, searchFilter.CategoryIDs = 0, nil
+	searchFilter.MinPrice, searchFilter.MaxPrice = 0, 0
+	hits, err := resolveSearch(uc.searcher, &searchFilter)
+	if err != nil {
+		return nil, err
+	}
+	filter.Search, filter.ProductIDs = searchFilter.Search, searchFilter.ProductIDs
+	if hits != nil && len(hits) == 0 {
+		return &domain.ProductFacets{Categories: []domain.CategoryFacet{}, PriceRanges: []domain.PriceRangeFacet{}}, nil
+	}
+	return uc.productRepo.GetProductFacets(filter)
 }
 
 // GetUserProducts retrieves products for stores owned by a specific user.
-func (uc *productUseCase) GetUserProducts(filter domain.ProductFilter) ([]domain.Product, int64, error) {
+// When a StoreID is given, staff with the manage_products permission see that store's products.
+func (uc *productUseCase) GetUserProducts(filter domain.ProductFilter) ([]domain.Product, domain.PageInfo, error) {
 	if _, err := uc.userRepo.FindByID(filter.UserID); err != nil {
-		return nil, 0, errors.New("user not found")
+		return nil, domain.PageInfo{}, errors.New("user not found")
+	}
+	if filter.StoreID != 0 {
+		if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, filter.StoreID, filter.UserID, domain.StorePermissionManageProducts); err != nil {
+			return nil, domain.PageInfo{}, err
+		}
+		// Scope by store instead of owner so staff see the products they manage
+		filter.UserID = 0
+	}
+	if err := expandCategoryFilter(uc.categoryRepo, &filter); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	// Sellers still see their own products while their store is pending or suspended, and their unpublished products
+	filter.IncludeUnverifiedStores = true
+	filter.IncludeUnpublished = true
+	return uc.listProducts(filter)
+}
+
+// Maximum number of search hits within the filtered listing considered for pagination
+const maxSearchHits = 1000
+
+// searchProducts lists products with find, ranking them by relevance through the
+// searcher when the filter has a search query, and attaches the search highlights.
+func searchProducts(searcher repository.ProductSearcher, filter domain.ProductFilter, find func(domain.ProductFilter) ([]domain.Product, domain.PageInfo, error)) ([]domain.Product, domain.PageInfo, error) {
+	hits, err := resolveSearch(searcher, &filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	if hits != nil && len(hits) == 0 {
+		return []domain.Product{}, domain.PageInfo{CountSkipped: filter.SkipCount}, nil
+	}
+
+	products, page, err := find(filter)
+	if err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	for i := range products {
+		if hit, ok := hits[products[i].ID]; ok {
+			products[i].SearchScore = hit.Score
+			products[i].Highlights = hit.Highlights
+		}
+	}
+	return products, page, nil
+}
+
+// resolveSearch replaces the search query of the filter with the IDs of the matching products, best match first.
+// It returns the hits by product ID, which is empty when nothing matches and nil when the filter has no query.
+func resolveSearch(searcher repository.ProductSearcher, filter *domain.ProductFilter) (map[uint]domain.ProductSearchHit, error) {
+	if strings.TrimSpace(filter.Search) == "" {
+		filter.Search = ""
+		return nil, nil
+	}
+
+	hits, err := searcher.Search(*filter, maxSearchHits)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]domain.ProductSearchHit, len(hits))
+	filter.ProductIDs = make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		byID[hit.ProductID] = hit
+		filter.ProductIDs = append(filter.ProductIDs, hit.ProductID)
+	}
+	filter.Search = ""
+	return byID, nil
+}
+
+// validateAttributes checks product attributes against the schema of the category and its ancestors.
+func (uc *productUseCase) validateAttributes(categoryID uint, attributes map[string]interface{}) error {
+	schema, err := categoryAttributeSchema(uc.categoryRepo, categoryID)
+	if err != nil {
+		return errors.New("category not found for the given CategoryID")
+	}
+	return validateProductAttributes(schema, attributes)
+}
+
+// Limits for product options
+const (
+	maxProductOptions  = 3
+	maxProductVariants = 100
+)
+
+// SetVariants replaces the option types and variants of a product. Existing variants are
+// matched by SKU so orders referencing them stay valid. An empty option list removes all variants.
+func (uc *productUseCase) SetVariants(productID, userID uint, options []domain.ProductOption, variants []domain.ProductVariant) (*domain.Product, error) {
+	product, err := uc.productRepo.FindByID(productID)
+	if err != nil {
+		return nil, errors.New("product not found")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, product.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+
+	if len(options) > maxProductOptions {
+		return nil, errors.New("a product can have at most 3 options")
+	}
+	if len(variants) > maxProductVariants {
+		return nil, errors.New("a product can have at most 100 variants")
+	}
+	if len(options) == 0 && len(variants) > 0 {
+		return nil, errors.New("variants require at least one option")
+	}
+	if len(options) > 0 && len(variants) == 0 {
+		return nil, errors.New("at least one variant is required when options are set")
+	}
+
+	// Validate options: unique names, unique non-empty values
+	allowed := make(map[string]map[string]bool, len(options))
+	for i := range options {
+		option := &options[i]
+		option.Name = strings.TrimSpace(option.Name)
+		if option.Name == "" {
+			return nil, errors.New("option name is required")
+		}
+		if allowed[option.Name] != nil {
+			return nil, errors.New("duplicate option name: " + option.Name)
+		}
+		values := make(map[string]bool, len(option.Values))
+		for _, value := range option.Values {
+			if value == "" || values[value] {
+				return nil, errors.New("option " + option.Name + " has an empty or duplicate value")
+			}
+			values[value] = true
+		}
+		allowed[option.Name] = values
+		option.ID = 0
+		option.ProductID = productID
+		option.Position = i
+	}
+
+	existing, err := uc.variantRepo.FindVariantsByProductID(productID, true)
+	if err != nil {
+		return nil, err
+	}
+	existingBySKU := make(map[string]uint, len(existing))
+	for _, variant := range existing {
+		existingBySKU[variant.SKU] = variant.ID
+	}
+
+	// Validate variants: one allowed value per option, unique combinations and SKUs
+	combinations := make(map[string]bool, len(variants))
+	skus := make(map[string]bool, len(variants))
+	for i := range variants {
+		variant := &variants[i]
+		if variant.SKU == "" || skus[variant.SKU] {
+			return nil, errors.New("variant SKU is required and must be unique")
+		}
+		skus[variant.SKU] = true
+		if variant.Stock < 0 {
+			return nil, errors.New("variant stock cannot be negative")
+		}
+		if variant.Price != nil && *variant.Price <= 0 {
+			return nil, errors.New("variant price must be greater than 0")
+		}
+		if len(variant.Options) != len(options) {
+			return nil, errors.New("variant " + variant.SKU + " must have exactly one value for each option")
+		}
+
+		names := make([]string, 0, len(options))
+		for _, option := range options {
+			value, ok := variant.Options[option.Name]
+			if !ok || !allowed[option.Name][value] {
+				return nil, errors.New("variant " + variant.SKU + " has an invalid value for option " + option.Name)
+			}
+			names = append(names, value)
+		}
+		variant.Name = strings.Join(names, " / ")
+		if combinations[variant.Name] {
+			return nil, errors.New("duplicate variant combination: " + variant.Name)
+		}
+		combinations[variant.Name] = true
+
+		if id, ok := existingBySKU[variant.SKU]; ok {
+			variant.ID = id
+		} else {
+			variant.ID = 0
+			taken, err := uc.variantRepo.VariantSKUExists(product.StoreID, variant.SKU, productID)
+			if err != nil {
+				return nil, err
+			}
+			productSKUTaken, err := uc.productRepo.SKUExists(product.StoreID, variant.SKU, 0)
+			if err != nil {
+				return nil, err
+			}
+			if taken || productSKUTaken {
+				return nil, errors.New("variant SKU already exists: " + variant.SKU)
+			}
+		}
+		variant.ProductID = productID
+		variant.StoreID = product.StoreID
+	}
+
+	if err := uc.variantRepo.ReplaceVariants(productID, userID, options, variants); err != nil {
+		return nil, err
 	}
-	return uc.productRepo.GetProducts(filter)
+	return uc.productRepo.FindDetailByID(productID)
 }
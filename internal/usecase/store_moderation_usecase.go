@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// ErrStoreDocumentsDenied is returned when a user other than the store owner manages its KYC documents.
+var ErrStoreDocumentsDenied = errors.New("unauthorized: only the store owner can manage its documents")
+
+// StoreReview bundles a store with its KYC documents and moderation history for admin review.
+type StoreReview struct {
+	Store     *domain.Store               `json:"store"`
+	Documents []domain.StoreDocument      `json:"documents"`
+	Logs      []domain.StoreModerationLog `json:"logs"`
+}
+
+// StoreModerationUseCase defines the interface for seller verification and store moderation.
+type StoreModerationUseCase interface {
+	AuthorizeDocuments(storeID, userID uint) error
+	AddDocument(document *domain.StoreDocument, userID uint) error
+	GetDocuments(storeID, userID uint) ([]domain.StoreDocument, error)
+
+	// Admin
+	GetStores(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error)
+	GetReview(storeID uint) (*StoreReview, error)
+	GetDocument(storeID, documentID uint) (*domain.StoreDocument, error)
+	Moderate(storeID, adminID uint, action, reason string) (*domain.Store, error)
+}
+
+// storeModerationUseCase implements the StoreModerationUseCase interface.
+type storeModerationUseCase struct {
+	storeRepo      repository.StoreRepository
+	moderationRepo repository.StoreModerationRepository
+}
+
+// NewStoreModerationUseCase creates a new instance of StoreModerationUseCase.
+func NewStoreModerationUseCase(storeRepo repository.StoreRepository, moderationRepo repository.StoreModerationRepository) StoreModerationUseCase {
+	return &storeModerationUseCase{storeRepo: storeRepo, moderationRepo: moderationRepo}
+}
+
+// AuthorizeDocuments checks that userID owns the store, as only the owner manages its KYC documents.
+// Uploads are checked before the file is saved.
+func (uc *storeModerationUseCase) AuthorizeDocuments(storeID, userID uint) error {
+	store, err := uc.storeRepo.FindByID(storeID)
+	if err != nil {
+		return errors.New("store not found")
+	}
+	if store.UserID != userID {
+		return ErrStoreDocumentsDenied
+	}
+	return nil
+}
+
+// AddDocument records a KYC document uploaded by the store owner.
+func (uc *storeModerationUseCase) AddDocument(document *domain.StoreDocument, userID uint) error {
+	if err := uc.AuthorizeDocuments(document.StoreID, userID); err != nil {
+		return err
+	}
+
+	switch document.Type {
+	case domain.StoreDocumentKTP, domain.StoreDocumentNPWP, domain.StoreDocumentSIUP, domain.StoreDocumentSelfie:
+	default:
+		return errors.New("invalid document type")
+	}
+
+	return uc.moderationRepo.CreateDocument(document)
+}
+
+// GetDocuments retrieves the KYC documents of a store for its owner.
+func (uc *storeModerationUseCase) GetDocuments(storeID, userID uint) ([]domain.StoreDocument, error) {
+	if err := uc.AuthorizeDocuments(storeID, userID); err != nil {
+		return nil, err
+	}
+	return uc.moderationRepo.FindDocumentsByStoreID(storeID)
+}
+
+// GetStores retrieves stores of any status for admin review.
+func (uc *storeModerationUseCase) GetStores(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error) {
+	return uc.storeRepo.FindAll(filter)
+}
+
+// GetReview retrieves a store with its documents and moderation history.
+func (uc *storeModerationUseCase) GetReview(storeID uint) (*StoreReview, error) {
+	store, err := uc.storeRepo.FindByID(storeID)
+	if err != nil {
+		return nil, errors.New("store not found")
+	}
+
+	documents, err := uc.moderationRepo.FindDocumentsByStoreID(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := uc.moderationRepo.FindLogsByStoreID(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StoreReview{Store: store, Documents: documents, Logs: logs}, nil
+}
+
+// GetDocument retrieves a single KYC document of a store.
+func (uc *storeModerationUseCase) GetDocument(storeID, documentID uint) (*domain.StoreDocument, error) {
+	document, err := uc.moderationRepo.FindDocumentByID(documentID)
+	if err != nil || document.StoreID != storeID {
+		return nil, errors.New("document not found")
+	}
+	return document, nil
+}
+
+// Moderate applies an admin action to a store and logs it with the given reason.
+func (uc *storeModerationUseCase) Moderate(storeID, adminID uint, action, reason string) (*domain.Store, error) {
+	store, err := uc.storeRepo.FindByID(storeID)
+	if err != nil {
+		return nil, errors.New("store not found")
+	}
+
+	var toStatus string
+	switch action {
+	case domain.StoreActionVerify:
+		toStatus = domain.StoreStatusVerified
+	case domain.StoreActionSuspend:
+		toStatus = domain.StoreStatusSuspended
+	case domain.StoreActionRevoke:
+		toStatus = domain.StoreStatusPending
+	default:
+		return nil, errors.New("invalid moderation action")
+	}
+
+	if store.Status == toStatus {
+		return nil, errors.New("store is already " + toStatus)
+	}
+
+	log := &domain.StoreModerationLog{
+		StoreID:    store.ID,
+		AdminID:    adminID,
+		Action:     action,
+		FromStatus: store.Status,
+		ToStatus:   toStatus,
+		Reason:     reason,
+	}
+
+	store.Status = toStatus
+	if toStatus == domain.StoreStatusVerified {
+		now := time.Now()
+		store.VerifiedAt = &now
+	}
+
+	if err := uc.moderationRepo.ApplyAction(store, log); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
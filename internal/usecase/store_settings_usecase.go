@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// StoreSettingsUseCase defines the interface for seller store settings.
+type StoreSettingsUseCase interface {
+	GetSettings(storeID, userID uint) (*domain.StoreSettings, error)
+	UpdateSettings(settings *domain.StoreSettings, userID uint) error
+}
+
+// storeSettingsUseCase implements the StoreSettingsUseCase interface.
+type storeSettingsUseCase struct {
+	settingsRepo repository.StoreSettingsRepository
+	storeRepo    repository.StoreRepository
+}
+
+// NewStoreSettingsUseCase creates a new instance of StoreSettingsUseCase.
+func NewStoreSettingsUseCase(settingsRepo repository.StoreSettingsRepository, storeRepo repository.StoreRepository) StoreSettingsUseCase {
+	return &storeSettingsUseCase{settingsRepo: settingsRepo, storeRepo: storeRepo}
+}
+
+// findStoreSettings returns the settings of a store, or the defaults when none were saved yet.
+func findStoreSettings(settingsRepo repository.StoreSettingsRepository, storeID uint) *domain.StoreSettings {
+	settings, err := settingsRepo.FindByStoreID(storeID)
+	if err != nil {
+		return &domain.StoreSettings{StoreID: storeID, MinProcessingDays: domain.DefaultMinProcessingDays, LowStockThreshold: domain.DefaultLowStockThreshold}
+	}
+	return settings
+}
+
+// GetSettings retrieves the settings of the owner's store.
+func (uc *storeSettingsUseCase) GetSettings(storeID, userID uint) (*domain.StoreSettings, error) {
+	if err := uc.checkOwner(storeID, userID); err != nil {
+		return nil, err
+	}
+	return findStoreSettings(uc.settingsRepo, storeID), nil
+}
+
+// UpdateSettings validates and replaces the settings of the owner's store. A zero low-stock
+// threshold keeps the stored one.
+func (uc *storeSettingsUseCase) UpdateSettings(settings *domain.StoreSettings, userID uint) error {
+	if err := uc.checkOwner(settings.StoreID, userID); err != nil {
+		return err
+	}
+
+	// Keep the same row so created_at is preserved, and the stored low-stock threshold when none is given
+	existing := findStoreSettings(uc.settingsRepo, settings.StoreID)
+	settings.ID = existing.ID
+	settings.CreatedAt = existing.CreatedAt
+	if settings.LowStockThreshold == 0 {
+		settings.LowStockThreshold = existing.LowStockThreshold
+	}
+
+	if settings.MinProcessingDays < 1 {
+		return errors.New("min processing days must be at least 1")
+	}
+	if settings.LowStockThreshold < 1 {
+		return errors.New("low stock threshold must be at least 1")
+	}
+	if err := validateOperatingHours(settings.OperatingHours); err != nil {
+		return err
+	}
+	if settings.VacationMode && settings.VacationMessage == "" {
+		return errors.New("vacation message is required when vacation mode is on")
+	}
+	if settings.VacationUntil != nil && settings.VacationUntil.Before(time.Now()) {
+		return errors.New("vacation end must be in the future")
+	}
+
+	return uc.settingsRepo.Save(settings)
+}
+
+func (uc *storeSettingsUseCase) checkOwner(storeID, userID uint) error {
+	store, err := uc.storeRepo.FindByID(storeID)
+	if err != nil {
+		return errors.New("store not found")
+	}
+	if store.UserID != userID {
+		return errors.New("unauthorized: only the store owner can manage store settings")
+	}
+	return nil
+}
+
+// validateOperatingHours checks that each weekday appears once with a valid "HH:MM" window,
+// normalizing the times so they compare correctly as strings.
+func validateOperatingHours(hours []domain.OperatingHour) error {
+	seen := make(map[time.Weekday]bool)
+	for i, h := range hours {
+		if h.Day < time.Sunday || h.Day > time.Saturday {
+			return errors.New("operating hour day must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		if seen[h.Day] {
+			return errors.New("operating hours contain the same day twice")
+		}
+		seen[h.Day] = true
+
+		openAt, err := time.Parse("15:04", h.Open)
+		if err != nil {
+			return errors.New("operating hour open must be in HH:MM format")
+		}
+		closeAt, err := time.Parse("15:04", h.Close)
+		if err != nil {
+			return errors.New("operating hour close must be in HH:MM format")
+		}
+		if !openAt.Before(closeAt) {
+			return errors.New("operating hour open must be before close")
+		}
+		hours[i].Open = openAt.Format("15:04")
+		hours[i].Close = closeAt.Format("15:04")
+	}
+	return nil
+}
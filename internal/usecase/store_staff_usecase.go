@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// ErrStorePermission is returned when a user lacks the permissions for an action on a store.
+var ErrStorePermission = errors.New("unauthorized")
+
+// authorizeStore returns the store when userID owns it or is active staff holding any of the permissions.
+// It is the single place where store ownership is checked.
+func authorizeStore(storeRepo repository.StoreRepository, staffRepo repository.StoreStaffRepository, storeID, userID uint, permissions ...string) (*domain.Store, error) {
+	store, err := storeRepo.FindByID(storeID)
+	if err != nil {
+		return nil, errors.New("store not found")
+	}
+	if store.UserID == userID {
+		return store, nil
+	}
+
+	staff, err := staffRepo.FindByStoreAndUser(storeID, userID)
+	if err == nil {
+		for _, permission := range permissions {
+			if staff.Can(permission) {
+				return store, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: missing %s permission for this store", ErrStorePermission, strings.Join(permissions, " or "))
+}
+
+// StaffMember is a staff member or pending invitation as listed to the store owner,
+// with only the name of the user's account.
+type StaffMember struct {
+	ID          uint       `json:"id"`
+	UserID      uint       `json:"user_id"`
+	Name        string     `json:"name"`
+	Status      string     `json:"status"`
+	Permissions []string   `json:"permissions"`
+	AcceptedAt  *time.Time `json:"accepted_at"`
+}
+
+// StoreStaffUseCase defines the interface for store staff invitations and permissions.
+type StoreStaffUseCase interface {
+	Invite(storeID, ownerID uint, email string, permissions []string) (*domain.StoreStaff, error)
+	GetStaff(storeID, ownerID uint) ([]StaffMember, error)
+	UpdatePermissions(storeID, staffID, ownerID uint, permissions []string) error
+	Remove(storeID, staffID, ownerID uint) error
+
+	GetInvitations(userID uint) ([]domain.StoreStaff, error)
+	GetMemberships(userID uint) ([]domain.StoreStaff, error)
+	AcceptInvitation(id, userID uint) error
+	DeclineInvitation(id, userID uint) error
+}
+
+// storeStaffUseCase implements the StoreStaffUseCase interface.
+type storeStaffUseCase struct {
+	staffRepo repository.StoreStaffRepository
+	storeRepo repository.StoreRepository
+	userRepo  repository.UserRepository
+}
+
+// NewStoreStaffUseCase creates a new instance of StoreStaffUseCase.
+func NewStoreStaffUseCase(staffRepo repository.StoreStaffRepository, storeRepo repository.StoreRepository, userRepo repository.UserRepository) StoreStaffUseCase {
+	return &storeStaffUseCase{staffRepo: staffRepo, storeRepo: storeRepo, userRepo: userRepo}
+}
+
+// Invite invites a registered user, by email, to join the owner's store as staff.
+func (uc *storeStaffUseCase) Invite(storeID, ownerID uint, email string, permissions []string) (*domain.StoreStaff, error) {
+	store, err := uc.ownedStore(storeID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByEmail(email)
+	if err != nil {
+		return nil, errors.New("no user registered with this email")
+	}
+	if user.ID == store.UserID {
+		return nil, errors.New("the store owner cannot be invited as staff")
+	}
+	if _, err := uc.staffRepo.FindByStoreAndUser(store.ID, user.ID); err == nil {
+		return nil, errors.New("user is already staff or invited to this store")
+	}
+
+	staff := &domain.StoreStaff{
+		StoreID:     store.ID,
+		UserID:      user.ID,
+		InvitedByID: ownerID,
+		Status:      domain.StoreStaffStatusInvited,
+	}
+	staff.SetPermissions(permissions)
+
+	if err := uc.staffRepo.Create(staff); err != nil {
+		return nil, err
+	}
+	return staff, nil
+}
+
+// GetStaff lists the staff and pending invitations of the owner's store.
+func (uc *storeStaffUseCase) GetStaff(storeID, ownerID uint) ([]StaffMember, error) {
+	if _, err := uc.ownedStore(storeID, ownerID); err != nil {
+		return nil, err
+	}
+	staff, err := uc.staffRepo.FindByStoreID(storeID)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]StaffMember, 0, len(staff))
+	for i := range staff {
+		member := &staff[i]
+		members = append(members, StaffMember{
+			ID:          member.ID,
+			UserID:      member.UserID,
+			Name:        member.User.Name,
+			Status:      member.Status,
+			Permissions: member.Permissions(),
+			AcceptedAt:  member.AcceptedAt,
+		})
+	}
+	return members, nil
+}
+
+// UpdatePermissions replaces the permissions of a staff member.
+func (uc *storeStaffUseCase) UpdatePermissions(storeID, staffID, ownerID uint, permissions []string) error {
+	staff, err := uc.ownedStaff(storeID, staffID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	staff.SetPermissions(permissions)
+	return uc.staffRepo.Update(staff)
+}
+
+// Remove revokes a staff membership or pending invitation.
+func (uc *storeStaffUseCase) Remove(storeID, staffID, ownerID uint) error {
+	staff, err := uc.ownedStaff(storeID, staffID, ownerID)
+	if err != nil {
+		return err
+	}
+	return uc.staffRepo.Delete(staff.ID)
+}
+
+// GetInvitations lists the pending store invitations of a user.
+func (uc *storeStaffUseCase) GetInvitations(userID uint) ([]domain.StoreStaff, error) {
+	return uc.staffRepo.FindByUserID(userID, domain.StoreStaffStatusInvited)
+}
+
+// GetMemberships lists the stores a user works at as active staff.
+func (uc *storeStaffUseCase) GetMemberships(userID uint) ([]domain.StoreStaff, error) {
+	return uc.staffRepo.FindByUserID(userID, domain.StoreStaffStatusActive)
+}
+
+// AcceptInvitation activates a pending invitation addressed to the user.
+func (uc *storeStaffUseCase) AcceptInvitation(id, userID uint) error {
+	staff, err := uc.pendingInvitation(id, userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	staff.Status = domain.StoreStaffStatusActive
+	staff.AcceptedAt = &now
+	return uc.staffRepo.Update(staff)
+}
+
+// DeclineInvitation deletes a pending invitation addressed to the user.
+func (uc *storeStaffUseCase) DeclineInvitation(id, userID uint) error {
+	staff, err := uc.pendingInvitation(id, userID)
+	if err != nil {
+		return err
+	}
+	return uc.staffRepo.Delete(staff.ID)
+}
+
+// ownedStore returns the store if it is owned by ownerID. Staff management is reserved to owners.
+func (uc *storeStaffUseCase) ownedStore(storeID, ownerID uint) (*domain.Store, error) {
+	store, err := uc.storeRepo.FindByID(storeID)
+	if err != nil {
+		return nil, errors.New("store not found")
+	}
+	if store.UserID != ownerID {
+		return nil, errors.New("unauthorized: only the store owner can manage staff")
+	}
+	return store, nil
+}
+
+func (uc *storeStaffUseCase) ownedStaff(storeID, staffID, ownerID uint) (*domain.StoreStaff, error) {
+	if _, err := uc.ownedStore(storeID, ownerID); err != nil {
+		return nil, err
+	}
+
+	staff, err := uc.staffRepo.FindByID(staffID)
+	if err != nil || staff.StoreID != storeID {
+		return nil, errors.New("staff member not found")
+	}
+	return staff, nil
+}
+
+func (uc *storeStaffUseCase) pendingInvitation(id, userID uint) (*domain.StoreStaff, error) {
+	staff, err := uc.staffRepo.FindByID(id)
+	if err != nil || staff.UserID != userID {
+		return nil, errors.New("invitation not found")
+	}
+	if staff.Status != domain.StoreStaffStatusInvited {
+		return nil, errors.New("invitation has already been accepted")
+	}
+	return staff, nil
+}
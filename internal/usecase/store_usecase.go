@@ -2,29 +2,37 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/slug"
 )
 
+// ErrStoreAccessDenied is returned when a user may not see the private details of a store.
+var ErrStoreAccessDenied = errors.New("unauthorized: only the store owner or an admin can view this store")
+
 // StoreUseCase defines the interface for store-related business logic.
 type StoreUseCase interface {
 	Create(store *domain.Store) error
 	GetByID(id uint) (*domain.Store, error)
-	Update(store *domain.Store) error
+	GetByIDForUser(id, userID uint) (*domain.Store, error)
+	Update(id uint, changes domain.StoreUpdate) error
 	Delete(id uint) error
 	GetByUserID(userID uint) ([]domain.Store, error)
-	GetStores(filter domain.StoreFilter) ([]domain.Store, int64, error)
+	GetStores(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error)
 }
 
 // storeUseCase implements the StoreUseCase interface.
 type storeUseCase struct {
-	storeRepo repository.StoreRepository
-	userRepo  repository.UserRepository // For validating UserID if needed
+	storeRepo        repository.StoreRepository
+	userRepo         repository.UserRepository // For validating UserID if needed
+	maxStoresPerUser int
 }
 
 // NewStoreUseCase creates a new instance of StoreUseCase.
-func NewStoreUseCase(storeRepo repository.StoreRepository, userRepo repository.UserRepository) StoreUseCase {
-	return &storeUseCase{storeRepo: storeRepo, userRepo: userRepo}
+// maxStoresPerUser caps how many stores one user may own; zero means unlimited.
+func NewStoreUseCase(storeRepo repository.StoreRepository, userRepo repository.UserRepository, maxStoresPerUser int) StoreUseCase {
+	return &storeUseCase{storeRepo: storeRepo, userRepo: userRepo, maxStoresPerUser: maxStoresPerUser}
 }
 
 // Create a new store.
@@ -35,39 +43,89 @@ func (uc *storeUseCase) Create(store *domain.Store) error {
 	if err != nil {
 		return errors.New("user not found for the given UserID")
 	}
+
+	if uc.maxStoresPerUser > 0 {
+		count, err := uc.storeRepo.CountByUserID(store.UserID)
+		if err != nil {
+			return err
+		}
+		if count >= int64(uc.maxStoresPerUser) {
+			return fmt.Errorf("store limit reached: a user can own at most %d stores", uc.maxStoresPerUser)
+		}
+	}
+
+	if err := assignStoreSlug(uc.storeRepo, store); err != nil {
+		return err
+	}
 	return uc.storeRepo.Create(store)
 }
 
+// assignStoreSlug generates a unique slug for a new store from its name.
+func assignStoreSlug(storeRepo repository.StoreRepository, store *domain.Store) error {
+	base := slug.Make(store.Name)
+	if base == "" {
+		base = "toko"
+	} else if slug.IsNumeric(base) {
+		base = "toko-" + base
+	}
+
+	unique, err := slug.Unique(base, func(candidate string) (bool, error) {
+		return storeRepo.SlugExists(candidate, store.ID)
+	})
+	if err != nil {
+		return err
+	}
+	store.Slug = unique
+	return nil
+}
+
 // GetByID retrieves a store by its ID.
 func (uc *storeUseCase) GetByID(id uint) (*domain.Store, error) {
 	return uc.storeRepo.FindByID(id)
 }
 
-// Update an existing store.
-func (uc *storeUseCase) Update(store *domain.Store) error {
+// GetByIDForUser retrieves a store by its ID for its owner or an admin, whatever its status,
+// so owners can follow their stores through review.
+func (uc *storeUseCase) GetByIDForUser(id, userID uint) (*domain.Store, error) {
+	store, err := uc.storeRepo.FindByID(id)
+	if err != nil {
+		return nil, errors.New("store not found")
+	}
+	if store.UserID == userID {
+		return store, nil
+	}
+
+	// The admin role is read from the database, as it may have been revoked since the token was issued
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil || !user.IsAdmin {
+		return nil, ErrStoreAccessDenied
+	}
+	return store, nil
+}
+
+// Update applies a partial update to a store; fields left nil in changes are kept.
+func (uc *storeUseCase) Update(id uint, changes domain.StoreUpdate) error {
 	// First, check if the store exists.
-	existingStore, err := uc.storeRepo.FindByID(store.ID)
+	existingStore, err := uc.storeRepo.FindByID(id)
 	if err != nil {
 		return errors.New("store not found")
 	}
 
-	// Update only the mutable fields if they are provided in the update request.
-	// This prevents overwriting existing data with empty strings if a field is not sent.
-	if store.Name != "" {
-		existingStore.Name = store.Name
+	// Update only the fields present in the request, so they can also be cleared on purpose.
+	if changes.Name != nil {
+		existingStore.Name = *changes.Name
 	}
-	if store.Description != "" {
-		existingStore.Description = store.Description
+	if changes.Description != nil {
+		existingStore.Description = *changes.Description
 	}
-	if store.Address != "" {
-		existingStore.Address = store.Address
+	if changes.Address != nil {
+		existingStore.Address = *changes.Address
 	}
-	if store.Phone != "" {
-		existingStore.Phone = store.Phone
+	if changes.Phone != nil {
+		existingStore.Phone = *changes.Phone
 	}
-	// Update PhotoProfile only if a new photo is provided
-	if store.PhotoProfile != "" {
-		existingStore.PhotoProfile = store.PhotoProfile
+	if changes.PhotoProfile != nil {
+		existingStore.PhotoProfile = *changes.PhotoProfile
 	}
 
 	return uc.storeRepo.Update(existingStore)
@@ -94,6 +152,6 @@ func (uc *storeUseCase) GetByUserID(userID uint) ([]domain.Store, error) {
 }
 
 // GetStores retrieves all stores with pagination and filtering.
-func (uc *storeUseCase) GetStores(filter domain.StoreFilter) ([]domain.Store, int64, error) {
+func (uc *storeUseCase) GetStores(filter domain.StoreFilter) ([]domain.Store, domain.PageInfo, error) {
 	return uc.storeRepo.FindAll(filter)
 }
\ No newline at end of file
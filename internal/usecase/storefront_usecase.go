@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// Storefront is the public page of a store.
+type Storefront struct {
+	Store         *domain.Store         `json:"store"`
+	IsOpen        bool                  `json:"is_open"`
+	ProductCount  int64                 `json:"product_count"`
+	FollowerCount int64                 `json:"follower_count"`
+	Rating        *domain.RatingSummary `json:"rating"`
+	Products      []domain.Product      `json:"products"`
+	ProductPage   domain.PageInfo       `json:"-"`
+}
+
+// StorefrontUseCase defines the interface for public store pages and following.
+type StorefrontUseCase interface {
+	GetStorefront(key string, filter domain.ProductFilter) (*Storefront, error)
+	Follow(key string, userID uint) (int64, error)
+	Unfollow(key string, userID uint) (int64, error)
+	GetFollowedStores(userID uint) ([]domain.StoreFollower, error)
+}
+
+// storefrontUseCase implements the StorefrontUseCase interface.
+type storefrontUseCase struct {
+	storeRepo    repository.StoreRepository
+	productRepo  repository.ProductRepository
+	followerRepo repository.StoreFollowerRepository
+	reviewRepo   repository.ReviewRepository
+	settingsRepo repository.StoreSettingsRepository
+	categoryRepo repository.CategoryRepository
+	discountRepo repository.DiscountCampaignRepository
+	searcher     repository.ProductSearcher
+}
+
+// NewStorefrontUseCase creates a new instance of StorefrontUseCase.
+func NewStorefrontUseCase(
+	storeRepo repository.StoreRepository,
+	productRepo repository.ProductRepository,
+	followerRepo repository.StoreFollowerRepository,
+	reviewRepo repository.ReviewRepository,
+	settingsRepo repository.StoreSettingsRepository,
+	categoryRepo repository.CategoryRepository,
+	discountRepo repository.DiscountCampaignRepository,
+	searcher repository.ProductSearcher,
+) StorefrontUseCase {
+	return &storefrontUseCase{
+		storeRepo:    storeRepo,
+		productRepo:  productRepo,
+		followerRepo: followerRepo,
+		reviewRepo:   reviewRepo,
+		settingsRepo: settingsRepo,
+		categoryRepo: categoryRepo,
+		discountRepo: discountRepo,
+		searcher:     searcher,
+	}
+}
+
+// GetStorefront retrieves a verified store by slug, or by ID for older links,
+// together with its counters, rating summary and a page of products.
+func (uc *storefrontUseCase) GetStorefront(key string, filter domain.ProductFilter) (*Storefront, error) {
+	store, err := uc.publicStore(key)
+	if err != nil {
+		return nil, err
+	}
+
+	store.Settings = findStoreSettings(uc.settingsRepo, store.ID)
+	store.PublicSettings = store.Settings.Public()
+
+	filter.StoreID = store.ID
+	filter.UserID = 0
+	filter.IncludeUnverifiedStores = false
+	if err := expandCategoryFilter(uc.categoryRepo, &filter); err != nil {
+		return nil, err
+	}
+	products, productPage, err := searchProducts(uc.searcher, filter, uc.productRepo.GetProducts)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyDiscounts(uc.discountRepo, products); err != nil {
+		return nil, err
+	}
+
+	followerCount, err := uc.followerRepo.CountByStoreID(store.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rating, err := uc.reviewRepo.RatingSummaryByStoreID(store.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Storefront{
+		Store:         store,
+		IsOpen:        store.Settings.IsOpen(time.Now()),
+		ProductCount:  productPage.TotalCount,
+		FollowerCount: followerCount,
+		Rating:        rating,
+		Products:      products,
+		ProductPage:   productPage,
+	}, nil
+}
+
+// Follow makes the user follow a store and returns the new follower count.
+func (uc *storefrontUseCase) Follow(key string, userID uint) (int64, error) {
+	store, err := uc.publicStore(key)
+	if err != nil {
+		return 0, err
+	}
+	if store.UserID == userID {
+		return 0, errors.New("you cannot follow your own store")
+	}
+
+	if err := uc.followerRepo.Follow(store.ID, userID); err != nil {
+		return 0, err
+	}
+	return uc.followerRepo.CountByStoreID(store.ID)
+}
+
+// Unfollow removes the user's follow of a store and returns the new follower count.
+func (uc *storefrontUseCase) Unfollow(key string, userID uint) (int64, error) {
+	store, err := uc.publicStore(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := uc.followerRepo.Unfollow(store.ID, userID); err != nil {
+		return 0, err
+	}
+	return uc.followerRepo.CountByStoreID(store.ID)
+}
+
+// GetFollowedStores lists the stores a user follows.
+func (uc *storefrontUseCase) GetFollowedStores(userID uint) ([]domain.StoreFollower, error) {
+	return uc.followerRepo.FindByUserID(userID)
+}
+
+// publicStore resolves a store by slug, falling back to a numeric ID. Only verified stores are public.
+func (uc *storefrontUseCase) publicStore(key string) (*domain.Store, error) {
+	store, err := uc.storeRepo.FindBySlug(key)
+	if err != nil {
+		id, parseErr := strconv.ParseUint(key, 10, 32)
+		if parseErr != nil {
+			return nil, errors.New("store not found")
+		}
+		if store, err = uc.storeRepo.FindByID(uint(id)); err != nil {
+			return nil, errors.New("store not found")
+		}
+	}
+
+	if store.Status != domain.StoreStatusVerified {
+		return nil, errors.New("store not found")
+	}
+	return store, nil
+}
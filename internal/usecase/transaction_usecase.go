@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
 
@@ -12,7 +13,8 @@ import (
 type TransactionUseCase interface {
 	Create(transaction *domain.Transaction) error
 	GetByID(id, userID uint) (*domain.Transaction, error) // Updated to include userID
-	GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, int64, error)
+	GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error)
+	GetStoreTransactions(filter domain.TransactionFilter, userID uint) ([]domain.Transaction, domain.PageInfo, error)
 }
 
 type transactionUseCase struct {
@@ -20,60 +22,240 @@ type transactionUseCase struct {
 	productRepo     repository.ProductRepository
 	userRepo        repository.UserRepository
 	addressRepo     repository.AddressRepository
+	storeRepo       repository.StoreRepository
+	staffRepo       repository.StoreStaffRepository
+	settingsRepo    repository.StoreSettingsRepository
+	variantRepo     repository.ProductVariantRepository
+	discountRepo    repository.DiscountCampaignRepository
+	voucherRepo     repository.VoucherRepository
+	quoteRepo       repository.CheckoutQuoteRepository
+	transactor      repository.Transactor
+	pricer          orderPricer
+	lowStock        lowStockNotifier
 }
 
-func NewTransactionUseCase(transactionRepo repository.TransactionRepository, productRepo repository.ProductRepository, userRepo repository.UserRepository, addressRepo repository.AddressRepository) TransactionUseCase {
-	return &transactionUseCase{transactionRepo: transactionRepo, productRepo: productRepo, userRepo: userRepo, addressRepo: addressRepo}
+func NewTransactionUseCase(transactionRepo repository.TransactionRepository, productRepo repository.ProductRepository, userRepo repository.UserRepository, addressRepo repository.AddressRepository, storeRepo repository.StoreRepository, staffRepo repository.StoreStaffRepository, settingsRepo repository.StoreSettingsRepository, variantRepo repository.ProductVariantRepository, discountRepo repository.DiscountCampaignRepository, voucherRepo repository.VoucherRepository, quoteRepo repository.CheckoutQuoteRepository, notificationRepo repository.NotificationRepository, transactor repository.Transactor, couriers []domain.CourierRate, taxRate float64) TransactionUseCase {
+	pricer := orderPricer{productRepo: productRepo, variantRepo: variantRepo, storeRepo: storeRepo, settingsRepo: settingsRepo, discountRepo: discountRepo, voucherRepo: voucherRepo, couriers: couriers, taxRate: taxRate}
+	lowStock := lowStockNotifier{notificationRepo: notificationRepo, storeRepo: storeRepo, settingsRepo: settingsRepo}
+	return &transactionUseCase{transactionRepo: transactionRepo, productRepo: productRepo, userRepo: userRepo, addressRepo: addressRepo, storeRepo: storeRepo, staffRepo: staffRepo, settingsRepo: settingsRepo, variantRepo: variantRepo, discountRepo: discountRepo, voucherRepo: voucherRepo, quoteRepo: quoteRepo, transactor: transactor, pricer: pricer, lowStock: lowStock}
 }
 
+// Create places an order. An order placed with a checkout quote takes its address, items, courier
+// and voucher from the quote and is charged the quoted totals, as long as the quote hasn't expired.
+// Without a quote, the order is priced and its shipping charged the way a preview would.
 func (uc *transactionUseCase) Create(transaction *domain.Transaction) error {
+	if transaction.QuoteID == "" {
+		if len(transaction.Items) == 0 {
+			return errors.New("order has no items")
+		}
+		return uc.checkout(transaction, nil)
+	}
+
+	quote, err := uc.quoteRepo.FindByID(transaction.QuoteID)
+	if err != nil || quote.UserID != transaction.UserID {
+		return errors.New("quote not found")
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return errors.New("quote has expired, preview the order again")
+	}
+	if quote.UsedAt != nil {
+		return repository.ErrQuoteUsed
+	}
+
+	transaction.AddressID = quote.AddressID
+	transaction.ShippingCourier = quote.Courier
+	transaction.ShippingCost = quote.ShippingCost
+	transaction.VoucherCode = quote.VoucherCode
+	transaction.Items = nil
+	for _, line := range quote.Lines() {
+		transaction.Items = append(transaction.Items, domain.TransactionItem{
+			ProductID: line.ProductID,
+			VariantID: line.VariantID,
+			Quantity:  line.Quantity,
+		})
+	}
+	return uc.checkout(transaction, quote)
+}
+
+// checkout prices the order, or takes the prices of its quote, and places it. Claiming the quote,
+// reserving flash sale units and stock, redeeming the voucher and saving the order happen in one
+// database transaction, so an order that fails at any step leaves nothing reserved.
+func (uc *transactionUseCase) checkout(transaction *domain.Transaction, checkoutQuote *domain.CheckoutQuote) error {
 	_, err := uc.userRepo.FindByID(transaction.UserID)
 	if err != nil {
 		return errors.New("user not found for the given UserID")
 	}
 
-	_, err = uc.addressRepo.FindByID(transaction.AddressID)
+	address, err := uc.addressRepo.FindByID(transaction.AddressID)
+	if err != nil || address.UserID != transaction.UserID {
+		return errors.New("address not found")
+	}
+
+	// All items are checked and priced before anything is reserved
+	now := time.Now()
+	var lines []orderLine
+	if checkoutQuote != nil {
+		lines, err = uc.pricer.priceQuoted(transaction.UserID, checkoutQuote, transaction.Items, now)
+	} else {
+		lines, err = uc.pricer.price(transaction.UserID, transaction.Items, now)
+	}
 	if err != nil {
-		return errors.New("address not found for the given AddressID")
+		return err
 	}
 
-	var totalAmount float64
+	// Shipping is charged at the courier rates, as quoted or worked out here
+	if checkoutQuote == nil {
+		if transaction.ShippingCourier, transaction.ShippingCost, err = uc.pricer.shipping(lines, address, transaction.ShippingCourier); err != nil {
+			return err
+		}
+	}
 
-	for i, item := range transaction.Items {
-		product, err := uc.productRepo.FindByID(item.ProductID)
-		if err != nil {
-			return errors.New("product not found for item")
+	// The voucher is checked up front too, and redeemed once the stock is reserved.
+	// A quoted voucher keeps its discounts; only its usage limits are checked again.
+	var voucher *domain.Voucher
+	var quote *domain.VoucherQuote
+	if checkoutQuote != nil && checkoutQuote.VoucherCode != "" {
+		if voucher, err = uc.voucherRepo.FindByCode(checkoutQuote.VoucherCode); err != nil {
+			return errors.New("voucher not found")
 		}
-		if product.Stock < item.Quantity {
-			return errors.New("not enough stock for product")
+		quote = &domain.VoucherQuote{Discount: checkoutQuote.VoucherDiscount, ShippingDiscount: checkoutQuote.ShippingDiscount}
+	} else if transaction.VoucherCode != "" {
+		if voucher, quote, err = uc.pricer.quoteVoucher(transaction.VoucherCode, transaction.UserID, lines, transaction.ShippingCost, now); err != nil {
+			return err
 		}
-		product.Stock -= item.Quantity
-		err = uc.productRepo.Update(product)
-		if err != nil {
-			return errors.New("failed to update product stock")
+	}
+
+	sales := make([]domain.StockMovement, 0, len(transaction.Items))
+	err = uc.transactor.Transaction(func(repos repository.TxRepositories) error {
+		// Claiming is a conditional update, so only one order can commit a quote
+		if checkoutQuote != nil {
+			if err := repos.CheckoutQuotes.Claim(checkoutQuote.ID); err != nil {
+				return err
+			}
 		}
 
-		// Calculate total amount
-		totalAmount += item.Price * float64(item.Quantity)
-
-		// Populate ProductLog
-		transaction.Items[i].ProductLog = domain.ProductLog{
-			ProductName:        product.Name,
-			ProductDescription: product.Description,
-			ProductPrice:       product.Price,
-			ProductWeight:      product.Weight,
-			ProductImages:      product.Images,
-			CreatedAt:          time.Now(),
+		var totalAmount float64
+		for i, item := range transaction.Items {
+			line := lines[i]
+			product, variant, campaign := line.Product, line.Variant, line.Campaign
+
+			// Flash sale units are reserved first, so a sold-out flash sale doesn't take stock
+			if campaign != nil && campaign.Type == domain.DiscountTypeFlashSale {
+				if err := repos.DiscountCampaigns.ReserveFlashSale(campaign.ID, transaction.UserID, item.Quantity); err != nil {
+					switch {
+					case errors.Is(err, repository.ErrFlashSaleSoldOut):
+						return errors.New("flash sale " + campaign.Name + " is sold out")
+					case errors.Is(err, repository.ErrFlashSaleLimitReached):
+						return fmt.Errorf("flash sale %s is limited to %d per buyer", campaign.Name, campaign.PerUserLimit)
+					}
+					return err
+				}
+			}
+
+			// The sale goes through the inventory ledger, which decrements the variant stock
+			// alongside the product total and refuses to oversell
+			sale := domain.StockMovement{
+				ProductID: product.ID,
+				VariantID: item.VariantID,
+				Type:      domain.StockMovementSale,
+				Quantity:  -item.Quantity,
+				Reason:    "order",
+				ActorID:   &transaction.UserID,
+			}
+			if err := repos.StockMovements.Apply(&sale); err != nil {
+				if !errors.Is(err, repository.ErrInsufficientStock) {
+					return errors.New("failed to update product stock")
+				}
+				if variant != nil {
+					return errors.New("not enough stock for variant " + variant.Name)
+				}
+				return errors.New("not enough stock for product")
+			}
+			sales = append(sales, sale)
+
+			// Calculate total amount
+			transaction.Items[i].Price = line.Price
+			totalAmount += line.Price * float64(item.Quantity)
+
+			// Populate ProductLog
+			transaction.Items[i].ProductLog = domain.ProductLog{
+				ProductName:        product.Name,
+				ProductDescription: product.Description,
+				ProductPrice:       line.RegularPrice,
+				DiscountedPrice:    line.Price,
+				ProductWeight:      product.Weight,
+				ProductImages:      product.Images,
+				CreatedAt:          time.Now(),
+			}
+			if campaign != nil {
+				log := &transaction.Items[i].ProductLog
+				log.CampaignID = &campaign.ID
+				log.CampaignType = campaign.Type
+			}
+			if variant != nil {
+				log := &transaction.Items[i].ProductLog
+				log.VariantID = &variant.ID
+				log.VariantSKU = variant.SKU
+				log.VariantName = variant.Name
+				log.VariantImage = variant.Image
+			}
 		}
-	}
 
-	transaction.TotalAmount = totalAmount + transaction.ShippingCost
-	transaction.InvoiceNumber = uuid.New().String()
-	transaction.Status = "pending"
-	// PaymentMethod and ShippingCourier/ShippingTracking are expected to be set by the handler.
-	// ConfirmedAt, PaidAt, ShippedAt, CompletedAt, CancelledAt are nil by default and updated later by status changes.
+		// Redeeming counts against the voucher limits atomically, so they hold under concurrent orders
+		var redemption *domain.VoucherRedemption
+		if voucher != nil {
+			redemption = &domain.VoucherRedemption{
+				VoucherID:        voucher.ID,
+				UserID:           transaction.UserID,
+				Discount:         quote.Discount,
+				ShippingDiscount: quote.ShippingDiscount,
+			}
+			if err := repos.Vouchers.Redeem(redemption); err != nil {
+				return err
+			}
+			transaction.VoucherCode = voucher.Code
+			transaction.VoucherDiscount = quote.Discount
+			transaction.ShippingDiscount = quote.ShippingDiscount
+		}
+
+		// Tax is on the items after the voucher discount. An order placed with a quote is charged
+		// exactly the quoted totals.
+		totalAmount -= transaction.VoucherDiscount
+		transaction.Tax = uc.pricer.tax(totalAmount)
+		transaction.TotalAmount = totalAmount + transaction.ShippingCost - transaction.ShippingDiscount + transaction.Tax
+		if checkoutQuote != nil {
+			transaction.Tax = checkoutQuote.Tax
+			transaction.TotalAmount = checkoutQuote.GrandTotal
+		}
+		transaction.InvoiceNumber = uuid.New().String()
+		transaction.Status = "pending"
+		// PaymentMethod and ShippingCourier/ShippingTracking are expected to be set by the handler.
+		// ConfirmedAt, PaidAt, ShippedAt, CompletedAt, CancelledAt are nil by default and updated later by status changes.
 
-	return uc.transactionRepo.Create(transaction)
+		if err := repos.Transactions.Create(transaction); err != nil {
+			return err
+		}
+		saleIDs := make([]uint, len(sales))
+		for i, sale := range sales {
+			saleIDs[i] = sale.ID
+		}
+		if err := repos.StockMovements.AttachTransaction(saleIDs, transaction.ID); err != nil {
+			return err
+		}
+		if redemption != nil {
+			return repos.Vouchers.AttachTransaction(redemption.ID, transaction.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Sellers are notified of stock running low once the order is placed
+	for i := range sales {
+		uc.lowStock.notify(lines[i].Product, lines[i].Variant, &sales[i])
+	}
+	return nil
 }
 
 // GetByID retrieves a transaction by its ID and userID for ownership validation.
@@ -87,11 +269,23 @@ func (uc *transactionUseCase) GetByID(id, userID uint) (*domain.Transaction, err
 }
 
 // GetUserTransactions retrieves transactions for a specific user with pagination and filtering.
-func (uc *transactionUseCase) GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, int64, error) {
+func (uc *transactionUseCase) GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error) {
 	// Validate UserID from filter
 	_, err := uc.userRepo.FindByID(filter.UserID)
 	if err != nil {
-		return nil, 0, errors.New("user not found or an error occurred while checking user existence")
+		return nil, domain.PageInfo{}, errors.New("user not found or an error occurred while checking user existence")
 	}
 	return uc.transactionRepo.FindAll(filter)
-}
\ No newline at end of file
+}
+
+// GetStoreTransactions retrieves transactions containing products of a store, for the seller's order management.
+// Staff need the process_orders or view_finances permission.
+func (uc *transactionUseCase) GetStoreTransactions(filter domain.TransactionFilter, userID uint) ([]domain.Transaction, domain.PageInfo, error) {
+	if filter.StoreID == 0 {
+		return nil, domain.PageInfo{}, errors.New("store is required")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, filter.StoreID, userID, domain.StorePermissionProcessOrders, domain.StorePermissionViewFinances); err != nil {
+		return nil, domain.PageInfo{}, err
+	}
+	return uc.transactionRepo.FindAll(filter)
+}
@@ -0,0 +1,531 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// checkoutDB is the in-memory data behind the checkout fakes. The fakes apply the limits with the
+// same conditions as the real repositories, and the fake transactor rolls everything back when the
+// transaction fails.
+type checkoutDB struct {
+	products     map[uint]domain.Product
+	stores       map[uint]domain.Store
+	addresses    map[uint]domain.Address
+	campaigns    map[uint]domain.DiscountCampaign
+	reservations map[[2]uint]int // Flash sale units by campaign and buyer
+	vouchers     map[string]domain.Voucher
+	redemptions  []domain.VoucherRedemption
+	quotes       map[string]domain.CheckoutQuote
+	transactions []domain.Transaction
+}
+
+func (db *checkoutDB) clone() *checkoutDB {
+	c := &checkoutDB{
+		products:     make(map[uint]domain.Product),
+		stores:       db.stores,
+		addresses:    db.addresses,
+		campaigns:    make(map[uint]domain.DiscountCampaign),
+		reservations: make(map[[2]uint]int),
+		vouchers:     make(map[string]domain.Voucher),
+		redemptions:  append([]domain.VoucherRedemption(nil), db.redemptions...),
+		quotes:       make(map[string]domain.CheckoutQuote),
+		transactions: append([]domain.Transaction(nil), db.transactions...),
+	}
+	for k, v := range db.products {
+		c.products[k] = v
+	}
+	for k, v := range db.campaigns {
+		c.campaigns[k] = v
+	}
+	for k, v := range db.reservations {
+		c.reservations[k] = v
+	}
+	for k, v := range db.vouchers {
+		c.vouchers[k] = v
+	}
+	for k, v := range db.quotes {
+		c.quotes[k] = v
+	}
+	return c
+}
+
+type checkoutProducts struct {
+	repository.ProductRepository
+	db *checkoutDB
+}
+
+func (r checkoutProducts) FindByID(id uint) (*domain.Product, error) {
+	if product, ok := r.db.products[id]; ok {
+		return &product, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type checkoutVariants struct {
+	repository.ProductVariantRepository
+}
+
+func (checkoutVariants) FindVariantsByProductID(uint, bool) ([]domain.ProductVariant, error) {
+	return nil, nil
+}
+
+type checkoutStores struct {
+	repository.StoreRepository
+	db *checkoutDB
+}
+
+func (r checkoutStores) FindByID(id uint) (*domain.Store, error) {
+	if store, ok := r.db.stores[id]; ok {
+		return &store, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// checkoutSettings has no settings saved, so stores get the defaults.
+type checkoutSettings struct {
+	repository.StoreSettingsRepository
+}
+
+func (checkoutSettings) FindByStoreID(uint) (*domain.StoreSettings, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+type checkoutAddresses struct {
+	repository.AddressRepository
+	db *checkoutDB
+}
+
+func (r checkoutAddresses) FindByID(id uint) (*domain.Address, error) {
+	if address, ok := r.db.addresses[id]; ok {
+		return &address, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+type checkoutCampaigns struct {
+	repository.DiscountCampaignRepository
+	db *checkoutDB
+}
+
+func (r checkoutCampaigns) FindByID(id uint) (*domain.DiscountCampaign, error) {
+	if campaign, ok := r.db.campaigns[id]; ok {
+		return &campaign, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r checkoutCampaigns) FindRunning(storeIDs []uint, now time.Time) ([]domain.DiscountCampaign, error) {
+	var running []domain.DiscountCampaign
+	for _, campaign := range r.db.campaigns {
+		soldOut := campaign.Type == domain.DiscountTypeFlashSale && campaign.Quota > 0 && campaign.SoldQuantity >= campaign.Quota
+		if !soldOut && !now.Before(campaign.StartsAt) && now.Before(campaign.EndsAt) {
+			running = append(running, campaign)
+		}
+	}
+	return running, nil
+}
+
+func (r checkoutCampaigns) CountUserReserved(campaignID, userID uint) (int, error) {
+	return r.db.reservations[[2]uint{campaignID, userID}], nil
+}
+
+func (r checkoutCampaigns) ReserveFlashSale(id, userID uint, quantity int) error {
+	campaign := r.db.campaigns[id]
+	key := [2]uint{id, userID}
+	if r.db.reservations[key]+quantity > campaign.PerUserLimit {
+		return repository.ErrFlashSaleLimitReached
+	}
+	if campaign.Quota > 0 && campaign.SoldQuantity+quantity > campaign.Quota {
+		return repository.ErrFlashSaleSoldOut
+	}
+	r.db.reservations[key] += quantity
+	campaign.SoldQuantity += quantity
+	r.db.campaigns[id] = campaign
+	return nil
+}
+
+type checkoutVouchers struct {
+	repository.VoucherRepository
+	db *checkoutDB
+}
+
+func (r checkoutVouchers) FindByCode(code string) (*domain.Voucher, error) {
+	if voucher, ok := r.db.vouchers[code]; ok {
+		return &voucher, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r checkoutVouchers) CountUserRedemptions(voucherID, userID uint) (int64, error) {
+	var count int64
+	for _, redemption := range r.db.redemptions {
+		if redemption.VoucherID == voucherID && redemption.UserID == userID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r checkoutVouchers) Redeem(redemption *domain.VoucherRedemption) error {
+	for code, voucher := range r.db.vouchers {
+		if voucher.ID != redemption.VoucherID {
+			continue
+		}
+		if voucher.UsageLimit > 0 && voucher.UsedCount >= voucher.UsageLimit {
+			return repository.ErrVoucherUsedUp
+		}
+		if used, _ := r.CountUserRedemptions(voucher.ID, redemption.UserID); voucher.PerUserLimit > 0 && used >= int64(voucher.PerUserLimit) {
+			return repository.ErrVoucherLimitReached
+		}
+		voucher.UsedCount++
+		r.db.vouchers[code] = voucher
+		redemption.ID = uint(len(r.db.redemptions) + 1)
+		r.db.redemptions = append(r.db.redemptions, *redemption)
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r checkoutVouchers) AttachTransaction(redemptionID, transactionID uint) error {
+	r.db.redemptions[redemptionID-1].TransactionID = &transactionID
+	return nil
+}
+
+type checkoutQuotes struct {
+	repository.CheckoutQuoteRepository
+	db *checkoutDB
+}
+
+func (r checkoutQuotes) FindByID(id string) (*domain.CheckoutQuote, error) {
+	if quote, ok := r.db.quotes[id]; ok {
+		return &quote, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r checkoutQuotes) Claim(id string) error {
+	quote, ok := r.db.quotes[id]
+	if !ok || quote.UsedAt != nil {
+		return repository.ErrQuoteUsed
+	}
+	now := time.Now()
+	quote.UsedAt = &now
+	r.db.quotes[id] = quote
+	return nil
+}
+
+type checkoutStock struct {
+	repository.StockMovementRepository
+	db *checkoutDB
+}
+
+func (r checkoutStock) Apply(movement *domain.StockMovement) error {
+	product := r.db.products[movement.ProductID]
+	movement.StockAfter = product.Stock + movement.Quantity
+	if movement.StockAfter < 0 {
+		return repository.ErrInsufficientStock
+	}
+	product.Stock = movement.StockAfter
+	r.db.products[product.ID] = product
+	return nil
+}
+
+func (checkoutStock) AttachTransaction([]uint, uint) error {
+	return nil
+}
+
+type checkoutTransactions struct {
+	repository.TransactionRepository
+	db *checkoutDB
+}
+
+func (r checkoutTransactions) Create(transaction *domain.Transaction) error {
+	transaction.ID = uint(len(r.db.transactions) + 1)
+	r.db.transactions = append(r.db.transactions, *transaction)
+	return nil
+}
+
+// checkoutTransactor runs the function on a copy of the data, kept only when it succeeds.
+type checkoutTransactor struct {
+	db *checkoutDB
+}
+
+func (t checkoutTransactor) Transaction(fn func(repos repository.TxRepositories) error) error {
+	tx := t.db.clone()
+	err := fn(repository.TxRepositories{
+		Products:          checkoutProducts{db: tx},
+		DiscountCampaigns: checkoutCampaigns{db: tx},
+		Vouchers:          checkoutVouchers{db: tx},
+		CheckoutQuotes:    checkoutQuotes{db: tx},
+		StockMovements:    checkoutStock{db: tx},
+		Transactions:      checkoutTransactions{db: tx},
+	})
+	if err == nil {
+		*t.db = *tx
+	}
+	return err
+}
+
+// newCheckoutDB has a verified store selling two products, and an address for buyers 1 and 2 each.
+func newCheckoutDB() *checkoutDB {
+	return &checkoutDB{
+		products: map[uint]domain.Product{
+			1: {ID: 1, StoreID: 1, Name: "Kopi", Price: 50000, Stock: 100, Weight: 500, Status: domain.ProductStatusActive},
+			2: {ID: 2, StoreID: 1, Name: "Teh", Price: 20000, Stock: 1, Weight: 200, Status: domain.ProductStatusActive},
+		},
+		stores: map[uint]domain.Store{1: {ID: 1, UserID: 9, Status: domain.StoreStatusVerified}},
+		addresses: map[uint]domain.Address{
+			1: {ID: 1, UserID: 1},
+			2: {ID: 2, UserID: 2},
+		},
+		campaigns:    make(map[uint]domain.DiscountCampaign),
+		reservations: make(map[[2]uint]int),
+		vouchers:     make(map[string]domain.Voucher),
+		quotes:       make(map[string]domain.CheckoutQuote),
+	}
+}
+
+func newCheckoutUseCase(db *checkoutDB) TransactionUseCase {
+	users := &fakeUserRepo{users: map[uint]*domain.User{1: {ID: 1}, 2: {ID: 2}}}
+	couriers := []domain.CourierRate{{Code: "jne", Name: "JNE", PerKg: 10000, Days: 2}}
+	return NewTransactionUseCase(checkoutTransactions{db: db}, checkoutProducts{db: db}, users, checkoutAddresses{db: db},
+		checkoutStores{db: db}, nil, checkoutSettings{}, checkoutVariants{}, checkoutCampaigns{db: db}, checkoutVouchers{db: db},
+		checkoutQuotes{db: db}, nil, checkoutTransactor{db: db}, couriers, 0.11)
+}
+
+// order is an order of the buyer for the items, as product ID and quantity pairs.
+func order(buyer uint, voucherCode string, items ...int) *domain.Transaction {
+	transaction := &domain.Transaction{UserID: buyer, AddressID: buyer, PaymentMethod: "transfer", VoucherCode: voucherCode}
+	for i := 0; i < len(items); i += 2 {
+		transaction.Items = append(transaction.Items, domain.TransactionItem{ProductID: uint(items[i]), Quantity: items[i+1]})
+	}
+	return transaction
+}
+
+func TestCheckoutLimits(t *testing.T) {
+	now := time.Now()
+
+	t.Run("a voucher is redeemed once per buyer", func(t *testing.T) {
+		db := newCheckoutDB()
+		db.vouchers["HEMAT"] = domain.Voucher{ID: 1, Code: "HEMAT", Type: domain.VoucherTypeFixed, Value: 10000, PerUserLimit: 1,
+			StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+		uc := newCheckoutUseCase(db)
+
+		first := order(1, "HEMAT", 1, 1)
+		if err := uc.Create(first); err != nil {
+			t.Fatalf("first order: %v", err)
+		}
+		if first.VoucherDiscount != 10000 {
+			t.Fatalf("voucher discount = %v, want 10000", first.VoucherDiscount)
+		}
+		if err := uc.Create(order(1, "HEMAT", 1, 1)); !errors.Is(err, repository.ErrVoucherLimitReached) {
+			t.Fatalf("second redemption by the buyer = %v, want %v", err, repository.ErrVoucherLimitReached)
+		}
+		if err := uc.Create(order(2, "HEMAT", 1, 1)); err != nil {
+			t.Fatalf("redemption by another buyer: %v", err)
+		}
+		if len(db.redemptions) != 2 || db.vouchers["HEMAT"].UsedCount != 2 {
+			t.Fatalf("%d redemptions, used count %d; want 2 and 2", len(db.redemptions), db.vouchers["HEMAT"].UsedCount)
+		}
+	})
+
+	t.Run("a voucher used up by other buyers is refused", func(t *testing.T) {
+		db := newCheckoutDB()
+		db.vouchers["HEMAT"] = domain.Voucher{ID: 1, Code: "HEMAT", Type: domain.VoucherTypeFixed, Value: 10000, UsageLimit: 1,
+			StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+		uc := newCheckoutUseCase(db)
+
+		if err := uc.Create(order(1, "HEMAT", 1, 1)); err != nil {
+			t.Fatalf("first order: %v", err)
+		}
+		if err := uc.Create(order(2, "HEMAT", 1, 1)); !errors.Is(err, repository.ErrVoucherUsedUp) {
+			t.Fatalf("redemption of a used up voucher = %v, want %v", err, repository.ErrVoucherUsedUp)
+		}
+	})
+
+	t.Run("flash sale units are limited per buyer", func(t *testing.T) {
+		db := newCheckoutDB()
+		productID := uint(1)
+		salePrice := 25000.0
+		db.campaigns[1] = domain.DiscountCampaign{ID: 1, StoreID: 1, Type: domain.DiscountTypeFlashSale, Name: "Kilat",
+			ProductID: &productID, SalePrice: &salePrice, PerUserLimit: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+		uc := newCheckoutUseCase(db)
+
+		first := order(1, "", 1, 2)
+		if err := uc.Create(first); err != nil {
+			t.Fatalf("first order: %v", err)
+		}
+		if first.Items[0].Price != salePrice {
+			t.Fatalf("charged %v, want the flash sale price %v", first.Items[0].Price, salePrice)
+		}
+		if err := uc.Create(order(1, "", 1, 1)); err == nil || !strings.Contains(err.Error(), "per buyer") {
+			t.Fatalf("order over the buyer limit = %v, want the per buyer limit error", err)
+		}
+		if err := uc.Create(order(2, "", 1, 2)); err != nil {
+			t.Fatalf("order by another buyer: %v", err)
+		}
+	})
+
+	t.Run("a sold out flash sale reserves nothing", func(t *testing.T) {
+		db := newCheckoutDB()
+		productID := uint(1)
+		salePrice := 25000.0
+		db.campaigns[1] = domain.DiscountCampaign{ID: 1, StoreID: 1, Type: domain.DiscountTypeFlashSale, Name: "Kilat",
+			ProductID: &productID, SalePrice: &salePrice, Quota: 3, PerUserLimit: 2, StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+		uc := newCheckoutUseCase(db)
+
+		// The campaign is priced while it runs, then sells out before the order reserves its units
+		if err := uc.Create(order(1, "", 1, 2)); err != nil {
+			t.Fatalf("first order: %v", err)
+		}
+		if err := uc.Create(order(2, "", 1, 2)); err == nil || !strings.Contains(err.Error(), "sold out") {
+			t.Fatalf("order over the quota = %v, want the sold out error", err)
+		}
+		if sold := db.campaigns[1].SoldQuantity; sold != 2 {
+			t.Fatalf("sold quantity = %d, want 2", sold)
+		}
+		if stock := db.products[1].Stock; stock != 98 {
+			t.Fatalf("stock = %d, want 98", stock)
+		}
+	})
+
+	t.Run("a failed order keeps nothing reserved", func(t *testing.T) {
+		db := newCheckoutDB()
+		db.vouchers["HEMAT"] = domain.Voucher{ID: 1, Code: "HEMAT", Type: domain.VoucherTypeFixed, Value: 10000,
+			StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)}
+		uc := newCheckoutUseCase(db)
+
+		if err := uc.Create(order(1, "HEMAT", 1, 3, 2, 5)); err == nil {
+			t.Fatal("an order over the stock was placed")
+		}
+		if stock := db.products[1].Stock; stock != 100 {
+			t.Fatalf("stock after the failed order = %d, want 100", stock)
+		}
+		if len(db.redemptions) != 0 || len(db.transactions) != 0 {
+			t.Fatalf("failed order left %d redemptions and %d orders", len(db.redemptions), len(db.transactions))
+		}
+	})
+
+	t.Run("the address must be the buyer's", func(t *testing.T) {
+		uc := newCheckoutUseCase(newCheckoutDB())
+		transaction := order(1, "", 1, 1)
+		transaction.AddressID = 2
+		if err := uc.Create(transaction); err == nil {
+			t.Fatal("an order was shipped to another buyer's address")
+		}
+	})
+
+	t.Run("shipping is charged at the courier rates", func(t *testing.T) {
+		uc := newCheckoutUseCase(newCheckoutDB())
+		transaction := order(1, "", 1, 3)
+		transaction.ShippingCost = 1
+		if err := uc.Create(transaction); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		// 1.5 kg is charged as 2 kg
+		if transaction.ShippingCourier != "jne" || transaction.ShippingCost != 20000 {
+			t.Fatalf("shipping %s %v, want jne 20000", transaction.ShippingCourier, transaction.ShippingCost)
+		}
+	})
+}
+
+func TestCheckoutQuote(t *testing.T) {
+	newQuote := func(db *checkoutDB, buyer uint, expiresAt time.Time) string {
+		db.quotes["quote-1"] = domain.CheckoutQuote{
+			ID:        "quote-1",
+			UserID:    buyer,
+			AddressID: buyer,
+			Courier:   "jne",
+			Stores: []domain.CheckoutQuoteStore{{
+				StoreID: 1,
+				Lines:   []domain.CheckoutQuoteLine{{ProductID: 1, Quantity: 2, RegularPrice: 50000, Price: 45000, Total: 90000}},
+			}},
+			Subtotal:     90000,
+			ShippingCost: 10000,
+			Tax:          9900,
+			GrandTotal:   109900,
+			ExpiresAt:    expiresAt,
+		}
+		return "quote-1"
+	}
+	quoted := func(buyer uint, quoteID string) *domain.Transaction {
+		return &domain.Transaction{UserID: buyer, PaymentMethod: "transfer", QuoteID: quoteID}
+	}
+
+	t.Run("a quote places one order at the quoted totals", func(t *testing.T) {
+		db := newCheckoutDB()
+		quoteID := newQuote(db, 1, time.Now().Add(time.Hour))
+		uc := newCheckoutUseCase(db)
+
+		transaction := quoted(1, quoteID)
+		if err := uc.Create(transaction); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if transaction.TotalAmount != 109900 || transaction.Items[0].Price != 45000 {
+			t.Fatalf("charged %v at %v per unit, want the quoted 109900 at 45000", transaction.TotalAmount, transaction.Items[0].Price)
+		}
+
+		if err := uc.Create(quoted(1, quoteID)); !errors.Is(err, repository.ErrQuoteUsed) {
+			t.Fatalf("second order with the quote = %v, want %v", err, repository.ErrQuoteUsed)
+		}
+		if len(db.transactions) != 1 || db.products[1].Stock != 98 {
+			t.Fatalf("%d orders and stock %d, want 1 order and stock 98", len(db.transactions), db.products[1].Stock)
+		}
+	})
+
+	t.Run("a claim that loses the race places nothing", func(t *testing.T) {
+		db := newCheckoutDB()
+		quoteID := newQuote(db, 1, time.Now().Add(time.Hour))
+		uc := newCheckoutUseCase(db)
+
+		// Another order claims the quote after this one has read it
+		now := time.Now()
+		uc.(*transactionUseCase).quoteRepo = claimedQuotes{checkoutQuotes{db: db}, &now}
+		if err := uc.Create(quoted(1, quoteID)); !errors.Is(err, repository.ErrQuoteUsed) {
+			t.Fatalf("order with a claimed quote = %v, want %v", err, repository.ErrQuoteUsed)
+		}
+		if len(db.transactions) != 0 || db.products[1].Stock != 100 {
+			t.Fatalf("%d orders and stock %d, want none and stock 100", len(db.transactions), db.products[1].Stock)
+		}
+	})
+
+	t.Run("a quote of another buyer is refused", func(t *testing.T) {
+		db := newCheckoutDB()
+		quoteID := newQuote(db, 1, time.Now().Add(time.Hour))
+		if err := newCheckoutUseCase(db).Create(quoted(2, quoteID)); err == nil {
+			t.Fatal("a buyer ordered with another buyer's quote")
+		}
+	})
+
+	t.Run("an expired quote is refused", func(t *testing.T) {
+		db := newCheckoutDB()
+		quoteID := newQuote(db, 1, time.Now().Add(-time.Second))
+		if err := newCheckoutUseCase(db).Create(quoted(1, quoteID)); err == nil {
+			t.Fatal("an expired quote placed an order")
+		}
+	})
+}
+
+// claimedQuotes finds quotes as still unused, while they are claimed in the database meanwhile.
+type claimedQuotes struct {
+	checkoutQuotes
+	claimedAt *time.Time
+}
+
+func (r claimedQuotes) FindByID(id string) (*domain.CheckoutQuote, error) {
+	quote, err := r.checkoutQuotes.FindByID(id)
+	if err == nil {
+		claimed := *quote
+		claimed.UsedAt = r.claimedAt
+		r.db.quotes[id] = claimed
+	}
+	return quote, err
+}
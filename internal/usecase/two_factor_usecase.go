@@ -0,0 +1,251 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+	"mini-project-ostore/pkg/jwt"
+	"mini-project-ostore/pkg/totp"
+)
+
+const (
+	totpIssuer        = "ostore"
+	recoveryCodeCount = 10
+	// Codes that can be tried with one challenge token before the login has to start over
+	challengeMaxAttempts = 5
+)
+
+// TwoFactorEnrollment is returned when a user starts TOTP enrollment.
+type TwoFactorEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"` // Render as a QR code for authenticator apps
+}
+
+// TwoFactorUseCase defines the interface for TOTP two-factor authentication.
+type TwoFactorUseCase interface {
+	Enroll(userID uint) (*TwoFactorEnrollment, error)
+	Confirm(userID uint, code string) ([]string, error)
+	Disable(userID uint, code string) error
+	RegenerateRecoveryCodes(userID uint, code string) ([]string, error)
+	VerifyChallenge(challengeToken, code string) (*LoginResult, error)
+}
+
+// twoFactorUseCase implements the TwoFactorUseCase interface.
+type twoFactorUseCase struct {
+	userRepo         repository.UserRepository
+	recoveryCodeRepo repository.RecoveryCodeRepository
+	challengeRepo    repository.TwoFactorChallengeRepository
+}
+
+// NewTwoFactorUseCase creates a new instance of TwoFactorUseCase.
+func NewTwoFactorUseCase(userRepo repository.UserRepository, recoveryCodeRepo repository.RecoveryCodeRepository, challengeRepo repository.TwoFactorChallengeRepository) TwoFactorUseCase {
+	return &twoFactorUseCase{userRepo: userRepo, recoveryCodeRepo: recoveryCodeRepo, challengeRepo: challengeRepo}
+}
+
+// Enroll generates a new TOTP secret for the user. Two-factor stays disabled
+// until the user proves possession of the secret through Confirm.
+func (uc *twoFactorUseCase) Enroll(userID uint) (*TwoFactorEnrollment, error) {
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.New("failed to generate secret")
+	}
+
+	user.TOTPSecret = secret
+	user.TOTPLastCounter = 0
+	if err := uc.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return &TwoFactorEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(totpIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication after a valid code and returns fresh recovery codes.
+func (uc *twoFactorUseCase) Confirm(userID uint, code string) ([]string, error) {
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is already enabled")
+	}
+	if user.TOTPSecret == "" {
+		return nil, errors.New("two-factor enrollment has not been started")
+	}
+
+	if !uc.checkTOTP(user, code) {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	user.TwoFactorEnabled = true
+	if err := uc.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	return uc.issueRecoveryCodes(user.ID)
+}
+
+// Disable turns two-factor authentication off after a valid TOTP or recovery code.
+// Admins can't turn it off, as it is mandatory for admin access.
+func (uc *twoFactorUseCase) Disable(userID uint, code string) error {
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil {
+		return errors.New("user not found")
+	}
+	if !user.TwoFactorEnabled {
+		return errors.New("two-factor authentication is not enabled")
+	}
+	if user.IsAdmin {
+		return errors.New("two-factor authentication is required for admin accounts")
+	}
+
+	ok, err := uc.checkSecondFactor(user, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("invalid two-factor code")
+	}
+
+	user.TwoFactorEnabled = false
+	user.TOTPSecret = ""
+	user.TOTPLastCounter = 0
+	if err := uc.userRepo.Update(user); err != nil {
+		return err
+	}
+	return uc.recoveryCodeRepo.DeleteByUserID(user.ID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after a valid TOTP code.
+func (uc *twoFactorUseCase) RegenerateRecoveryCodes(userID uint, code string) ([]string, error) {
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if !uc.checkTOTP(user, code) {
+		return nil, errors.New("invalid two-factor code")
+	}
+
+	return uc.issueRecoveryCodes(user.ID)
+}
+
+// VerifyChallenge exchanges a challenge token from the first login step and a
+// TOTP or recovery code for the final access token. Each challenge allows a few attempts
+// and completes one login; after that the login has to start over.
+func (uc *twoFactorUseCase) VerifyChallenge(challengeToken, code string) (*LoginResult, error) {
+	claims, err := jwt.ValidateChallengeToken(challengeToken)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil, errors.New("invalid or expired challenge")
+	}
+
+	challenge := &domain.TwoFactorChallenge{ID: claims.ID, UserID: claims.UserID, ExpiresAt: claims.ExpiresAt.Time}
+	if err := uc.challengeRepo.Attempt(challenge, challengeMaxAttempts); err != nil {
+		if errors.Is(err, repository.ErrChallengeClosed) {
+			return nil, errors.New("too many attempts, please log in again")
+		}
+		return nil, err
+	}
+
+	user, err := uc.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		return nil, errors.New("user not found")
+	}
+	if !user.TwoFactorEnabled {
+		return nil, errors.New("two-factor authentication is not enabled")
+	}
+	if user.SuspendedAt != nil {
+		return nil, errors.New("account suspended")
+	}
+
+	ok, err := uc.checkSecondFactor(user, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.New("invalid two-factor code")
+	}
+	if err := uc.challengeRepo.Consume(challenge.ID); err != nil {
+		if errors.Is(err, repository.ErrChallengeClosed) {
+			return nil, errors.New("invalid or expired challenge")
+		}
+		return nil, err
+	}
+
+	token, err := jwt.GenerateToken(user.ID, user.IsAdmin, true)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: token, User: user}, nil
+}
+
+// checkTOTP validates a TOTP code and records its time step so it can't be replayed.
+func (uc *twoFactorUseCase) checkTOTP(user *domain.User, code string) bool {
+	counter, ok := totp.Validate(user.TOTPSecret, code, time.Now())
+	if !ok || counter <= user.TOTPLastCounter {
+		return false
+	}
+
+	user.TOTPLastCounter = counter
+	return uc.userRepo.Update(user) == nil
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code.
+func (uc *twoFactorUseCase) checkSecondFactor(user *domain.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		return uc.checkTOTP(user, code), nil
+	}
+
+	if err := uc.recoveryCodeRepo.Use(user.ID, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, repository.ErrRecoveryCodeInvalid) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// issueRecoveryCodes generates and stores a new set of recovery codes, returning them in plain text once.
+func (uc *twoFactorUseCase) issueRecoveryCodes(userID uint) ([]string, error) {
+	plain := make([]string, 0, recoveryCodeCount)
+	records := make([]domain.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, errors.New("failed to generate recovery codes")
+		}
+		h := hex.EncodeToString(b)
+		code := h[:5] + "-" + h[5:]
+		plain = append(plain, code)
+		records = append(records, domain.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := uc.recoveryCodeRepo.ReplaceForUser(userID, records); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(code))))
+	return hex.EncodeToString(sum[:])
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"errors"
+	"time"
+
 	"mini-project-ostore/internal/domain"
 	"mini-project-ostore/internal/repository"
 
@@ -12,18 +14,27 @@ import (
 type UserUseCase interface {
 	CreateDefaultStore(userID uint, userName string) error
 	GetByID(id uint) (*domain.User, error)
-	Update(user *domain.User) error
+	Update(id uint, changes domain.UserUpdate) error
+
+	// Admin user management
+	GetUsers(filter domain.UserFilter) ([]domain.User, int64, error)
+	GetUserStores(id uint) ([]domain.Store, error)
+	GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error)
+	Suspend(adminID, id uint, reason string) error
+	Unsuspend(id uint) error
+	Delete(adminID, id uint) error
 }
 
 // userUseCase implements the UserUseCase interface.
 type userUseCase struct {
-	userRepo  repository.UserRepository
-	storeRepo repository.StoreRepository
+	userRepo        repository.UserRepository
+	storeRepo       repository.StoreRepository
+	transactionRepo repository.TransactionRepository
 }
 
 // NewUserUseCase creates a new instance of UserUseCase.
-func NewUserUseCase(userRepo repository.UserRepository, storeRepo repository.StoreRepository) UserUseCase {
-	return &userUseCase{userRepo: userRepo, storeRepo: storeRepo}
+func NewUserUseCase(userRepo repository.UserRepository, storeRepo repository.StoreRepository, transactionRepo repository.TransactionRepository) UserUseCase {
+	return &userUseCase{userRepo: userRepo, storeRepo: storeRepo, transactionRepo: transactionRepo}
 }
 
 // CreateDefaultStore creates a default store for a newly registered user.
@@ -42,6 +53,9 @@ func (uc *userUseCase) CreateDefaultStore(userID uint, userName string) error {
 		Phone:       "",                // This could be updated later
 	}
 
+	if err := assignStoreSlug(uc.storeRepo, defaultStore); err != nil {
+		return err
+	}
 	return uc.storeRepo.Create(defaultStore)
 }
 
@@ -54,56 +68,111 @@ func (uc *userUseCase) GetByID(id uint) (*domain.User, error) {
 	return user, nil
 }
 
-// Update an existing user.
-func (uc *userUseCase) Update(user *domain.User) error {
-	existingUser, err := uc.userRepo.FindByID(user.ID)
+// Update applies a partial update to a user; fields left nil in changes are kept.
+func (uc *userUseCase) Update(id uint, changes domain.UserUpdate) error {
+	existingUser, err := uc.userRepo.FindByID(id)
 	if err != nil {
 		return errors.New("user not found")
 	}
 
-	// Update fields only if they are explicitly provided in the input 'user'
-	if user.Name != "" {
-		existingUser.Name = user.Name
+	if changes.Name != nil {
+		existingUser.Name = *changes.Name
 	}
-	if user.Email != "" && existingUser.Email != user.Email {
+	if changes.Email != nil && existingUser.Email != *changes.Email {
 		// Check if new email already exists for another user
-		exists, err := uc.userRepo.EmailExists(user.Email, user.ID)
+		exists, err := uc.userRepo.EmailExists(*changes.Email, id)
 		if err != nil {
 			return err
 		}
 		if exists {
 			return errors.New("email already exists")
 		}
-		existingUser.Email = user.Email
+		existingUser.Email = *changes.Email
+		existingUser.EmailVerifiedAt = nil // The new address hasn't been verified
 	}
-	if user.Phone != "" && existingUser.Phone != user.Phone {
+	if changes.Phone != nil && existingUser.Phone != *changes.Phone {
 		// Check if new phone already exists for another user
-		exists, err := uc.userRepo.PhoneExists(user.Phone, user.ID)
+		exists, err := uc.userRepo.PhoneExists(*changes.Phone, id)
 		if err != nil {
 			return err
 		}
 		if exists {
 			return errors.New("phone already exists")
 		}
-		existingUser.Phone = user.Phone
+		existingUser.Phone = *changes.Phone
+		existingUser.PhoneVerifiedAt = nil // The new number hasn't been verified
 	}
-	if user.Password != "" { // If password is provided, hash it
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	if changes.Password != nil { // If password is provided, hash it
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(*changes.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return errors.New("failed to hash password")
 		}
 		existingUser.Password = string(hashedPassword)
 	}
-	// For IsAdmin, the handler passes a `domain.User` where `IsAdmin` is `false` if `req.IsAdmin` was `nil` in the handler.
-	// If `req.IsAdmin` was `&false`, it also sets `user.IsAdmin` to `false`.
-	// This creates ambiguity for `false` values. To avoid unintended updates from `false` (default value),
-	// we update `IsAdmin` only if it's different from the existing value.
-	// This approach might incorrectly set `IsAdmin` to `false` if `existingUser.IsAdmin` was `true` and
-	// `req.IsAdmin` in the handler was `nil` (meaning no explicit update was intended for `IsAdmin`).
-	// A more robust solution would require changing the handler or the domain struct to pass specific update flags or nullable fields.
-	if existingUser.IsAdmin != user.IsAdmin {
-		existingUser.IsAdmin = user.IsAdmin
+	if changes.IsAdmin != nil {
+		existingUser.IsAdmin = *changes.IsAdmin
 	}
 
 	return uc.userRepo.Update(existingUser)
 }
+
+// GetUsers retrieves users with pagination and filtering.
+func (uc *userUseCase) GetUsers(filter domain.UserFilter) ([]domain.User, int64, error) {
+	return uc.userRepo.FindAll(filter)
+}
+
+// GetUserStores retrieves all stores owned by a user.
+func (uc *userUseCase) GetUserStores(id uint) ([]domain.Store, error) {
+	if _, err := uc.userRepo.FindByID(id); err != nil {
+		return nil, errors.New("user not found")
+	}
+	return uc.storeRepo.FindByUserID(id)
+}
+
+// GetUserTransactions retrieves the orders placed by a user.
+func (uc *userUseCase) GetUserTransactions(filter domain.TransactionFilter) ([]domain.Transaction, domain.PageInfo, error) {
+	if _, err := uc.userRepo.FindByID(filter.UserID); err != nil {
+		return nil, domain.PageInfo{}, errors.New("user not found")
+	}
+	return uc.transactionRepo.FindAll(filter)
+}
+
+// Suspend blocks a user from logging in and from using existing tokens.
+func (uc *userUseCase) Suspend(adminID, id uint, reason string) error {
+	if adminID == id {
+		return errors.New("cannot suspend your own account")
+	}
+
+	user, err := uc.userRepo.FindByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	now := time.Now()
+	user.SuspendedAt = &now
+	user.SuspendReason = reason
+	return uc.userRepo.Update(user)
+}
+
+// Unsuspend lifts a user's suspension.
+func (uc *userUseCase) Unsuspend(id uint) error {
+	user, err := uc.userRepo.FindByID(id)
+	if err != nil {
+		return errors.New("user not found")
+	}
+
+	user.SuspendedAt = nil
+	user.SuspendReason = ""
+	return uc.userRepo.Update(user)
+}
+
+// Delete anonymizes and soft-deletes a user and suspends their stores.
+func (uc *userUseCase) Delete(adminID, id uint) error {
+	if adminID == id {
+		return errors.New("cannot delete your own account")
+	}
+	if _, err := uc.userRepo.FindByID(id); err != nil {
+		return errors.New("user not found")
+	}
+	return uc.userRepo.Anonymize(id, adminID)
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"mini-project-ostore/internal/domain"
+	"mini-project-ostore/internal/repository"
+)
+
+// VoucherUseCase defines the interface for store and platform vouchers.
+type VoucherUseCase interface {
+	CreateStoreVoucher(voucher *domain.Voucher, userID uint) error
+	GetStoreVouchers(storeID, userID uint) ([]domain.Voucher, error)
+	EndStoreVoucher(storeID, voucherID, userID uint) error
+	Preview(code string, userID uint, items []domain.TransactionItem, shippingCost float64) (*domain.VoucherQuote, error)
+
+	// Admin
+	CreatePlatformVoucher(voucher *domain.Voucher) error
+	GetPlatformVouchers() ([]domain.Voucher, error)
+	EndPlatformVoucher(voucherID uint) error
+}
+
+// voucherUseCase implements the VoucherUseCase interface.
+type voucherUseCase struct {
+	voucherRepo repository.VoucherRepository
+	storeRepo   repository.StoreRepository
+	staffRepo   repository.StoreStaffRepository
+	pricer      orderPricer
+}
+
+// NewVoucherUseCase creates a new instance of VoucherUseCase.
+func NewVoucherUseCase(
+	voucherRepo repository.VoucherRepository,
+	productRepo repository.ProductRepository,
+	variantRepo repository.ProductVariantRepository,
+	storeRepo repository.StoreRepository,
+	staffRepo repository.StoreStaffRepository,
+	settingsRepo repository.StoreSettingsRepository,
+	discountRepo repository.DiscountCampaignRepository,
+) VoucherUseCase {
+	return &voucherUseCase{
+		voucherRepo: voucherRepo,
+		storeRepo:   storeRepo,
+		staffRepo:   staffRepo,
+		pricer: orderPricer{
+			productRepo:  productRepo,
+			variantRepo:  variantRepo,
+			storeRepo:    storeRepo,
+			settingsRepo: settingsRepo,
+			discountRepo: discountRepo,
+			voucherRepo:  voucherRepo,
+		},
+	}
+}
+
+// CreateStoreVoucher creates a voucher for the items of a store the user manages products of.
+func (uc *voucherUseCase) CreateStoreVoucher(voucher *domain.Voucher, userID uint) error {
+	if voucher.StoreID == nil {
+		return errors.New("store is required")
+	}
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, *voucher.StoreID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
+	}
+	return uc.create(voucher)
+}
+
+// CreatePlatformVoucher creates a voucher that applies to orders from any store.
+func (uc *voucherUseCase) CreatePlatformVoucher(voucher *domain.Voucher) error {
+	voucher.StoreID = nil
+	return uc.create(voucher)
+}
+
+func (uc *voucherUseCase) create(voucher *domain.Voucher) error {
+	voucher.Code = strings.ToUpper(strings.TrimSpace(voucher.Code))
+	if voucher.Code == "" {
+		return errors.New("voucher code is required")
+	}
+
+	switch voucher.Type {
+	case domain.VoucherTypeFixed:
+		if voucher.Value <= 0 {
+			return errors.New("value must be greater than 0 for a fixed voucher")
+		}
+	case domain.VoucherTypePercentage:
+		if voucher.Value <= 0 || voucher.Value > 100 {
+			return errors.New("value must be a percentage between 0 and 100")
+		}
+	case domain.VoucherTypeFreeShipping:
+		voucher.Value = 0
+	default:
+		return errors.New("type must be fixed, percentage or free_shipping")
+	}
+
+	if !voucher.EndsAt.After(voucher.StartsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+	if !voucher.EndsAt.After(time.Now()) {
+		return errors.New("ends_at must be in the future")
+	}
+
+	exists, err := uc.voucherRepo.CodeExists(voucher.Code)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.New("voucher code already exists")
+	}
+
+	voucher.UsedCount = 0
+	return uc.voucherRepo.Create(voucher)
+}
+
+// GetStoreVouchers lists the vouchers of a store, newest first.
+func (uc *voucherUseCase) GetStoreVouchers(storeID, userID uint) ([]domain.Voucher, error) {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return nil, err
+	}
+	return uc.voucherRepo.FindAll(&storeID)
+}
+
+// GetPlatformVouchers lists the platform vouchers, newest first.
+func (uc *voucherUseCase) GetPlatformVouchers() ([]domain.Voucher, error) {
+	return uc.voucherRepo.FindAll(nil)
+}
+
+// EndStoreVoucher stops a voucher of a store from being used.
+func (uc *voucherUseCase) EndStoreVoucher(storeID, voucherID, userID uint) error {
+	if _, err := authorizeStore(uc.storeRepo, uc.staffRepo, storeID, userID, domain.StorePermissionManageProducts); err != nil {
+		return err
+	}
+	voucher, err := uc.voucherRepo.FindByID(voucherID)
+	if err != nil || voucher.StoreID == nil || *voucher.StoreID != storeID {
+		return errors.New("voucher not found")
+	}
+	return uc.end(voucher)
+}
+
+// EndPlatformVoucher stops a platform voucher from being used.
+func (uc *voucherUseCase) EndPlatformVoucher(voucherID uint) error {
+	voucher, err := uc.voucherRepo.FindByID(voucherID)
+	if err != nil || voucher.StoreID != nil {
+		return errors.New("voucher not found")
+	}
+	return uc.end(voucher)
+}
+
+// end closes the validity window of a voucher now; redeemed vouchers are kept for the order history.
+func (uc *voucherUseCase) end(voucher *domain.Voucher) error {
+	now := time.Now()
+	if !voucher.EndsAt.After(now) {
+		return errors.New("voucher has already ended")
+	}
+	voucher.EndsAt = now
+	if voucher.StartsAt.After(now) {
+		voucher.StartsAt = now
+	}
+	return uc.voucherRepo.Update(voucher)
+}
+
+// Preview prices the items like checkout does and applies the voucher, without redeeming it.
+func (uc *voucherUseCase) Preview(code string, userID uint, items []domain.TransactionItem, shippingCost float64) (*domain.VoucherQuote, error) {
+	now := time.Now()
+	lines, err := uc.pricer.price(userID, items, now)
+	if err != nil {
+		return nil, err
+	}
+	_, quote, err := uc.pricer.quoteVoucher(code, userID, lines, shippingCost, now)
+	return quote, err
+}
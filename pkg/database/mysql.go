@@ -30,7 +30,23 @@ func NewMySQLConnection(cfg Config) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	// Stores created before moderation existed were public, so they are verified when the status column is added
+	backfillStoreStatus := db.Migrator().HasTable(&domain.Store{}) && !db.Migrator().HasColumn(&domain.Store{}, "Status")
+
+	// Variants now carry the store of their product for the unique SKU index, filled in
+	// before the index is created
+	if db.Migrator().HasTable(&domain.ProductVariant{}) && !db.Migrator().HasColumn(&domain.ProductVariant{}, "StoreID") {
+		if err := db.Migrator().AddColumn(&domain.ProductVariant{}, "StoreID"); err != nil {
+			return err
+		}
+		err := db.Exec(`UPDATE product_variants v JOIN products p ON p.id = v.product_id
+			SET v.store_id = p.store_id`).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	err := db.AutoMigrate(
 		&domain.User{},
 		&domain.Store{},
 		&domain.Address{},
@@ -39,5 +55,128 @@ func Migrate(db *gorm.DB) error {
 		&domain.Transaction{},
 		&domain.TransactionItem{},
 		&domain.ProductLog{},
+		&domain.OTP{},
+		&domain.LoginAttempt{},
+		&domain.UserIdentity{},
+		&domain.RecoveryCode{},
+		&domain.TwoFactorChallenge{},
+		&domain.StoreDocument{},
+		&domain.StoreModerationLog{},
+		&domain.StoreStaff{},
+		&domain.Review{},
+		&domain.StoreFollower{},
+		&domain.StoreSettings{},
+		&domain.ProductOption{},
+		&domain.ProductVariant{},
+		&domain.ProductImportJob{},
+		&domain.ProductSlugHistory{},
+		&domain.StockMovement{},
+		&domain.DiscountCampaign{},
+		&domain.FlashSaleReservation{},
+		&domain.Voucher{},
+		&domain.VoucherRedemption{},
+		&domain.CheckoutQuote{},
+		&domain.Notification{},
 	)
+	if err != nil {
+		return err
+	}
+
+	if backfillStoreStatus {
+		if err := db.Model(&domain.Store{}).Where("1 = 1").Update("status", domain.StoreStatusVerified).Error; err != nil {
+			return err
+		}
+	}
+
+	// Category names are now unique among siblings instead of globally, by a parent key
+	// that also makes them unique among root categories
+	for _, old := range []string{"idx_categories_name", "idx_category_parent_name"} {
+		if db.Migrator().HasIndex(&domain.Category{}, old) {
+			if err := db.Migrator().DropIndex(&domain.Category{}, old); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Product and variant SKUs are now unique per store
+	for _, old := range []struct {
+		model interface{}
+		name  string
+	}{
+		{&domain.Product{}, "idx_products_sku"},
+		{&domain.ProductVariant{}, "idx_product_variants_sku"},
+		{&domain.ProductVariant{}, "idx_product_variants_sku_lookup"},
+	} {
+		if db.Migrator().HasIndex(old.model, old.name) {
+			if err := db.Migrator().DropIndex(old.model, old.name); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Stock held before the inventory ledger existed is recorded as an opening balance,
+	// so the stock of every product and variant equals the sum of its movements
+	err = db.Exec(`INSERT INTO stock_movements (product_id, type, quantity, stock_after, reason, created_at)
+		SELECT p.id, ?, p.stock - COALESCE(v.stock, 0), p.stock, 'opening balance', NOW()
+		FROM products p
+		LEFT JOIN (SELECT product_id, SUM(stock) AS stock FROM product_variants WHERE deleted_at IS NULL GROUP BY product_id) v ON v.product_id = p.id
+		WHERE p.stock <> COALESCE(v.stock, 0)
+		AND NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.product_id = p.id)`, domain.StockMovementAdjustment).Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec(`INSERT INTO stock_movements (product_id, variant_id, type, quantity, stock_after, variant_stock_after, reason, created_at)
+		SELECT v.product_id, v.id, ?, v.stock, p.stock, v.stock, 'opening balance', NOW()
+		FROM product_variants v JOIN products p ON p.id = v.product_id
+		WHERE v.stock <> 0 AND v.deleted_at IS NULL
+		AND NOT EXISTS (SELECT 1 FROM stock_movements m WHERE m.variant_id = v.id)`, domain.StockMovementAdjustment).Error
+	if err != nil {
+		return err
+	}
+
+	// Items ordered before discount campaigns existed were charged their item price
+	err = db.Exec(`UPDATE product_logs pl JOIN transaction_items ti ON ti.id = pl.transaction_item_id
+		SET pl.discounted_price = ti.price
+		WHERE pl.discounted_price = 0 AND pl.campaign_id IS NULL`).Error
+	if err != nil {
+		return err
+	}
+
+	// Flash sale units bought before reservations were kept per buyer still count against the limit
+	err = db.Exec(`INSERT INTO flash_sale_reservations (campaign_id, user_id, quantity, updated_at)
+		SELECT pl.campaign_id, t.user_id, SUM(ti.quantity), NOW()
+		FROM product_logs pl
+		JOIN transaction_items ti ON ti.id = pl.transaction_item_id
+		JOIN transactions t ON t.id = ti.transaction_id AND t.deleted_at IS NULL
+		WHERE pl.campaign_type = ? AND t.status <> 'cancelled'
+		AND NOT EXISTS (SELECT 1 FROM flash_sale_reservations r WHERE r.campaign_id = pl.campaign_id AND r.user_id = t.user_id)
+		GROUP BY pl.campaign_id, t.user_id`, domain.DiscountTypeFlashSale).Error
+	if err != nil {
+		return err
+	}
+
+	// Stores of users deleted before their stores were suspended along with them
+	err = db.Exec(`UPDATE stores s JOIN users u ON u.id = s.user_id
+		SET s.status = ?
+		WHERE u.deleted_at IS NOT NULL AND s.status <> ?`, domain.StoreStatusSuspended, domain.StoreStatusSuspended).Error
+	if err != nil {
+		return err
+	}
+
+	// Numeric slugs are reserved for IDs, so products and stores whose name made one get a prefixed slug
+	err = db.Exec("UPDATE products SET slug = CONCAT('produk-', slug, '-', id) WHERE slug REGEXP '^[0-9]+$'").Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec("DELETE FROM product_slug_histories WHERE slug REGEXP '^[0-9]+$'").Error
+	if err != nil {
+		return err
+	}
+	err = db.Exec("UPDATE stores SET slug = CONCAT('toko-', slug, '-', id) WHERE slug REGEXP '^[0-9]+$'").Error
+	if err != nil {
+		return err
+	}
+
+	// Stores created before slugs existed get a placeholder slug based on their ID
+	return db.Exec("UPDATE stores SET slug = CONCAT('toko-', id) WHERE slug IS NULL OR slug = ''").Error
 }
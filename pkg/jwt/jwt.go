@@ -2,23 +2,37 @@
 package jwt
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 )
 
 var jwtSecret = []byte("your-secret-key") // Should be from environment variable
 
+// Token purposes. Access tokens carry no purpose.
+const (
+	PurposeTwoFactorChallenge = "2fa_challenge"
+	PurposeOAuthLink          = "oauth_link"
+)
+
+// ErrWrongPurpose is returned when a token is used for something it was not issued for.
+var ErrWrongPurpose = errors.New("token not valid for this purpose")
+
 type Claims struct {
-	UserID  uint `json:"user_id"`
-	IsAdmin bool `json:"is_admin"`
+	UserID    uint   `json:"user_id"`
+	IsAdmin   bool   `json:"is_admin"`
+	TwoFactor bool   `json:"mfa,omitempty"`     // Login completed a second factor
+	Purpose   string `json:"purpose,omitempty"` // Empty for access tokens
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, isAdmin bool) (string, error) {
+func GenerateToken(userID uint, isAdmin, twoFactor bool) (string, error) {
 	claims := Claims{
-		UserID:  userID,
-		IsAdmin: isAdmin,
+		UserID:    userID,
+		IsAdmin:   isAdmin,
+		TwoFactor: twoFactor,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -29,7 +43,78 @@ func GenerateToken(userID uint, isAdmin bool) (string, error) {
 	return token.SignedString(jwtSecret)
 }
 
+// GenerateChallengeToken issues a short-lived token proving the first login
+// factor succeeded. It can only be exchanged for an access token with a second factor.
+// Its ID identifies the challenge so attempts against it can be counted.
+func GenerateChallengeToken(userID uint) (string, error) {
+	claims := Claims{
+		UserID:  userID,
+		Purpose: PurposeTwoFactorChallenge,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(5 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// GenerateLinkState issues the OAuth state for linking a provider to a logged-in user. The
+// provider's link callback carries no access token, so the state says which user is linking.
+func GenerateLinkState(userID uint) (string, error) {
+	claims := Claims{
+		UserID:  userID,
+		Purpose: PurposeOAuthLink,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(10 * time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(jwtSecret)
+}
+
+// ValidateToken validates an access token.
 func ValidateToken(tokenString string) (*Claims, error) {
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, ErrWrongPurpose
+	}
+	return claims, nil
+}
+
+// ValidateChallengeToken validates a two-factor challenge token.
+func ValidateChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeTwoFactorChallenge {
+		return nil, ErrWrongPurpose
+	}
+	return claims, nil
+}
+
+// ValidateLinkState validates the OAuth state of a provider link.
+func ValidateLinkState(tokenString string) (*Claims, error) {
+	claims, err := parse(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeOAuthLink {
+		return nil, ErrWrongPurpose
+	}
+	return claims, nil
+}
+
+func parse(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtSecret, nil
 	})
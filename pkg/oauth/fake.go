@@ -0,0 +1,134 @@
+// pkg/oauth/fake.go
+package oauth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// FakeOIDCServer is a minimal local OIDC provider for development. It skips
+// consent entirely: GET /authorize?login_hint=<email> immediately redirects back
+// with a code for that email, which /token and /userinfo then honour.
+// Never enable it in production.
+type FakeOIDCServer struct {
+	mu     sync.Mutex
+	codes  map[string]UserInfo
+	tokens map[string]UserInfo
+}
+
+func NewFakeOIDCServer() *FakeOIDCServer {
+	return &FakeOIDCServer{codes: make(map[string]UserInfo), tokens: make(map[string]UserInfo)}
+}
+
+// Config returns an OIDCConfig pointing at the fake server mounted under baseURL.
+func (s *FakeOIDCServer) Config(name, baseURL, redirectURL, linkRedirectURL string) OIDCConfig {
+	return OIDCConfig{
+		Name:            name,
+		ClientID:        "fake-client",
+		ClientSecret:    "fake-secret",
+		RedirectURL:     redirectURL,
+		LinkRedirectURL: linkRedirectURL,
+		AuthURL:         baseURL + "/authorize",
+		TokenURL:        baseURL + "/token",
+		UserInfoURL:     baseURL + "/userinfo",
+	}
+}
+
+func (s *FakeOIDCServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/authorize":
+		s.authorize(w, r)
+	case "/token":
+		s.token(w, r)
+	case "/userinfo":
+		s.userInfo(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *FakeOIDCServer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	email := q.Get("login_hint")
+	redirectURI := q.Get("redirect_uri")
+	if email == "" || redirectURI == "" {
+		http.Error(w, "login_hint and redirect_uri are required", http.StatusBadRequest)
+		return
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	info := UserInfo{
+		Subject:       "fake-" + hex.EncodeToString(sum[:8]),
+		Email:         email,
+		EmailVerified: q.Get("email_verified") != "false",
+		Name:          strings.Split(email, "@")[0],
+	}
+
+	code := randomToken()
+	s.mu.Lock()
+	s.codes[code] = info
+	s.mu.Unlock()
+
+	target, err := url.Parse(redirectURI)
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := target.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	target.RawQuery = params.Encode()
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func (s *FakeOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	info, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	accessToken := randomToken()
+	if ok {
+		s.tokens[accessToken] = info
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"access_token": accessToken, "token_type": "Bearer"})
+}
+
+func (s *FakeOIDCServer) userInfo(w http.ResponseWriter, r *http.Request) {
+	accessToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	info, ok := s.tokens[accessToken]
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, `{"error":"invalid_token"}`, http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(info)
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,306 @@
+// Package search implements a small in-process full-text index with relevance
+// scoring, prefix and typo-tolerant matching, and highlighting.
+package search
+
+import (
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Highlight markers wrapped around matched words; the rest of the text is HTML-escaped.
+const (
+	HighlightPre  = "<em>"
+	HighlightPost = "</em>"
+)
+
+// Field is an indexed text field with its relevance weight.
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// Hit is a search result.
+type Hit struct {
+	ID         uint
+	Score      float64
+	Highlights map[string]string // Field name -> text with matched words highlighted
+}
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Match factors applied to a query term's score depending on how it matched.
+const (
+	exactMatch  = 1.0
+	prefixMatch = 0.7
+	fuzzyMatch  = 0.5
+)
+
+type document struct {
+	fields []Field
+	length int
+}
+
+// Index is an inverted index safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[uint]document
+	postings map[string]map[uint]float64 // term -> document -> weighted term frequency
+	totalLen int
+}
+
+// NewIndex creates an empty index.
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[uint]document),
+		postings: make(map[string]map[uint]float64),
+	}
+}
+
+// Add indexes a document, replacing any previous version with the same ID.
+func (idx *Index) Add(id uint, fields ...Field) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(id)
+	length := 0
+	for _, field := range fields {
+		for _, term := range Tokenize(field.Text) {
+			if idx.postings[term] == nil {
+				idx.postings[term] = make(map[uint]float64)
+			}
+			idx.postings[term][id] += field.Weight
+			length++
+		}
+	}
+	idx.docs[id] = document{fields: fields, length: length}
+	idx.totalLen += length
+}
+
+// Remove deletes a document from the index.
+func (idx *Index) Remove(id uint) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id uint) {
+	doc, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+	for _, field := range doc.fields {
+		for _, term := range Tokenize(field.Text) {
+			delete(idx.postings[term], id)
+			if len(idx.postings[term]) == 0 {
+				delete(idx.postings, term)
+			}
+		}
+	}
+	idx.totalLen -= doc.length
+	delete(idx.docs, id)
+}
+
+// Len returns the number of indexed documents.
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search returns up to limit documents matching the query, best first. Every query
+// term is matched exactly, as a prefix of an indexed word, or within a small edit
+// distance; documents matching more of the query terms rank higher.
+func (idx *Index) Search(query string, limit int) []Hit {
+	terms := Tokenize(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	scores := make(map[uint]float64)
+	matchedTerms := make(map[uint]int)
+	matchedWords := make(map[uint]map[string]bool)
+	total := float64(len(idx.docs))
+	avgLen := math.Max(float64(idx.totalLen)/math.Max(total, 1), 1)
+
+	for _, term := range terms {
+		termScores := make(map[uint]float64)
+		for word, postings := range idx.postings {
+			factor := matchFactor(term, word)
+			if factor == 0 {
+				continue
+			}
+			idf := math.Log(1 + (total-float64(len(postings))+0.5)/(float64(len(postings))+0.5))
+			for id, tf := range postings {
+				// BM25 term weight with field-weighted term frequency
+				score := factor * idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(idx.docs[id].length)/avgLen))
+				if score > termScores[id] {
+					termScores[id] = score
+				}
+				if matchedWords[id] == nil {
+					matchedWords[id] = make(map[string]bool)
+				}
+				matchedWords[id][word] = true
+			}
+		}
+		for id, score := range termScores {
+			scores[id] += score
+			matchedTerms[id]++
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		coverage := float64(matchedTerms[id]) / float64(len(terms))
+		hits = append(hits, Hit{ID: id, Score: score * coverage * coverage})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	for i := range hits {
+		words := matchedWords[hits[i].ID]
+		hits[i].Highlights = make(map[string]string)
+		for _, field := range idx.docs[hits[i].ID].fields {
+			if highlighted, ok := Highlight(field.Text, func(token string) bool { return words[token] }); ok {
+				hits[i].Highlights[field.Name] = highlighted
+			}
+		}
+	}
+	return hits
+}
+
+// matchFactor scores how a query term matches an indexed word, 0 when it doesn't.
+func matchFactor(term, word string) float64 {
+	switch {
+	case term == word:
+		return exactMatch
+	case len(term) >= 2 && strings.HasPrefix(word, term):
+		return prefixMatch
+	}
+
+	maxEdits := MaxEdits(term)
+	if maxEdits == 0 || abs(len(term)-len(word)) > maxEdits {
+		return 0
+	}
+	if Levenshtein(term, word) <= maxEdits {
+		return fuzzyMatch
+	}
+	return 0
+}
+
+// MaxEdits returns the typo tolerance for a term: none for short words,
+// one edit from 4 characters and two from 8.
+func MaxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// Levenshtein returns the edit distance between two strings, counting an
+// adjacent transposition as a single edit (optimal string alignment).
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+// Tokenize lowercases text and splits it into words of letters and digits.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+// Highlight HTML-escapes text and wraps the words accepted by match with the highlight
+// markers, so the result can be rendered as markup. It reports whether any word was highlighted.
+func Highlight(text string, match func(token string) bool) (string, bool) {
+	var b strings.Builder
+	found := false
+	start := 0
+	inWord := false
+	flush := func(end int) {
+		chunk := text[start:end]
+		if inWord && match(strings.ToLower(chunk)) {
+			b.WriteString(HighlightPre + html.EscapeString(chunk) + HighlightPost)
+			found = true
+		} else {
+			b.WriteString(html.EscapeString(chunk))
+		}
+		start = end
+	}
+
+	for i, r := range text {
+		if isWordRune(r) != inWord {
+			flush(i)
+			inWord = !inWord
+		}
+	}
+	flush(len(text))
+	return b.String(), found
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// Matcher returns a highlight matcher accepting words that match any query term
+// exactly, as a prefix, or within the typo tolerance.
+func Matcher(query string) func(token string) bool {
+	terms := Tokenize(query)
+	return func(token string) bool {
+		for _, term := range terms {
+			if matchFactor(term, token) > 0 {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
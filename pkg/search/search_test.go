@@ -0,0 +1,207 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"lowercases words", "Red Shoes", []string{"red", "shoes"}},
+		{"splits on punctuation", "t-shirt, size:XL!", []string{"t", "shirt", "size", "xl"}},
+		{"keeps digits", "iPhone 15 Pro", []string{"iphone", "15", "pro"}},
+		{"keeps letters outside ASCII", "Café Crème", []string{"café", "crème"}},
+		{"only separators", " -- ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.text)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize(%q) = %q; want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"shoe", "shoe", 0},
+		{"", "shoe", 4},
+		{"shoe", "shoes", 1},
+		{"shoe", "shoo", 1},
+		{"shoe", "hsoe", 1}, // Transposition
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	tests := []struct {
+		term string
+		want int
+	}{
+		{"tee", 0},
+		{"shoe", 1},
+		{"sneaker", 1},
+		{"keyboard", 2},
+		{"crème", 1},
+	}
+	for _, tt := range tests {
+		if got := MaxEdits(tt.term); got != tt.want {
+			t.Errorf("MaxEdits(%q) = %d; want %d", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	matchShoe := func(token string) bool { return token == "shoe" }
+	tests := []struct {
+		name      string
+		text      string
+		want      string
+		wantFound bool
+	}{
+		{"wraps matched words", "Red shoe", "Red <em>shoe</em>", true},
+		{"keeps original case", "SHOE rack", "<em>SHOE</em> rack", true},
+		{"wraps every match", "shoe, shoe", "<em>shoe</em>, <em>shoe</em>", true},
+		{"matches whole words only", "shoes", "shoes", false},
+		{"no match", "Blue hat", "Blue hat", false},
+		{"empty", "", "", false},
+		{"escapes the text between matches", `<script>alert("x")</script> shoe & sock`,
+			"&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; <em>shoe</em> &amp; sock", true},
+		{"escapes text without matches", "<b>hat</b>", "&lt;b&gt;hat&lt;/b&gt;", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := Highlight(tt.text, matchShoe)
+			if got != tt.want || found != tt.wantFound {
+				t.Fatalf("Highlight(%q) = %q, %v; want %q, %v", tt.text, got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	match := Matcher("sneakr tee")
+	tests := []struct {
+		token string
+		want  bool
+	}{
+		{"sneakr", true},    // Exact
+		{"sneakrs", true},   // Prefix
+		{"sneaker", true},   // One edit
+		{"sneakers", false}, // Two edits
+		{"tee", true},
+		{"tees", true},
+		{"toe", false}, // Short terms need an exact or prefix match
+		{"hat", false},
+	}
+	for _, tt := range tests {
+		if got := match(tt.token); got != tt.want {
+			t.Errorf("Matcher(%q)(%q) = %v; want %v", "sneakr tee", tt.token, got, tt.want)
+		}
+	}
+}
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Add(1, Field{Name: "name", Text: "Red running shoe", Weight: 3}, Field{Name: "description", Text: "Light shoe for road running", Weight: 1})
+	idx.Add(2, Field{Name: "name", Text: "Blue hat", Weight: 3}, Field{Name: "description", Text: "Wool hat, fits any shoe outfit", Weight: 1})
+	idx.Add(3, Field{Name: "name", Text: "Mechanical keyboard", Weight: 3}, Field{Name: "description", Text: "Tactile switches", Weight: 1})
+	idx.Add(4, Field{Name: "name", Text: "Red hat", Weight: 3}, Field{Name: "description", Text: "Cotton cap", Weight: 1})
+	return idx
+}
+
+func hitIDs(hits []Hit) []uint {
+	ids := make([]uint, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestIndexSearch(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		limit int
+		want  []uint
+	}{
+		{"empty query", "", 0, nil},
+		{"no match", "guitar", 0, nil},
+		{"weighted fields rank higher", "shoe", 0, []uint{1, 2}},
+		{"prefix match", "keyb", 0, []uint{3}},
+		{"typo match", "keybaord", 0, []uint{3}},
+		{"more matched terms rank higher", "red hat", 0, []uint{4, 2, 1}},
+		{"limit", "red hat", 2, []uint{4, 2}},
+		{"case insensitive", "HAT", 0, []uint{4, 2}},
+	}
+	idx := newTestIndex()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(idx.Search(tt.query, tt.limit))
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Search(%q, %d) = %v; want %v", tt.query, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSearchHighlights(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(1, Field{Name: "name", Text: "Keyboard <b>deal</b>", Weight: 3}, Field{Name: "description", Text: "No match here", Weight: 1})
+
+	hits := idx.Search("keybord", 0)
+	if len(hits) != 1 {
+		t.Fatalf("Search returned %d hits; want 1", len(hits))
+	}
+	want := map[string]string{"name": "<em>Keyboard</em> &lt;b&gt;deal&lt;/b&gt;"}
+	if !reflect.DeepEqual(hits[0].Highlights, want) {
+		t.Fatalf("Highlights = %q; want %q", hits[0].Highlights, want)
+	}
+}
+
+func TestIndexAddRemove(t *testing.T) {
+	idx := newTestIndex()
+	if got := idx.Len(); got != 4 {
+		t.Fatalf("Len() = %d; want 4", got)
+	}
+
+	// Re-adding replaces the document's terms
+	idx.Add(3, Field{Name: "name", Text: "Gaming mouse", Weight: 3})
+	if got := hitIDs(idx.Search("keyboard", 0)); len(got) != 0 {
+		t.Fatalf("Search(keyboard) after replace = %v; want none", got)
+	}
+	if got := hitIDs(idx.Search("mouse", 0)); !reflect.DeepEqual(got, []uint{3}) {
+		t.Fatalf("Search(mouse) = %v; want [3]", got)
+	}
+
+	idx.Remove(1)
+	idx.Remove(42)
+	if got := idx.Len(); got != 3 {
+		t.Fatalf("Len() after Remove = %d; want 3", got)
+	}
+	if got := hitIDs(idx.Search("running", 0)); len(got) != 0 {
+		t.Fatalf("Search(running) after Remove = %v; want none", got)
+	}
+}
@@ -0,0 +1,55 @@
+// pkg/slug/slug.go
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Make turns s into a lowercase URL slug of ASCII letters, digits and single hyphens.
+func Make(s string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+			hyphen = false
+		case !hyphen && b.Len() > 0:
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// IsNumeric reports whether s is made of digits only. Such slugs are not allowed,
+// as slugs and numeric IDs share the same URLs.
+func IsNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Unique returns base, or base with the lowest numeric suffix ("-2", "-3", ...)
+// for which exists reports false.
+func Unique(base string, exists func(candidate string) (bool, error)) (string, error) {
+	candidate := base
+	for i := 2; ; i++ {
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = base + "-" + strconv.Itoa(i)
+	}
+}
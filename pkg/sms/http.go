@@ -0,0 +1,54 @@
+package sms
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HTTPSender is an SMSSender that posts messages to an SMS gateway as JSON
+// ({"from", "to", "message"}) with the API key as a bearer token.
+type HTTPSender struct {
+	url    string
+	apiKey string
+	from   string
+	client *http.Client
+}
+
+func NewHTTPSender(url, apiKey, from string) *HTTPSender {
+	return &HTTPSender{
+		url:    url,
+		apiKey: apiKey,
+		from:   from,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *HTTPSender) Send(phone, message string) error {
+	body, err := json.Marshal(map[string]string{"from": s.from, "to": phone, "message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sms gateway returned status %d", resp.StatusCode)
+	}
+	return nil
+}
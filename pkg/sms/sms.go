@@ -0,0 +1,77 @@
+// pkg/sms/sms.go
+package sms
+
+import (
+	"log"
+	"sync"
+	"unicode"
+)
+
+// SMSSender delivers text messages to a phone number.
+type SMSSender interface {
+	Send(phone, message string) error
+}
+
+// Message is a message captured by FakeSender.
+type Message struct {
+	Phone   string
+	Message string
+}
+
+// maxFakeMessages bounds the messages kept by FakeSender; older ones are dropped.
+const maxFakeMessages = 100
+
+// FakeSender is an SMSSender that keeps the latest messages in memory instead of sending them.
+// It is meant for local development and tests; the log only shows messages with their digits
+// masked, so codes don't end up in log files.
+type FakeSender struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+func (s *FakeSender) Send(phone, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.messages) == maxFakeMessages {
+		s.messages = append(s.messages[:0], s.messages[1:]...)
+	}
+	s.messages = append(s.messages, Message{Phone: phone, Message: message})
+	log.Printf("[sms] to %s: %s", maskDigits(phone, 4), maskDigits(message, 0))
+	return nil
+}
+
+// maskDigits replaces the digits of s with '*', keeping the last keep digits.
+func maskDigits(s string, keep int) string {
+	digits := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	masked := []rune(s)
+	for i, r := range masked {
+		if !unicode.IsDigit(r) {
+			continue
+		}
+		if digits > keep {
+			masked[i] = '*'
+		}
+		digits--
+	}
+	return string(masked)
+}
+
+// Messages returns a copy of the latest messages sent.
+func (s *FakeSender) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]Message, len(s.messages))
+	copy(out, s.messages)
+	return out
+}
@@ -0,0 +1,125 @@
+// pkg/spreadsheet/spreadsheet.go
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+// ContentTypes maps the supported formats to their MIME type.
+var ContentTypes = map[string]string{
+	FormatCSV:  "text/csv",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// FormatOf returns the format of a file from its extension.
+func FormatOf(fileName string) (string, error) {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(fileName)), ".")
+	if _, ok := ContentTypes[format]; !ok {
+		return "", errors.New("unsupported file type, use .csv or .xlsx")
+	}
+	return format, nil
+}
+
+// Read parses the rows of a CSV file or of the first sheet of an XLSX workbook.
+// Cells are returned as text; trailing empty rows are dropped.
+func Read(format string, data []byte) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(data)
+	case FormatXLSX:
+		rows, err = readXLSX(data)
+	default:
+		return nil, errors.New("unsupported file format: " + format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 && IsBlankRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, nil
+}
+
+// Write writes the rows as a CSV file or as a single-sheet XLSX workbook.
+func Write(format string, w io.Writer, rows [][]string) error {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		for _, row := range rows {
+			escaped := make([]string, len(row))
+			for i, cell := range row {
+				escaped[i] = escapeFormula(cell)
+			}
+			if err := cw.Write(escaped); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case FormatXLSX:
+		return writeXLSX(w, rows)
+	}
+	return errors.New("unsupported file format: " + format)
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	// Spreadsheet programs often prefix UTF-8 CSV exports with a byte order mark
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = unescapeFormula(cell)
+		}
+	}
+	return rows, nil
+}
+
+// formulaPrefixes are the first characters that make spreadsheet programs evaluate a cell as a formula.
+const formulaPrefixes = "=+-@"
+
+// escapeFormula prefixes a CSV cell that would be evaluated as a formula with an apostrophe,
+// so spreadsheet programs show it as text. XLSX cells are written as text already.
+func escapeFormula(cell string) string {
+	if cell != "" && strings.IndexByte(formulaPrefixes, cell[0]) >= 0 {
+		return "'" + cell
+	}
+	return cell
+}
+
+// unescapeFormula removes the apostrophe added by escapeFormula, so exported files import unchanged.
+func unescapeFormula(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.IndexByte(formulaPrefixes, cell[1]) >= 0 {
+		return cell[1:]
+	}
+	return cell
+}
+
+// IsBlankRow reports whether all cells of the row are empty or whitespace.
+func IsBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,260 @@
+// pkg/spreadsheet/xlsx.go
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Only the parts of the Office Open XML format needed for plain cell values are supported:
+// shared and inline strings, numbers and booleans of the first worksheet. Styles, formulas
+// (their cached value is used) and dates (read as serial numbers) are not interpreted.
+
+const maxXLSXPartSize = 64 << 20 // Guards against zip bombs
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"id,attr"` // r:id, the relationship to the worksheet part
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxRichText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxRichText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxRichText `xml:"si"`
+}
+
+type xlsxWorksheet struct {
+	Rows []struct {
+		Index int `xml:"r,attr"` // 1-based; rows without cells may be left out
+		Cells []struct {
+			Ref    string       `xml:"r,attr"`
+			Type   string       `xml:"t,attr"`
+			Value  string       `xml:"v"`
+			Inline xlsxRichText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid xlsx file")
+	}
+	parts := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		parts[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(parts)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := parts["xl/sharedStrings.xml"]; ok {
+		if err := decodePart(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := parts[sheetPath]
+	if !ok {
+		return nil, errors.New("invalid xlsx file: worksheet not found")
+	}
+	var sheet xlsxWorksheet
+	if err := decodePart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	for _, r := range sheet.Rows {
+		for r.Index > len(rows)+1 {
+			rows = append(rows, []string{})
+		}
+		var row []string
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				col = columnIndex(c.Ref)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+
+			switch c.Type {
+			case "s":
+				var idx int
+				if _, err := fmt.Sscan(c.Value, &idx); err != nil || idx < 0 || idx >= len(shared.Items) {
+					return nil, errors.New("invalid xlsx file: bad shared string reference")
+				}
+				row[col] = shared.Items[idx].String()
+			case "inlineStr":
+				row[col] = c.Inline.String()
+			case "b":
+				row[col] = map[string]string{"1": "true", "0": "false"}[c.Value]
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// firstSheetPath resolves the part name of the first worksheet through the workbook relationships.
+func firstSheetPath(parts map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var workbook xlsxWorkbook
+	f, ok := parts["xl/workbook.xml"]
+	if !ok {
+		return "", errors.New("invalid xlsx file: workbook not found")
+	}
+	if err := decodePart(f, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("invalid xlsx file: workbook has no sheets")
+	}
+
+	var rels xlsxRelationships
+	f, ok = parts["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+	if err := decodePart(f, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].ID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return fallback, nil
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return errors.New("invalid xlsx file: " + f.Name + ": " + err.Error())
+	}
+	return nil
+}
+
+// columnIndex converts the column letters of a cell reference ("C12") to a zero-based index.
+func columnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+	}
+	return col - 1
+}
+
+// columnName converts a zero-based column index to its letters (0 -> "A", 27 -> "AB").
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+// writeXLSX writes a minimal workbook with one sheet; all cells are written as inline strings,
+// so values like SKUs with leading zeros survive a round trip.
+func writeXLSX(w io.Writer, rows [][]string) error {
+	zw := zip.NewWriter(w)
+	static := []struct{ name, content string }{
+		{"[Content_Types].xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets>` +
+			`</workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` +
+			`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`</Relationships>`},
+	}
+	for _, part := range static {
+		pw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(pw, part.content); err != nil {
+			return err
+		}
+	}
+
+	pw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>`)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&b, `<row r="%d">`, i+1)
+		for j, cell := range row {
+			fmt.Fprintf(&b, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			if err := xml.EscapeText(&b, []byte(cell)); err != nil {
+				return err
+			}
+			b.WriteString(`</t></is></c>`)
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	if _, err := pw.Write(b.Bytes()); err != nil {
+		return err
+	}
+	return zw.Close()
+}